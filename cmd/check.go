@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -13,20 +15,70 @@ import (
 
 // checkOptions encapsulates all the flags for the check command
 type checkOptions struct {
-	Verbose bool
-	Quiet   bool
-	Workers int
+	Verbose         bool
+	Quiet           bool
+	ProgressJSON    bool
+	JSON            bool
+	ReportExtra     bool
+	Raw             bool
+	AllowIncomplete bool
+	Locate          bool
+	DedupHardlinks  bool
+	OnlyMissing     bool
+	Workers         int
+	ReadRetries     int
+	DownloadDir     string
+	Storage         string
+	BatchDir        string
+	RelativeTo      string
+	ContentPaths    []string
+	Timeout         time.Duration
 }
 
 var checkOpts checkOptions
 
 var checkCmd = &cobra.Command{
-	Use:   "check <torrent-file> <content-path>",
+	Use:   "check <torrent-file> [content-path]",
 	Short: "Verify the integrity of content against a torrent file",
 	Long: `Checks if the data in the specified content path (file or directory) matches
 the pieces defined in the torrent file. This is useful for verifying downloads
-or checking data integrity after moving files.`,
-	Args:                       cobra.ExactArgs(2),
+or checking data integrity after moving files.
+
+Exits with 0 when the content is complete, 2 when pieces are bad or files are
+missing, and 1 when the check could not be run. With --allow-incomplete an
+incomplete result exits with 0.
+
+If no content path is given, the content is looked up by the torrent's name next
+to the torrent file, or in --download-dir. A torrent-file of - reads the torrent
+from stdin. Content spread over several disks can be checked by giving more roots
+with --content-path; each file is taken from the first root that has it.
+
+With --batch, every .torrent file in a directory is checked against its content in
+--download-dir and a summary table is printed.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if checkOpts.BatchDir != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot specify both torrent arguments and --batch flag")
+			}
+			if checkOpts.ProgressJSON {
+				return fmt.Errorf("--progress-json is not supported with --batch")
+			}
+			if checkOpts.Raw {
+				return fmt.Errorf("--raw is not supported with --batch")
+			}
+			if checkOpts.Locate {
+				return fmt.Errorf("--locate is not supported with --batch")
+			}
+			if checkOpts.OnlyMissing {
+				return fmt.Errorf("--only-missing is not supported with --batch")
+			}
+			if len(checkOpts.ContentPaths) > 0 {
+				return fmt.Errorf("--content-path is not supported with --batch")
+			}
+			return nil
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE:                       runCheck,
 	DisableFlagsInUseLine:      true,
 	SuggestionsMinimumDistance: 1,
@@ -37,44 +89,168 @@ func init() {
 	checkCmd.Flags().SortFlags = false
 	checkCmd.Flags().BoolVarP(&checkOpts.Verbose, "verbose", "v", false, "show list of bad piece indices")
 	checkCmd.Flags().BoolVarP(&checkOpts.Quiet, "quiet", "q", false, "reduced output mode (prints only completion percentage)")
+	checkCmd.Flags().BoolVar(&checkOpts.JSON, "json", false, "print the verification result as JSON")
+	checkCmd.Flags().BoolVar(&checkOpts.ProgressJSON, "progress-json", false, "write verification progress to stderr as JSON lines (replaces the progress bar)")
+	checkCmd.Flags().BoolVar(&checkOpts.ReportExtra, "report-extra", false, "list files in the content directory that are not part of the torrent")
+	checkCmd.Flags().BoolVar(&checkOpts.Raw, "raw", false, "treat the content path as one file of concatenated data and check it against the pieces, ignoring file boundaries")
+	checkCmd.Flags().BoolVar(&checkOpts.Locate, "locate", false, "re-read the first bad piece and show the file byte ranges it covers, flagging ranges that are all zeros")
+	checkCmd.Flags().BoolVar(&checkOpts.DedupHardlinks, "dedup-hardlinks", false, "read data shared by hardlinked files once (Linux/macOS/BSD; pieces only line up reliably in padded torrents)")
+	checkCmd.Flags().BoolVar(&checkOpts.OnlyMissing, "only-missing", false, "print only the missing files and files with bad pieces, one per line (a JSON array with --json)")
+	checkCmd.Flags().StringVar(&checkOpts.RelativeTo, "relative-to", "", "show file paths relative to this directory, e.g. to share a report without revealing where the data lives")
+	checkCmd.Flags().BoolVar(&checkOpts.AllowIncomplete, "allow-incomplete", false, "exit with 0 even if pieces are bad or files are missing")
 	checkCmd.Flags().IntVar(&checkOpts.Workers, "workers", 0, "number of worker goroutines for verification (0 for automatic)")
+	checkCmd.Flags().StringVar(&checkOpts.Storage, "storage", string(torrent.StorageAuto), "storage the content is on, tunes workers and read size (auto, ssd, hdd, network)")
+	checkCmd.Flags().IntVar(&checkOpts.ReadRetries, "read-retries", torrent.DefaultReadRetries, "retry failed reads this many times with backoff before marking pieces bad")
+	checkCmd.Flags().StringArrayVar(&checkOpts.ContentPaths, "content-path", nil, "further content root to search for the torrent's files, in order (can be specified multiple times)")
+	checkCmd.Flags().StringVar(&checkOpts.DownloadDir, "download-dir", "", "directory to look up the content in by torrent name when no content path is given")
+	checkCmd.Flags().StringVarP(&checkOpts.BatchDir, "batch", "b", "", "check every .torrent file in this directory against its content in --download-dir")
+	checkCmd.Flags().DurationVar(&checkOpts.Timeout, "timeout", torrent.DefaultRemoteTimeout, "timeout for fetching the torrent when given as an http(s) URL")
 	checkCmd.SetUsageTemplate(`Usage:
-  {{.CommandPath}} <torrent-file> <content-path> [flags]
+  {{.CommandPath}} <torrent-file> [content-path] [flags]
+  {{.CommandPath}} --batch <torrent-dir> [--download-dir <dir>] [flags]
 
 Arguments:
-  torrent-file   Path to the .torrent file
+  torrent-file   Path or http(s) URL to the .torrent file, or - for stdin
   content-path   Path to the directory or file containing the data
+                 (default: the torrent's name next to the torrent file or in --download-dir,
+                 or the first --content-path)
 
 Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
 `)
 }
 
-// validateCheckArgs validates the command arguments and returns the paths
-func validateCheckArgs(args []string) (torrentPath string, contentPath string, err error) {
+// validateCheckArgs validates the command arguments and returns the torrent path and
+// the content roots to search, the first being the content path. remote is the
+// already fetched torrent when the path is a URL, and nil otherwise.
+func validateCheckArgs(args []string, opts checkOptions, remote *torrent.Torrent) (torrentPath string, contentPaths []string, err error) {
 	torrentPath = args[0]
-	contentPath = args[1]
 
-	if _, err := os.Stat(torrentPath); err != nil {
-		return "", "", fmt.Errorf("invalid torrent file path %q: %w", torrentPath, err)
+	// remote and piped torrents are loaded and validated when verifying
+	if !torrent.IsRemoteTorrent(torrentPath) && !torrent.IsStdinTorrent(torrentPath) {
+		if _, err := os.Stat(torrentPath); err != nil {
+			return "", nil, fmt.Errorf("invalid torrent file path %q: %w", torrentPath, err)
+		}
 	}
 
-	if _, err := os.Stat(contentPath); err != nil {
-		return "", "", fmt.Errorf("invalid content path %q: %w", contentPath, err)
+	if len(args) > 1 || len(opts.ContentPaths) > 0 {
+		if opts.DownloadDir != "" {
+			return "", nil, fmt.Errorf("cannot use both a content path and --download-dir")
+		}
+		contentPaths = slices.Concat(args[1:], opts.ContentPaths)
+	} else {
+		var contentPath string
+		if remote != nil {
+			contentPath, err = torrent.DefaultContentPathFor(remote, torrentPath, opts.DownloadDir)
+		} else {
+			contentPath, err = torrent.DefaultContentPath(torrentPath, opts.DownloadDir, opts.Timeout)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		contentPaths = []string{contentPath}
+	}
+
+	if opts.Raw && len(contentPaths) > 1 {
+		return "", nil, fmt.Errorf("--raw checks a single content path")
+	}
+	for _, contentPath := range contentPaths {
+		if _, err := os.Stat(contentPath); err != nil {
+			return "", nil, fmt.Errorf("invalid content path %q: %w", contentPath, err)
+		}
 	}
 
-	return torrentPath, contentPath, nil
+	return torrentPath, contentPaths, nil
 }
 
 // buildVerifyOptions creates the verification options from the command flags
-func buildVerifyOptions(opts checkOptions, torrentPath, contentPath string) torrent.VerifyOptions {
-	return torrent.VerifyOptions{
-		TorrentPath: torrentPath,
-		ContentPath: contentPath,
-		Verbose:     opts.Verbose,
-		Quiet:       opts.Quiet,
-		Workers:     opts.Workers,
+func buildVerifyOptions(opts checkOptions, torrentPath string, contentPaths []string) (torrent.VerifyOptions, error) {
+	storage, err := torrent.ParseStorageType(opts.Storage)
+	if err != nil {
+		return torrent.VerifyOptions{}, err
+	}
+
+	verifyOpts := torrent.VerifyOptions{
+		TorrentPath:       torrentPath,
+		ContentPath:       contentPaths[0],
+		ExtraContentPaths: contentPaths[1:],
+		Verbose:           opts.Verbose,
+		Quiet:             opts.Quiet || opts.JSON || opts.OnlyMissing,
+		Workers:           opts.Workers,
+		ReadRetries:       opts.ReadRetries,
+		Timeout:           opts.Timeout,
+		ReportExtra:       opts.ReportExtra,
+		Storage:           storage,
+		Raw:               opts.Raw,
+		Locate:            opts.Locate,
+		DedupHardlinks:    opts.DedupHardlinks,
+		RelativeTo:        opts.RelativeTo,
+	}
+
+	if opts.ProgressJSON {
+		verifyOpts.ProgressCallback = torrent.NewJSONProgressCallback(os.Stderr)
+	}
+
+	return verifyOpts, nil
+}
+
+// checkJSONResult is the --json output for one checked torrent
+type checkJSONResult struct {
+	Torrent string `json:"torrent"`
+	Content string `json:"content,omitempty"`
+	Error   string `json:"error,omitempty"`
+	*torrent.VerificationResult
+}
+
+func newCheckJSONResult(torrentPath, contentPath string, result *torrent.VerificationResult, err error) checkJSONResult {
+	out := checkJSONResult{Torrent: torrentPath, Content: contentPath, VerificationResult: result}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	if result != nil {
+		// encode empty lists as [] rather than null
+		if result.BadPieceIndices == nil {
+			result.BadPieceIndices = []int{}
+		}
+		if result.MissingFiles == nil {
+			result.MissingFiles = []string{}
+		}
+		if result.BadFiles == nil {
+			result.BadFiles = []string{}
+		}
+		if result.ExtraFiles == nil {
+			result.ExtraFiles = []string{}
+		}
+		if result.ReadErrors == nil {
+			result.ReadErrors = []string{}
+		}
+	}
+	return out
+}
+
+// printRepairList prints the files that need to be fetched again for --only-missing:
+// missing files, then files with bad pieces, each once
+func printRepairList(result *torrent.VerificationResult, asJSON bool) error {
+	files := []string{}
+	seen := make(map[string]bool)
+	for _, file := range slices.Concat(result.MissingFiles, result.BadFiles) {
+		file = strings.TrimSuffix(file, " (size mismatch)")
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
 	}
+
+	if asJSON {
+		return writeJSON(os.Stdout, files)
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return nil
 }
 
 // displayCheckResults handles the display of verification results
@@ -88,35 +264,153 @@ func displayCheckResults(display *torrent.Display, result *torrent.VerificationR
 	}
 }
 
+// runBatchCheck verifies all torrents in the batch directory and prints a summary
+func runBatchCheck(opts checkOptions) error {
+	start := time.Now()
+
+	storage, err := torrent.ParseStorageType(opts.Storage)
+	if err != nil {
+		return err
+	}
+
+	verifyOpts := torrent.VerifyOptions{
+		Verbose:        opts.Verbose,
+		Workers:        opts.Workers,
+		ReadRetries:    opts.ReadRetries,
+		Timeout:        opts.Timeout,
+		ReportExtra:    opts.ReportExtra,
+		Storage:        storage,
+		DedupHardlinks: opts.DedupHardlinks,
+		RelativeTo:     opts.RelativeTo,
+	}
+	results, err := torrent.VerifyBatch(opts.BatchDir, opts.DownloadDir, verifyOpts)
+	if err != nil {
+		return fmt.Errorf("batch verification failed: %w", err)
+	}
+
+	incomplete, failed := 0, 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		} else if !result.Complete() {
+			incomplete++
+		}
+	}
+
+	if opts.JSON {
+		out := make([]checkJSONResult, 0, len(results))
+		for _, result := range results {
+			out = append(out, newCheckJSONResult(result.TorrentPath, result.ContentPath, result.Result, result.Error))
+		}
+		if err := writeJSON(os.Stdout, out); err != nil {
+			return err
+		}
+	} else if opts.Quiet {
+		for _, result := range results {
+			if result.Error != nil {
+				fmt.Printf("error\t%s\n", result.Name)
+			} else {
+				fmt.Printf("%.2f%%\t%s\n", result.Result.Completion, result.Name)
+			}
+		}
+	} else {
+		display := torrent.NewDisplay(torrent.NewFormatter(opts.Verbose))
+		display.ShowBatchVerificationResults(results, time.Since(start))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d torrents could not be checked", failed, len(results))
+	}
+	if incomplete > 0 && !opts.AllowIncomplete {
+		return &exitCodeError{
+			err:  fmt.Errorf("%d of %d torrents are incomplete", incomplete, len(results)),
+			code: ExitIncomplete,
+		}
+	}
+	return nil
+}
+
 func runCheck(cmd *cobra.Command, args []string) error {
-	torrentPath, contentPath, err := validateCheckArgs(args)
+	if checkOpts.BatchDir != "" {
+		return runBatchCheck(checkOpts)
+	}
+
+	ctx, stop := notifyInterrupt(cmd.Context())
+	defer stop()
+
+	// a remote torrent is fetched once, for both the content path and verifying
+	var remote *torrent.Torrent
+	if torrent.IsRemoteTorrent(args[0]) {
+		t, _, err := torrent.LoadFromURLContext(ctx, args[0], checkOpts.Timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return &exitCodeError{err: torrent.ErrCancelled, code: ExitCancelled}
+			}
+			return err
+		}
+		remote = t
+	}
+
+	torrentPath, contentPaths, err := validateCheckArgs(args, checkOpts, remote)
 	if err != nil {
 		return err
 	}
 
-	start := time.Now()
+	verifyOpts, err := buildVerifyOptions(checkOpts, torrentPath, contentPaths)
+	if err != nil {
+		return err
+	}
+	if remote != nil {
+		verifyOpts.MetaInfo = remote.MetaInfo
+	}
 
-	verifyOpts := buildVerifyOptions(checkOpts, torrentPath, contentPath)
+	start := time.Now()
 	display := torrent.NewDisplay(torrent.NewFormatter(checkOpts.Verbose))
 
-	if !checkOpts.Quiet {
+	// paths shown in the report; the real ones are still used for reading
+	shownRoots := make([]string, 0, len(contentPaths))
+	for _, contentPath := range contentPaths {
+		shownRoots = append(shownRoots, torrent.RelativePath(checkOpts.RelativeTo, contentPath))
+	}
+	shownTorrent, shownContent := torrentPath, strings.Join(shownRoots, ", ")
+	if torrent.IsRemoteTorrent(torrentPath) {
+		shownTorrent = torrent.RedactTrackerURL(torrentPath)
+	} else if !torrent.IsStdinTorrent(torrentPath) {
+		shownTorrent = torrent.RelativePath(checkOpts.RelativeTo, torrentPath)
+	}
+
+	if !checkOpts.Quiet && !checkOpts.JSON && !checkOpts.OnlyMissing {
 		green := color.New(color.FgGreen).SprintFunc()
 		cyan := color.New(color.FgCyan).SprintFunc()
 		fmt.Fprintf(os.Stdout, "\n%s\n", green("Verifying:"))
-		fmt.Fprintf(os.Stdout, "  Torrent file: %s\n", cyan(torrentPath))
-		fmt.Fprintf(os.Stdout, "  Content: %s\n", cyan(contentPath))
+		fmt.Fprintf(os.Stdout, "  Torrent file: %s\n", cyan(shownTorrent))
+		fmt.Fprintf(os.Stdout, "  Content: %s\n", cyan(shownContent))
 	}
 
-	result, err := torrent.VerifyData(verifyOpts)
+	result, err := torrent.VerifyDataContext(ctx, verifyOpts)
 	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr) // end the interrupted progress line
+			return &exitCodeError{err: torrent.ErrCancelled, code: ExitCancelled}
+		}
 		return fmt.Errorf("verification failed: %w", err)
 	}
 
 	duration := time.Since(start)
-	displayCheckResults(display, result, duration, checkOpts)
+	if checkOpts.OnlyMissing {
+		if err := printRepairList(result, checkOpts.JSON); err != nil {
+			return err
+		}
+	} else if checkOpts.JSON {
+		if err := writeJSON(os.Stdout, newCheckJSONResult(shownTorrent, shownContent, result, nil)); err != nil {
+			return err
+		}
+	} else {
+		displayCheckResults(display, result, duration, checkOpts)
+	}
 
-	if result.BadPieces > 0 || len(result.MissingFiles) > 0 {
-		return fmt.Errorf("verification failed or incomplete")
+	if (result.BadPieces > 0 || len(result.MissingFiles) > 0) && !checkOpts.AllowIncomplete {
+		return &exitCodeError{err: fmt.Errorf("verification failed or incomplete"), code: ExitIncomplete}
 	}
 
 	return nil
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/autobrr/mkbrr/internal/preset"
+	"github.com/autobrr/mkbrr/internal/trackers"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a shell completion script for mkbrr.
+
+Preset names (-P) and tracker URLs (-t, -T) are completed from your preset file
+and the list of trackers mkbrr has rules for.
+
+  bash:       source <(mkbrr completion bash)
+  zsh:        mkbrr completion zsh > "${fpath[1]}/_mkbrr"
+  fish:       mkbrr completion fish > ~/.config/fish/completions/mkbrr.fish
+  powershell: mkbrr completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE:                  runCompletion,
+	DisableFlagsInUseLine: true,
+}
+
+func init() {
+	completionCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} [bash|zsh|fish|powershell]
+`)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(out, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(out)
+	case "fish":
+		return rootCmd.GenFishCompletion(out, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(out)
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// registerCompletions wires custom flag completions once all command flags are defined
+func registerCompletions() error {
+	if err := rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions([]string{"auto", "always", "never"}, cobra.ShellCompDirectiveNoFileComp)); err != nil {
+		return err
+	}
+	for _, c := range []*cobra.Command{createCmd, modifyCmd} {
+		if err := c.RegisterFlagCompletionFunc("preset", completePresetNames); err != nil {
+			return err
+		}
+		if err := c.RegisterFlagCompletionFunc("tracker", completeTrackerURLs); err != nil {
+			return err
+		}
+	}
+	if err := createCmd.RegisterFlagCompletionFunc("piece-length", cobra.FixedCompletions([]string{pieceLengthAutoMax}, cobra.ShellCompDirectiveNoFileComp)); err != nil {
+		return err
+	}
+	if err := createCmd.RegisterFlagCompletionFunc("normalize-unicode", cobra.FixedCompletions([]string{"nfc", "nfd", "none"}, cobra.ShellCompDirectiveNoFileComp)); err != nil {
+		return err
+	}
+	return inspectCmd.RegisterFlagCompletionFunc("validate-tracker", completeTrackerURLs)
+}
+
+// loadCompletionPresets loads the preset file named by --preset-file, or the default one
+func loadCompletionPresets(cmd *cobra.Command) (*preset.Config, error) {
+	presetFile, _ := cmd.Flags().GetString("preset-file")
+	presetPath, err := preset.FindPresetFile(presetFile)
+	if err != nil {
+		return nil, err
+	}
+	return preset.Load(presetPath)
+}
+
+// completePresetNames completes preset names from the preset file
+func completePresetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := loadCompletionPresets(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(config.Presets))
+	for name := range config.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTrackerURLs completes tracker URLs used in the preset file,
+// followed by the hosts of trackers with known rules
+func completeTrackerURLs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	if config, err := loadCompletionPresets(cmd); err == nil {
+		if config.Default != nil {
+			for _, url := range config.Default.Trackers {
+				add(url)
+			}
+		}
+
+		names := make([]string, 0, len(config.Presets))
+		for name := range config.Presets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, url := range config.Presets[name].Trackers {
+				add(url)
+			}
+		}
+	}
+
+	for _, host := range trackers.KnownTrackers() {
+		add("https://" + host + "/")
+	}
+
+	return urls, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
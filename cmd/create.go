@@ -1,47 +1,102 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"slices"
+	"strconv"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/autobrr/mkbrr/internal/clipboard"
 	"github.com/autobrr/mkbrr/internal/preset"
 	"github.com/autobrr/mkbrr/internal/trackers"
 	"github.com/autobrr/mkbrr/torrent"
 )
 
+// pieceLengthAutoMax is the --piece-length value for PieceLengthAutoMax
+const pieceLengthAutoMax = "auto-max"
+
 // createOptions encapsulates all command-line flag values for the create command
 type createOptions struct {
 	pieceLengthExp      *uint
+	pieceLengthAutoMax  bool
+	minPieceLengthExp   *uint
 	maxPieceLengthExp   *uint
 	targetPieceCount    *uint
 	trackers            []string
 	comment             string
 	name                string
 	outputPath          string
+	outputPattern       string
 	outputDir           string
 	source              string
+	sourceMap           map[string]string
 	batchFile           string
 	presetName          string
 	presetFile          string
+	announceListFile    string
+	trackersFrom        string
+	expectedEpisodes    string
+	ioMode              string
+	normalizeUnicode    string
+	storage             string
+	copyTarget          string
+	symlinkTo           string
+	execCommand         string
+	maxMemory           string
 	webSeeds            []string
+	nodes               []string
+	meta                []string
+	infoMeta            []string
+	addSources          []string
 	excludePatterns     []string
 	includePatterns     []string
+	excludeExtensions   []string
+	includeExtensions   []string
+	excludeRegex        []string
+	includeRegex        []string
 	createWorkers       int
+	pathDepth           int
+	readRetries         int
 	isPrivate           bool
 	noDate              bool
+	setDate             string
 	noCreator           bool
+	createdBy           string
+	anonymous           bool
 	verbose             bool
 	entropy             bool
+	padded              bool
+	dedupHardlinks      bool
+	derefJunctions      bool
+	checkTrackers       bool
+	trackerTimeout      time.Duration
+	legacyUTF8          bool
 	quiet               bool
 	infoOnly            bool
 	skipPrefix          bool
+	outputPrefix        string
+	outputSuffix        string
+	force               bool
 	failOnSeasonWarning bool
+	warnDuplicates      bool
+	failOnEmptyDirs     bool
+	verifyStable        bool
+	verifyAfterCreate   bool
+	waitStable          time.Duration
+	fromStdin           bool
+	reportFile          string
+	stopOnError         bool
+	progressJSON        bool
+	strict              bool
 }
 
 var options = createOptions{
@@ -59,7 +114,23 @@ When a single tracker URL is provided, the output filename will use the tracker
 		if len(args) > 1 {
 			return fmt.Errorf("accepts at most one arg")
 		}
-		if len(args) == 0 && options.batchFile == "" {
+		if options.fromStdin {
+			if len(args) > 0 || options.batchFile != "" || len(options.addSources) > 0 {
+				return fmt.Errorf("cannot combine --from-stdin with a path argument, --add or --batch flag")
+			}
+			return nil
+		}
+		if len(options.addSources) > 0 {
+			if len(args) > 0 || options.batchFile != "" {
+				return fmt.Errorf("cannot combine --add with a path argument or --batch flag")
+			}
+			if options.name == "" {
+				return fmt.Errorf("--add requires --name, the torrent has no single root to take it from")
+			}
+			if options.pathDepth != 0 {
+				return fmt.Errorf("cannot combine --add with --path-depth; set the path in the torrent with path=name instead")
+			}
+		} else if len(args) == 0 && options.batchFile == "" {
 			presetFlag := cmd.Flags().Lookup("preset")
 			if presetFlag != nil && presetFlag.Changed {
 				return fmt.Errorf("when using a preset (-P/--preset), you must provide a path to the content")
@@ -69,6 +140,43 @@ When a single tracker URL is provided, the output filename will use the tracker
 		if len(args) == 1 && options.batchFile != "" {
 			return fmt.Errorf("cannot specify both path argument and --batch flag")
 		}
+		if options.progressJSON && options.batchFile != "" {
+			return fmt.Errorf("--progress-json is not supported with --batch")
+		}
+		if options.copyTarget != "" && (options.batchFile != "" || options.fromStdin) {
+			return fmt.Errorf("--copy can only be used when creating a single torrent")
+		}
+		if options.execCommand != "" {
+			if options.batchFile != "" {
+				return fmt.Errorf("--exec is not supported with --batch; set exec per job in the batch config")
+			}
+			if err := torrent.ValidateExec(options.execCommand); err != nil {
+				return err
+			}
+		}
+		if options.verifyAfterCreate {
+			if options.batchFile != "" {
+				return fmt.Errorf("--verify-after-create is not supported with --batch")
+			}
+			if len(options.addSources) > 0 || options.pathDepth < 0 {
+				return fmt.Errorf("--verify-after-create can't be used with --add or a negative --path-depth, the torrent's layout differs from the content on disk")
+			}
+		}
+		if options.force && options.batchFile != "" {
+			return fmt.Errorf("--force is not supported with --batch; set force: true per job in the batch config")
+		}
+		if options.symlinkTo != "" && options.batchFile != "" {
+			return fmt.Errorf("--symlink-to is not supported with --batch")
+		}
+		if options.checkTrackers && options.batchFile != "" {
+			return fmt.Errorf("--check-trackers is not supported with --batch")
+		}
+		if options.reportFile != "" && options.batchFile == "" && !options.fromStdin {
+			return fmt.Errorf("--report can only be used with --batch or --from-stdin")
+		}
+		if options.stopOnError && options.batchFile == "" {
+			return fmt.Errorf("--stop-on-error can only be used with --batch")
+		}
 		return nil
 	},
 	RunE:                       runCreate,
@@ -80,21 +188,45 @@ When a single tracker URL is provided, the output filename will use the tracker
 func init() {
 	createCmd.Flags().SortFlags = false
 	createCmd.Flags().StringVarP(&options.batchFile, "batch", "b", "", "batch config file (YAML)")
+	createCmd.Flags().BoolVar(&options.stopOnError, "stop-on-error", false, "with --batch, skip the remaining jobs once a job fails")
+	createCmd.Flags().BoolVar(&options.fromStdin, "from-stdin", false, "read newline-delimited content paths from stdin and create one torrent per path")
+	createCmd.Flags().StringVar(&options.reportFile, "report", "", "write the outcome of each --batch or --from-stdin job to this file (JSON, or CSV for a .csv name)")
 
 	createCmd.Flags().StringVarP(&options.presetName, "preset", "P", "", "use preset from config")
 	createCmd.Flags().StringVar(&options.presetFile, "preset-file", "", "preset config file (default ~/.config/mkbrr/presets.yaml)")
 	createCmd.Flags().StringArrayVarP(&options.trackers, "tracker", "t", nil, "tracker URLs (can be specified multiple times)")
+	createCmd.Flags().StringVar(&options.announceListFile, "announce-list-file", "", "file of announce URLs, one per line (blank line starts a new tier, # for comments)")
+	createCmd.Flags().StringVar(&options.trackersFrom, "trackers-from", "", "copy the trackers of another .torrent file, keeping its tiers")
+	createCmd.Flags().BoolVar(&options.checkTrackers, "check-trackers", false, "check that the trackers are reachable while hashing; failures only warn")
+	createCmd.Flags().DurationVar(&options.trackerTimeout, "tracker-timeout", torrent.DefaultTrackerCheckTimeout, "how long --check-trackers waits for each tracker")
 	createCmd.Flags().StringArrayVarP(&options.webSeeds, "web-seed", "w", nil, "add web seed URLs")
+	createCmd.Flags().StringArrayVar(&options.meta, "meta", nil, "add a custom key=value string to the torrent's root, outside info so the info hash is unchanged (can be specified multiple times)")
+	createCmd.Flags().StringArrayVar(&options.infoMeta, "info-meta", nil, "add a custom key=value string to the info dictionary; changes the info hash (can be specified multiple times)")
+	createCmd.Flags().StringArrayVar(&options.nodes, "node", nil, "add a DHT bootstrap node as host:port for trackerless torrents (can be specified multiple times)")
 	createCmd.Flags().BoolVarP(&options.isPrivate, "private", "p", true, "make torrent private")
 	createCmd.Flags().StringVarP(&options.comment, "comment", "c", "", "add comment")
 
-	var defaultPieceLength, defaultMaxPieceLength, defaultTargetPieceCount uint
-	createCmd.Flags().UintVarP(&defaultPieceLength, "piece-length", "l", 0, "set piece length to 2^n bytes (16-27, automatic if not specified)")
+	var defaultPieceLength string
+	var defaultMinPieceLength, defaultMaxPieceLength, defaultTargetPieceCount uint
+	createCmd.Flags().StringVarP(&defaultPieceLength, "piece-length", "l", "", "set piece length to 2^n bytes (16-27, automatic if not specified), or auto-max for the smallest that keeps the .torrent under the tracker's size limit")
+	createCmd.Flags().UintVar(&defaultMinPieceLength, "min-piece-length", 0, "raise the automatic piece length to at least 2^n bytes (16-27)")
 	createCmd.Flags().UintVarP(&defaultMaxPieceLength, "max-piece-length", "m", 0, "limit maximum piece length to 2^n bytes (16-27, unlimited if not specified)")
 	createCmd.Flags().UintVar(&defaultTargetPieceCount, "target-piece-count", 0, "target approximate number of pieces (calculates optimal piece length)")
-	createCmd.PreRun = func(cmd *cobra.Command, args []string) {
+	createCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().Changed("piece-length") {
-			options.pieceLengthExp = &defaultPieceLength
+			if defaultPieceLength == pieceLengthAutoMax {
+				options.pieceLengthAutoMax = true
+			} else {
+				exp, err := strconv.ParseUint(defaultPieceLength, 10, 0)
+				if err != nil {
+					return fmt.Errorf("invalid --piece-length %q: must be an exponent (16-27) or %s", defaultPieceLength, pieceLengthAutoMax)
+				}
+				pieceLength := uint(exp)
+				options.pieceLengthExp = &pieceLength
+			}
+		}
+		if cmd.Flags().Changed("min-piece-length") {
+			options.minPieceLengthExp = &defaultMinPieceLength
 		}
 		if cmd.Flags().Changed("max-piece-length") {
 			options.maxPieceLengthExp = &defaultMaxPieceLength
@@ -102,23 +234,58 @@ func init() {
 		if cmd.Flags().Changed("target-piece-count") {
 			options.targetPieceCount = &defaultTargetPieceCount
 		}
+		return nil
 	}
 
 	createCmd.Flags().StringVar(&options.name, "name", "", "set torrent name (default: <filename>)")
+	createCmd.Flags().IntVar(&options.pathDepth, "path-depth", 0, "keep N parent directories of the path in the torrent (the top one becomes the name), or strip N leading directories when negative")
+	createCmd.Flags().StringArrayVar(&options.addSources, "add", nil, "add a file or directory as path[=path/in/torrent] instead of a path argument, combining several into one torrent (can be specified multiple times)")
 	createCmd.Flags().StringVarP(&options.outputPath, "output", "o", "", "set output path (default: <filename>.torrent)")
+	createCmd.Flags().StringVar(&options.outputPattern, "output-pattern", "", "output filename pattern with placeholders {name}, {tracker}, {date}, {infohash}, {infohash8}, {size}")
 	createCmd.Flags().StringVar(&options.outputDir, "output-dir", "", "output directory for created torrent")
-	createCmd.Flags().StringVarP(&options.source, "source", "s", "", "add source string")
+	createCmd.Flags().StringVarP(&options.source, "source", "s", "", "add source string, {tracker} expands to the tracker's domain")
+	createCmd.Flags().StringToStringVar(&options.sourceMap, "source-map", nil, "source per tracker domain, e.g. \"example.com=EX,other.org=OT\" (overrides --source)")
 	createCmd.Flags().BoolVarP(&options.noDate, "no-date", "d", false, "don't write creation date")
+	createCmd.Flags().StringVar(&options.setDate, "set-date", "", "write this creation date instead of now (RFC 3339 or unix seconds)")
 	createCmd.Flags().BoolVarP(&options.noCreator, "no-creator", "", false, "don't write creator")
+	createCmd.Flags().StringVar(&options.createdBy, "created-by", "", "set a custom creator string instead of mkbrr/<version>")
+	createCmd.Flags().StringVar(&options.copyTarget, "copy", "", "copy the magnet link (magnet) or the info hash (hash) to the clipboard after creating")
+	createCmd.Flags().BoolVar(&options.anonymous, "anonymous", false, "don't write creator, creation date, comment or web seeds unless given explicitly")
 	createCmd.Flags().BoolVarP(&options.entropy, "entropy", "e", false, "randomize info hash by adding entropy field")
+	createCmd.Flags().BoolVar(&options.padded, "padded", false, "insert BEP 47 padding files so each file starts on a piece boundary (changes the info hash)")
+	createCmd.Flags().BoolVar(&options.dedupHardlinks, "dedup-hardlinks", false, "hash data shared by hardlinked files once (Linux/macOS/BSD; pieces only line up reliably with --padded)")
+	createCmd.Flags().BoolVar(&options.derefJunctions, "dereference-junctions", false, "follow NTFS directory junctions instead of skipping them (Windows)")
+	createCmd.Flags().BoolVar(&options.legacyUTF8, "legacy-utf8", false, "also write name.utf-8 and path.utf-8 fields for older clients (changes the info hash)")
 	createCmd.Flags().BoolVarP(&options.verbose, "verbose", "v", false, "be verbose")
 	createCmd.Flags().BoolVarP(&options.quiet, "quiet", "q", false, "reduced output mode (prints only final torrent path)")
 	createCmd.Flags().BoolVarP(&options.infoOnly, "info-only", "i", false, "display only torrent info without progress (implies verbose)")
+	createCmd.Flags().BoolVar(&options.progressJSON, "progress-json", false, "write hashing progress to stderr as JSON lines (replaces the progress bar)")
 	createCmd.Flags().BoolVarP(&options.skipPrefix, "skip-prefix", "", false, "don't add tracker domain prefix to output filename")
-	createCmd.Flags().BoolVar(&options.failOnSeasonWarning, "fail-on-season-warning", false, "fail on season pack warning")
+	createCmd.Flags().StringVar(&options.outputPrefix, "prefix", "", "prepend this to the output filename, before the tracker prefix (combine with --skip-prefix to replace it)")
+	createCmd.Flags().StringVar(&options.outputSuffix, "suffix", "", "append this to the output filename, before .torrent")
+	createCmd.Flags().BoolVarP(&options.force, "force", "f", false, "overwrite the output file if it already exists")
+	createCmd.Flags().StringVar(&options.execCommand, "exec", "", "run a command after creating, with {path}, {infohash} and {name} substituted (e.g. \"inject.sh {path}\")")
+	createCmd.Flags().StringVar(&options.symlinkTo, "symlink-to", "", "also symlink the written torrent into this directory, e.g. next to the content (copied where symlinks are unsupported)")
+	createCmd.Flags().BoolVar(&options.failOnSeasonWarning, "fail-on-season-warning", false, "abort if the content looks like an incomplete season pack")
+	createCmd.Flags().BoolVar(&options.warnDuplicates, "warn-duplicates", false, "warn about duplicate files (identical content, symlinks or hardlinks) before hashing")
+	createCmd.Flags().BoolVar(&options.failOnEmptyDirs, "fail-on-empty-dirs", false, "fail if the content contains empty directories (they are skipped by default)")
+	createCmd.Flags().DurationVar(&options.waitStable, "wait-stable", 0, "before hashing, wait until no file has changed for this long (e.g. 30s), for content still being written")
+	createCmd.Flags().BoolVar(&options.verifyAfterCreate, "verify-after-create", false, "check the written torrent against the content before exiting (reads all content a second time)")
+	createCmd.Flags().BoolVar(&options.verifyStable, "verify-stable", false, "fail if any file's size or modification time changed while hashing (e.g. content still being written)")
+	createCmd.Flags().StringVar(&options.expectedEpisodes, "expected-episodes", "", "expected episodes for season pack check, as a count (\"10\") or range (\"3-12\")")
+	createCmd.Flags().BoolVar(&options.strict, "strict", false, "treat safety warnings (e.g. public torrent for a private tracker, paths differing only by case) as errors")
 	createCmd.Flags().StringArrayVarP(&options.excludePatterns, "exclude", "", nil, "exclude files matching these patterns (e.g., \"*.nfo,*.jpg\" or --exclude \"*.nfo\" --exclude \"*.jpg\")")
 	createCmd.Flags().StringArrayVarP(&options.includePatterns, "include", "", nil, "include only files matching these patterns (e.g., \"*.mkv,*.mp4\" or --include \"*.mkv\" --include \"*.mp4\")")
+	createCmd.Flags().StringSliceVar(&options.excludeExtensions, "exclude-ext", nil, "exclude files with these extensions (e.g., \"nfo,sfv,txt\")")
+	createCmd.Flags().StringSliceVar(&options.includeExtensions, "include-ext", nil, "include only files with these extensions (e.g., \"mkv,mp4\")")
+	createCmd.Flags().StringArrayVar(&options.excludeRegex, "exclude-regex", nil, "exclude paths matching this regular expression (can be specified multiple times)")
+	createCmd.Flags().StringArrayVar(&options.includeRegex, "include-regex", nil, "include only files whose path matches this regular expression (can be specified multiple times)")
 	createCmd.Flags().IntVar(&options.createWorkers, "workers", 0, "number of worker goroutines for hashing (0 for automatic)")
+	createCmd.Flags().StringVar(&options.ioMode, "io-mode", string(torrent.IOModeSync), "how files are read while hashing (sync, mmap)")
+	createCmd.Flags().StringVar(&options.normalizeUnicode, "normalize-unicode", string(torrent.UnicodeNone), "normalize file and folder names in the torrent (nfc, nfd, none); changes the info hash")
+	createCmd.Flags().StringVar(&options.storage, "storage", string(torrent.StorageAuto), "storage the content is on, tunes workers and read size (auto, ssd, hdd, network)")
+	createCmd.Flags().IntVar(&options.readRetries, "read-retries", torrent.DefaultReadRetries, "retry failed reads this many times with backoff (e.g. on NFS/SMB mounts)")
+	createCmd.Flags().StringVar(&options.maxMemory, "max-memory", "", "cap memory used by hashing buffers, e.g. \"256MiB\" (reduces buffer size, then workers)")
 
 	createCmd.Flags().String("cpuprofile", "", "write cpu profile to file (development flag)")
 
@@ -126,7 +293,10 @@ func init() {
   {{.CommandPath}} /path/to/content [flags]
 
 Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
 `)
 }
 
@@ -155,22 +325,119 @@ func setupProfiling(cmd *cobra.Command) (cleanup func(), err error) {
 }
 
 // processBatchMode handles processing multiple torrents using a batch configuration file
-func processBatchMode(opts createOptions, version string, startTime time.Time) error {
-	results, err := torrent.ProcessBatch(opts.batchFile, opts.verbose, opts.quiet, opts.infoOnly, version)
+func processBatchMode(ctx context.Context, opts createOptions, version string, startTime time.Time) error {
+	results, err := torrent.ProcessBatchContext(ctx, opts.batchFile, opts.verbose, opts.quiet, opts.infoOnly, opts.stopOnError, version)
 	if err != nil {
 		return fmt.Errorf("batch processing failed: %w", err)
 	}
 
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+
 	if opts.quiet {
 		for _, result := range results {
 			if result.Success {
 				fmt.Println("Wrote:", result.Info.Path)
+			} else {
+				fmt.Fprintf(os.Stderr, "Failed: %s: %v\n", result.Job.Path, result.Error)
 			}
 		}
 	} else {
 		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
 		display.ShowBatchResults(results, time.Since(startTime))
 	}
+
+	if opts.reportFile != "" {
+		if err := torrent.WriteBatchReport(opts.reportFile, results); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d batch jobs failed", failed, len(results))
+	}
+	return nil
+}
+
+// processStdinMode creates one torrent per content path read from stdin using the shared options
+func processStdinMode(ctx context.Context, cmd *cobra.Command, opts createOptions, version string, startTime time.Time) error {
+	if opts.outputPath != "" {
+		return fmt.Errorf("cannot use --output with --from-stdin; use --output-dir instead")
+	}
+	if opts.name != "" {
+		return fmt.Errorf("cannot use --name with --from-stdin")
+	}
+
+	paths, err := torrent.ReadPathList(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no content paths read from stdin")
+	}
+
+	results := make([]torrent.BatchResult, 0, len(paths))
+	failed := 0
+	for _, path := range paths {
+		result := torrent.BatchResult{Job: torrent.BatchJob{Path: path}}
+
+		createOpts, err := buildCreateOptions(cmd, path, opts, version)
+		if err == nil {
+			result.Trackers = createOpts.TrackerURLs
+			result.Info, err = torrent.CreateContext(ctx, createOpts)
+		}
+		if err == nil && opts.verifyAfterCreate {
+			err = verifyCreated(ctx, result.Info, createOpts, opts)
+		}
+		if err == nil {
+			err = runExec(result.Info, opts)
+		}
+
+		if err != nil {
+			result.Error = withForceHint(err)
+			failed++
+		} else {
+			result.Success = true
+		}
+		results = append(results, result)
+		if errors.Is(err, torrent.ErrCancelled) {
+			break
+		}
+	}
+
+	if opts.quiet {
+		for _, result := range results {
+			if result.Success {
+				fmt.Println("Wrote:", result.Info.Path)
+			} else {
+				fmt.Fprintf(os.Stderr, "Failed: %s: %v\n", result.Job.Path, result.Error)
+			}
+		}
+	} else {
+		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
+		display.ShowBatchResults(results, time.Since(startTime))
+		if !opts.verbose {
+			for _, result := range results {
+				if !result.Success {
+					display.ShowError(fmt.Sprintf("Error processing %s: %v", result.Job.Path, result.Error))
+				}
+			}
+		}
+	}
+
+	if opts.reportFile != "" {
+		if err := torrent.WriteBatchReport(opts.reportFile, results); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d torrents failed", failed, len(results))
+	}
 	return nil
 }
 
@@ -181,25 +448,51 @@ func buildCreateOptions(cmd *cobra.Command, inputPath string, opts createOptions
 		Name:                    opts.name,
 		TrackerURLs:             opts.trackers,
 		WebSeeds:                opts.webSeeds,
+		Nodes:                   opts.nodes,
 		IsPrivate:               opts.isPrivate,
 		Comment:                 opts.comment,
 		PieceLengthExp:          opts.pieceLengthExp,
+		PieceLengthAutoMax:      opts.pieceLengthAutoMax,
+		MinPieceLength:          opts.minPieceLengthExp,
 		MaxPieceLength:          opts.maxPieceLengthExp,
 		TargetPieceCount:        opts.targetPieceCount,
 		Source:                  opts.source,
 		NoDate:                  opts.noDate,
 		NoCreator:               opts.noCreator,
+		CreatedBy:               opts.createdBy,
 		Verbose:                 opts.verbose,
 		Version:                 version,
 		Entropy:                 opts.entropy,
+		Padded:                  opts.padded,
+		DedupHardlinks:          opts.dedupHardlinks,
+		DereferenceJunctions:    opts.derefJunctions,
+		LegacyUTF8:              opts.legacyUTF8,
+		SourceMap:               opts.sourceMap,
 		Quiet:                   opts.quiet,
 		InfoOnly:                opts.infoOnly,
 		SkipPrefix:              opts.skipPrefix,
+		OutputPrefix:            opts.outputPrefix,
+		OutputSuffix:            opts.outputSuffix,
+		Force:                   opts.force,
+		SymlinkTo:               opts.symlinkTo,
+		PathDepth:               opts.pathDepth,
 		ExcludePatterns:         opts.excludePatterns,
 		IncludePatterns:         opts.includePatterns,
+		ExcludeRegex:            opts.excludeRegex,
+		IncludeRegex:            opts.includeRegex,
 		Workers:                 opts.createWorkers,
+		ReadRetries:             opts.readRetries,
 		OutputDir:               opts.outputDir,
 		FailOnSeasonPackWarning: opts.failOnSeasonWarning,
+		WarnDuplicates:          opts.warnDuplicates,
+		FailOnEmptyDirs:         opts.failOnEmptyDirs,
+		VerifyStable:            opts.verifyStable,
+		WaitStable:              opts.waitStable,
+		Strict:                  opts.strict,
+	}
+
+	if opts.progressJSON {
+		createOpts.ProgressCallback = torrent.NewJSONProgressCallback(os.Stderr)
 	}
 
 	// If a preset is specified, load the preset options and merge with command-line flags
@@ -262,6 +555,11 @@ func buildCreateOptions(cmd *cobra.Command, inputPath string, opts createOptions
 			createOpts.TargetPieceCount = &count
 		}
 
+		if presetOpts.MinPieceLength != 0 && !cmd.Flags().Changed("min-piece-length") {
+			minPieceLen := presetOpts.MinPieceLength
+			createOpts.MinPieceLength = &minPieceLen
+		}
+
 		if presetOpts.MaxPieceLength != 0 && !cmd.Flags().Changed("max-piece-length") {
 			maxPieceLen := presetOpts.MaxPieceLength
 			createOpts.MaxPieceLength = &maxPieceLen
@@ -296,6 +594,109 @@ func buildCreateOptions(cmd *cobra.Command, inputPath string, opts createOptions
 		}
 	}
 
+	for _, s := range opts.addSources {
+		spec, err := torrent.ParseSourceSpec(s)
+		if err != nil {
+			return createOpts, fmt.Errorf("invalid --add: %w", err)
+		}
+		createOpts.Sources = append(createOpts.Sources, spec)
+	}
+
+	// extension flags add to whatever patterns came from --exclude/--include and the preset
+	excludeExt, err := torrent.ExtensionPatterns(opts.excludeExtensions)
+	if err != nil {
+		return createOpts, fmt.Errorf("invalid --exclude-ext: %w", err)
+	}
+	createOpts.ExcludePatterns = append(createOpts.ExcludePatterns, excludeExt...)
+
+	includeExt, err := torrent.ExtensionPatterns(opts.includeExtensions)
+	if err != nil {
+		return createOpts, fmt.Errorf("invalid --include-ext: %w", err)
+	}
+	createOpts.IncludePatterns = append(createOpts.IncludePatterns, includeExt...)
+
+	// --anonymous strips identifying metadata, including values from a preset,
+	// but leaves anything given explicitly on the command line
+	if opts.anonymous {
+		if !cmd.Flags().Changed("no-date") {
+			createOpts.NoDate = true
+		}
+		if !cmd.Flags().Changed("no-creator") {
+			createOpts.NoCreator = true
+		}
+		if !cmd.Flags().Changed("comment") {
+			createOpts.Comment = ""
+		}
+		if !cmd.Flags().Changed("web-seed") {
+			createOpts.WebSeeds = nil
+		}
+	}
+
+	if opts.expectedEpisodes != "" {
+		episodes, err := torrent.ParseEpisodeRange(opts.expectedEpisodes)
+		if err != nil {
+			return createOpts, fmt.Errorf("invalid --expected-episodes: %w", err)
+		}
+		createOpts.ExpectedEpisodes = episodes
+	}
+
+	ioMode, err := torrent.ParseIOMode(opts.ioMode)
+	if err != nil {
+		return createOpts, err
+	}
+	createOpts.IOMode = ioMode
+
+	unicodeForm, err := torrent.ParseUnicodeForm(opts.normalizeUnicode)
+	if err != nil {
+		return createOpts, err
+	}
+	createOpts.NormalizeUnicode = unicodeForm
+
+	storage, err := torrent.ParseStorageType(opts.storage)
+	if err != nil {
+		return createOpts, err
+	}
+	createOpts.Storage = storage
+
+	if opts.maxMemory != "" {
+		maxMemory, err := humanize.ParseBytes(opts.maxMemory)
+		if err != nil || maxMemory == 0 {
+			return createOpts, fmt.Errorf("invalid --max-memory %q: expected a size such as 256MiB", opts.maxMemory)
+		}
+		createOpts.MaxMemory = int64(maxMemory)
+	}
+
+	// Trackers from --announce-list-file and --trackers-from are appended as tiers after any --tracker URLs
+	var tiers [][]string
+	if opts.announceListFile != "" {
+		fileTiers, err := torrent.LoadAnnounceListFile(opts.announceListFile)
+		if err != nil {
+			return createOpts, err
+		}
+		tiers = append(tiers, fileTiers...)
+	}
+	if opts.trackersFrom != "" {
+		copiedTiers, err := torrent.LoadTrackerTiers(opts.trackersFrom)
+		if err != nil {
+			return createOpts, fmt.Errorf("could not copy trackers: %w", err)
+		}
+		tiers = append(tiers, copiedTiers...)
+	}
+	if len(tiers) > 0 {
+		announceList := make([][]string, 0, len(createOpts.TrackerURLs)+len(tiers))
+		for _, tracker := range createOpts.TrackerURLs {
+			announceList = append(announceList, []string{tracker})
+		}
+		announceList = append(announceList, tiers...)
+		createOpts.AnnounceList = announceList
+
+		trackerURLs := slices.Clone(createOpts.TrackerURLs)
+		for _, tier := range tiers {
+			trackerURLs = append(trackerURLs, tier...)
+		}
+		createOpts.TrackerURLs = trackerURLs
+	}
+
 	// Check for tracker's default source only if no source is set by flag or preset
 	if createOpts.Source == "" && !cmd.Flags().Changed("source") && len(createOpts.TrackerURLs) > 0 {
 		if trackerSource, ok := trackers.GetTrackerDefaultSource(createOpts.TrackerURLs[0]); ok {
@@ -303,30 +704,111 @@ func buildCreateOptions(cmd *cobra.Command, inputPath string, opts createOptions
 		}
 	}
 
+	if err := preset.ValidateSourcePattern(createOpts.Source); err != nil {
+		return createOpts, err
+	}
+
+	switch opts.copyTarget {
+	case "", "magnet", "hash":
+	default:
+		return createOpts, fmt.Errorf("invalid --copy %q: must be magnet or hash", opts.copyTarget)
+	}
+
+	for _, node := range createOpts.Nodes {
+		if _, err := torrent.ParseNode(node); err != nil {
+			return createOpts, err
+		}
+	}
+
+	for _, field := range opts.meta {
+		key, value, err := torrent.ParseMetaField(field)
+		if err != nil {
+			return createOpts, err
+		}
+		if createOpts.Meta == nil {
+			createOpts.Meta = make(map[string]string)
+		}
+		createOpts.Meta[key] = value
+	}
+	for _, field := range opts.infoMeta {
+		key, value, err := torrent.ParseMetaField(field)
+		if err != nil {
+			return createOpts, err
+		}
+		if createOpts.InfoMeta == nil {
+			createOpts.InfoMeta = make(map[string]string)
+		}
+		createOpts.InfoMeta[key] = value
+	}
+	if len(createOpts.Nodes) > 0 && createOpts.IsPrivate {
+		return createOpts, fmt.Errorf("--node cannot be used with a private torrent; add --private=false")
+	}
+
+	if opts.setDate != "" {
+		if cmd.Flags().Changed("no-date") && opts.noDate {
+			return createOpts, fmt.Errorf("cannot use both --set-date and --no-date")
+		}
+		date, err := torrent.ParseCreationDate(opts.setDate)
+		if err != nil {
+			return createOpts, err
+		}
+		createOpts.CreationDate = date
+	}
+
+	// a no-creator default from a preset or config file yields to --created-by
+	if opts.createdBy != "" && cmd.Flags().Changed("no-creator") && opts.noCreator {
+		return createOpts, fmt.Errorf("cannot use both --created-by and --no-creator")
+	}
+
 	// validate: piece_length and target_piece_count are mutually exclusive after all merging
-	if createOpts.PieceLengthExp != nil && createOpts.TargetPieceCount != nil {
+	if (createOpts.PieceLengthExp != nil || createOpts.PieceLengthAutoMax) && createOpts.TargetPieceCount != nil {
 		return createOpts, fmt.Errorf("cannot use both --piece-length and --target-piece-count; use one or the other")
 	}
 
 	if opts.outputPath != "" {
 		createOpts.OutputPath = opts.outputPath
+		if opts.outputPrefix != "" || opts.outputSuffix != "" {
+			return createOpts, fmt.Errorf("cannot use --prefix or --suffix with --output, which sets the whole filename")
+		}
+	}
+
+	if opts.outputPattern != "" {
+		if opts.outputPath != "" {
+			return createOpts, fmt.Errorf("cannot use both --output and --output-pattern")
+		}
+		if err := preset.ValidateOutputPattern(opts.outputPattern); err != nil {
+			return createOpts, err
+		}
+		createOpts.OutputPattern = opts.outputPattern
 	}
 
 	return createOpts, nil
 }
 
 // createSingleTorrent handles creating a single torrent file
-func createSingleTorrent(cmd *cobra.Command, args []string, opts createOptions, version string, startTime time.Time) error {
-	inputPath := args[0]
+func createSingleTorrent(ctx context.Context, cmd *cobra.Command, args []string, opts createOptions, version string, startTime time.Time) error {
+	var inputPath string // empty when the content is given with --add
+	if len(args) > 0 {
+		inputPath = args[0]
+	}
 
 	createOpts, err := buildCreateOptions(cmd, inputPath, opts, version)
 	if err != nil {
 		return err
 	}
 
-	torrentInfo, err := torrent.Create(createOpts)
+	// trackers are checked alongside hashing so the check adds no time
+	var trackerChecks chan []torrent.TrackerStatus
+	if opts.checkTrackers && len(createOpts.TrackerURLs) > 0 {
+		trackerChecks = make(chan []torrent.TrackerStatus, 1)
+		go func() {
+			trackerChecks <- torrent.CheckTrackers(createOpts.TrackerURLs, opts.trackerTimeout)
+		}()
+	}
+
+	torrentInfo, err := torrent.CreateContext(ctx, createOpts)
 	if err != nil {
-		return err
+		return withForceHint(err)
 	}
 
 	if opts.quiet {
@@ -334,6 +816,7 @@ func createSingleTorrent(cmd *cobra.Command, args []string, opts createOptions,
 	} else if !opts.infoOnly {
 		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
 		display.ShowOutputPathWithTime(torrentInfo.Path, time.Since(startTime))
+		display.ShowHashSummary(torrentInfo.Hash)
 	} else {
 		if opts.infoOnly {
 			prevNoColor := color.NoColor
@@ -342,11 +825,117 @@ func createSingleTorrent(cmd *cobra.Command, args []string, opts createOptions,
 		}
 		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose || opts.infoOnly))
 		display.ShowOutputPathWithTime(torrentInfo.Path, time.Since(startTime))
+		display.ShowHashSummary(torrentInfo.Hash)
 	}
 
+	if trackerChecks != nil {
+		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
+		display.SetQuiet(opts.quiet || opts.infoOnly)
+		display.ShowTrackerStatuses(<-trackerChecks)
+	}
+
+	if torrentInfo.LinkPath != "" {
+		display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
+		display.SetQuiet(opts.quiet || opts.infoOnly)
+		display.ShowLinkedOutput(torrentInfo.LinkPath, torrentInfo.LinkCopied)
+	}
+
+	if opts.verifyAfterCreate {
+		if err := verifyCreated(ctx, torrentInfo, createOpts, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.copyTarget != "" {
+		copyToClipboard(torrentInfo, opts)
+	}
+
+	return runExec(torrentInfo, opts)
+}
+
+// verifyCreated checks a written torrent against the content it was created from,
+// reading it again with VerifyData rather than trusting the hashes just computed
+func verifyCreated(ctx context.Context, torrentInfo *torrent.TorrentInfo, createOpts torrent.CreateOptions, opts createOptions) error {
+	contentPath := createOpts.Path
+	if createOpts.PathDepth > 0 {
+		// the torrent's root is the directory PathDepth levels up
+		abs, err := filepath.Abs(contentPath)
+		if err != nil {
+			return err
+		}
+		for range createOpts.PathDepth {
+			abs = filepath.Dir(abs)
+		}
+		contentPath = abs
+	}
+
+	result, err := torrent.VerifyDataContext(ctx, torrent.VerifyOptions{
+		TorrentPath: torrentInfo.Path,
+		ContentPath: contentPath,
+		Quiet:       opts.quiet || opts.infoOnly,
+		Workers:     createOpts.Workers,
+		ReadRetries: createOpts.ReadRetries,
+		Storage:     createOpts.Storage,
+	})
+	if err != nil {
+		return fmt.Errorf("torrent written but verifying it failed: %w", err)
+	}
+	if result.Completion < 100 {
+		return fmt.Errorf("torrent written but does not match the content: %d bad pieces, %d missing files (%.2f%% complete)",
+			result.BadPieces, len(result.MissingFiles), result.Completion)
+	}
+	if !opts.quiet {
+		fmt.Printf("Verified: %d/%d pieces match the content\n", result.GoodPieces, result.TotalPieces)
+	}
+	return nil
+}
+
+// runExec runs the --exec command for a created torrent, printing its output in verbose mode
+func runExec(torrentInfo *torrent.TorrentInfo, opts createOptions) error {
+	if opts.execCommand == "" {
+		return nil
+	}
+	out, err := torrent.RunExec(opts.execCommand, torrentInfo)
+	if opts.verbose && len(out) > 0 {
+		os.Stdout.Write(out)
+	}
+	if err != nil {
+		return fmt.Errorf("torrent written but post-create command failed: %w", err)
+	}
 	return nil
 }
 
+// withForceHint points at --force when err is due to an existing output file
+func withForceHint(err error) error {
+	if errors.Is(err, torrent.ErrOutputExists) {
+		return fmt.Errorf("%w, use --force to overwrite", err)
+	}
+	return err
+}
+
+// copyToClipboard copies the magnet link or info hash of a created torrent. Failing
+// to copy, e.g. on a headless system, only warns since the torrent was written.
+func copyToClipboard(torrentInfo *torrent.TorrentInfo, opts createOptions) {
+	display := torrent.NewDisplay(torrent.NewFormatter(opts.verbose))
+	display.SetQuiet(opts.quiet || opts.infoOnly)
+
+	text, what := torrentInfo.InfoHash, "info hash"
+	if opts.copyTarget == "magnet" {
+		magnet, err := torrentInfo.MetaInfo.MagnetV2()
+		if err != nil {
+			display.ShowWarning(fmt.Sprintf("could not build magnet link: %v", err))
+			return
+		}
+		text, what = magnet.String(), "magnet link"
+	}
+
+	if err := clipboard.WriteAll(text); err != nil {
+		display.ShowWarning(fmt.Sprintf("could not copy %s to clipboard: %v", what, err))
+		return
+	}
+	display.ShowMessage(fmt.Sprintf("Copied %s to clipboard", what))
+}
+
 func runCreate(cmd *cobra.Command, args []string) error {
 	cleanup, err := setupProfiling(cmd)
 	if err != nil {
@@ -356,9 +945,21 @@ func runCreate(cmd *cobra.Command, args []string) error {
 
 	start := time.Now()
 
+	// Ctrl-C stops hashing; torrents are only written once hashed, so nothing partial is left
+	ctx, stop := notifyInterrupt(cmd.Context())
+	defer stop()
+
 	if options.batchFile != "" {
-		return processBatchMode(options, version, start)
+		err = processBatchMode(ctx, options, version, start)
+	} else if options.fromStdin {
+		err = processStdinMode(ctx, cmd, options, version, start)
+	} else {
+		err = createSingleTorrent(ctx, cmd, args, options, version, start)
 	}
 
-	return createSingleTorrent(cmd, args, options, version, start)
+	if err != nil && ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr) // end the interrupted progress line
+		return &exitCodeError{err: torrent.ErrCancelled, code: ExitCancelled}
+	}
+	return err
 }
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/autobrr/mkbrr/torrent"
+)
+
+var doctorTimeout time.Duration
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [dir]",
+	Short: "Check how well hashing works on this machine",
+	Long: `Runs a short self-test for bug reports about slow or hanging hashing.
+
+A small test file is written to dir (default: the system temp directory) and hashed
+with each I/O mode. A mode that fails or doesn't finish within --timeout is reported
+as unsafe. Pass a directory on the same disk or mount as your content to test that
+storage. The report also shows the platform, kernel and detected storage type.`,
+	Args:                  cobra.MaximumNArgs(1),
+	RunE:                  runDoctor,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+}
+
+func init() {
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 30*time.Second, "report an I/O mode as hanging if its test takes longer than this")
+	doctorCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} [dir] [flags]
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
+`)
+}
+
+// kernelRelease returns the running kernel version where it can be read cheaply
+func kernelRelease() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(release))
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	dir := os.TempDir()
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if fi, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("invalid directory %q: %w", dir, err)
+	} else if !fi.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	out := cmd.OutOrStdout()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	system := fmt.Sprintf("%s/%s, %d CPUs", runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+	if kernel := kernelRelease(); kernel != "" {
+		system += ", kernel " + kernel
+	}
+	storage, detected := torrent.DetectStorage(dir)
+	storageStr := string(storage)
+	if !detected {
+		storageStr = fmt.Sprintf("unknown, %s defaults are used", torrent.StorageSSD)
+	}
+
+	fmt.Fprintf(out, "%s\n", cyan("System:"))
+	showPresetField(out, "Version:", version)
+	showPresetField(out, "Platform:", system)
+	showPresetField(out, "Test directory:", dir)
+	showPresetField(out, "Storage:", storageStr)
+
+	fmt.Fprintf(out, "\n%s\n", cyan("I/O modes:"))
+	var unsafe []string
+	for _, mode := range []torrent.IOMode{torrent.IOModeSync, torrent.IOModeMmap} {
+		probe := torrent.ProbeIOMode(dir, mode, doctorTimeout)
+		name := string(mode)
+		if mode == torrent.IOModeSync {
+			name += " (default)"
+		}
+		switch {
+		case probe.TimedOut:
+			showPresetField(out, name+":", red(fmt.Sprintf("hangs (no result after %s)", doctorTimeout)))
+			unsafe = append(unsafe, string(mode))
+		case probe.Err != nil:
+			showPresetField(out, name+":", red(fmt.Sprintf("fails: %v", probe.Err)))
+			unsafe = append(unsafe, string(mode))
+		default:
+			showPresetField(out, name+":", green(fmt.Sprintf("ok (%s)", probe.Elapsed.Round(time.Millisecond))))
+		}
+	}
+
+	if len(unsafe) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	if len(unsafe) == 1 && unsafe[0] == string(torrent.IOModeMmap) {
+		fmt.Fprintf(os.Stderr, "%s mmap is unsafe on this machine, use --io-mode sync (the default)\n", yellow("Note:"))
+	}
+	if len(unsafe) == 1 {
+		return fmt.Errorf("I/O mode %s is unsafe on this machine, include this report when filing an issue", unsafe[0])
+	}
+	return fmt.Errorf("I/O modes %s are unsafe on this machine, include this report when filing an issue", strings.Join(unsafe, " and "))
+}
@@ -3,18 +3,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/anacrolix/torrent/bencode"
 	"github.com/anacrolix/torrent/metainfo"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/autobrr/mkbrr/internal/preset"
 	"github.com/autobrr/mkbrr/torrent"
 )
 
 // inspectOptions encapsulates command-line flag values for the inspect command
 type inspectOptions struct {
-	verbose bool
+	validateTracker string
+	failOn          string
+	expectHash      string
+	format          string
+	fields          []string
+	timeout         time.Duration
+	verbose         bool
+	redact          bool
+	withPieces      bool
 }
 
 var (
@@ -26,7 +37,7 @@ var (
 var inspectCmd = &cobra.Command{
 	Use:                        "inspect [flags] [torrent files...]",
 	Short:                      "Inspect torrent files",
-	Long:                       "Inspect torrent files",
+	Long:                       "Inspect torrent files.\nArguments can also be http(s) URLs to a .torrent file, magnet links (hash, name and trackers only), or - to read a torrent piped to stdin.",
 	Args:                       cobra.MinimumNArgs(1),
 	RunE:                       runInspect,
 	DisableFlagsInUseLine:      true,
@@ -37,30 +48,82 @@ var inspectCmd = &cobra.Command{
 func init() {
 	inspectCmd.Flags().SortFlags = false
 	inspectCmd.Flags().BoolVarP(&inspectOpts.verbose, "verbose", "v", false, "show all metadata fields")
+	inspectCmd.Flags().StringVarP(&inspectOpts.validateTracker, "validate-tracker", "T", "", "validate torrent against the rules of this tracker URL, tracker alias or preset name")
+	inspectCmd.Flags().StringVar(&inspectOpts.failOn, "fail-on", "", "exit non-zero if any validation result is at or above this severity (warn, fail)")
+	inspectCmd.Flags().StringVar(&inspectOpts.expectHash, "expect-hash", "", "exit non-zero unless the info hash matches this v1 (40) or v2 (64 hex characters) hash")
+	inspectCmd.Flags().StringVarP(&inspectOpts.format, "format", "f", "text", "output format (text, json)")
+	inspectCmd.Flags().StringSliceVar(&inspectOpts.fields, "fields", nil, "only output these comma-separated JSON fields, e.g. name,infoHash,size (requires --format json)")
+	inspectCmd.Flags().BoolVar(&inspectOpts.withPieces, "with-pieces", false, "include the hex piece hashes (and v2 piece layers) in the JSON output (requires --format json)")
+	inspectCmd.Flags().BoolVar(&inspectOpts.redact, "redact", false, "mask passkeys in tracker URLs and magnet links, e.g. to share the output")
+	inspectCmd.Flags().DurationVar(&inspectOpts.timeout, "timeout", torrent.DefaultRemoteTimeout, "timeout for fetching torrents from http(s) URLs")
 	inspectCmd.SetUsageTemplate(`Usage:
   {{.CommandPath}} [flags] [torrent files...]
 
 Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
 `)
 }
 
-// loadTorrentData reads the torrent file and extracts metainfo, info, and raw bytes
-func loadTorrentData(filePath string) (mi *metainfo.MetaInfo, info *metainfo.Info, rawBytes []byte, err error) {
-	rawBytes, err = os.ReadFile(filePath)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("error reading file: %w", err)
+// resolveValidateTracker turns the --validate-tracker value into a tracker URL.
+// Values without a scheme are looked up in tracker_aliases of the global config,
+// then as a preset name (its first tracker); anything else is used as given,
+// so tracker hosts like "passthepopcorn.me" keep working.
+func resolveValidateTracker(value string) string {
+	if value == "" || strings.Contains(value, "://") {
+		return value
 	}
 
-	mi, err = metainfo.LoadFromFile(filePath)
-	if err != nil {
-		return nil, nil, rawBytes, fmt.Errorf("error loading torrent: %w", err)
+	if url, ok := globalConfig.TrackerAlias(value); ok {
+		return url
+	}
+
+	if presetPath, err := preset.FindPresetFile(""); err == nil {
+		if config, err := preset.Load(presetPath); err == nil {
+			if opts, err := config.GetPreset(value); err == nil && len(opts.Trackers) > 0 {
+				return opts.Trackers[0]
+			}
+		}
+	}
+
+	return value
+}
+
+// loadTorrentData reads the torrent file or URL and extracts metainfo, info, and raw bytes
+func loadTorrentData(filePath string, timeout time.Duration) (mi *metainfo.MetaInfo, info *metainfo.Info, rawBytes []byte, err error) {
+	if torrent.IsRemoteTorrent(filePath) {
+		t, data, err := torrent.LoadFromURL(filePath, timeout)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		mi, rawBytes = t.MetaInfo, data
+	} else if torrent.IsStdinTorrent(filePath) {
+		t, data, err := torrent.LoadFromStdin()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		mi, rawBytes = t.MetaInfo, data
+	} else {
+		rawBytes, err = os.ReadFile(filePath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error reading file: %w", err)
+		}
+
+		mi, err = metainfo.LoadFromFile(filePath)
+		if err != nil {
+			return nil, nil, rawBytes, fmt.Errorf("error loading torrent: %w", err)
+		}
 	}
 
 	parsedInfo, err := mi.UnmarshalInfo()
 	if err != nil {
 		return mi, nil, rawBytes, fmt.Errorf("error parsing info: %w", err)
 	}
+	if err := torrent.ValidatePieces(&parsedInfo); err != nil {
+		return mi, nil, rawBytes, err
+	}
 
 	return mi, &parsedInfo, rawBytes, nil
 }
@@ -98,7 +161,7 @@ func displayVerboseInfo(rawBytes []byte, mi *metainfo.MetaInfo) {
 			"name": true, "piece length": true, "pieces": true,
 			"files": true, "length": true, "private": true,
 			"source": true, "path": true, "paths": true,
-			"md5sum": true,
+			"md5sum": true, "name.utf-8": true, "path.utf-8": true,
 		}
 
 		for k, v := range infoMap {
@@ -117,13 +180,114 @@ func displayFileTreeIfNeeded(display *torrent.Display, info *metainfo.Info) {
 	}
 }
 
+// inspectJSONOutput returns the JSON value to print for one torrent,
+// narrowed to the --fields selection when given
+func inspectJSONOutput(j torrent.TorrentInspectJSON) (any, error) {
+	if inspectOpts.redact {
+		j.Redact()
+	}
+	if len(inspectOpts.fields) == 0 {
+		return j, nil
+	}
+	return j.SelectFields(inspectOpts.fields)
+}
+
 func runInspect(cmd *cobra.Command, args []string) error {
+	switch inspectOpts.format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of text, json", inspectOpts.format)
+	}
+	jsonOutput := inspectOpts.format == "json"
+	if len(inspectOpts.fields) > 0 && !jsonOutput {
+		return fmt.Errorf("--fields requires --format json")
+	}
+	if inspectOpts.withPieces && !jsonOutput {
+		return fmt.Errorf("--with-pieces requires --format json")
+	}
+	if !inspectOpts.withPieces {
+		for _, field := range inspectOpts.fields {
+			if f := strings.ToLower(strings.TrimSpace(field)); f == "pieces" || f == "piecelayers" {
+				return fmt.Errorf("field %q requires --with-pieces", strings.TrimSpace(field))
+			}
+		}
+	}
+
+	inspectOpts.validateTracker = resolveValidateTracker(inspectOpts.validateTracker)
+
+	var failOn torrent.ValidationSeverity
+	if inspectOpts.failOn != "" {
+		if inspectOpts.validateTracker == "" {
+			return fmt.Errorf("--fail-on requires --validate-tracker")
+		}
+		var err error
+		failOn, err = torrent.ParseValidationSeverity(inspectOpts.failOn)
+		if err != nil {
+			return err
+		}
+	}
+
+	if inspectOpts.expectHash != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("--expect-hash requires exactly one torrent")
+		}
+		if _, err := torrent.ParseInfoHash(inspectOpts.expectHash); err != nil {
+			return err
+		}
+	}
+
 	display := torrent.NewDisplay(torrent.NewFormatter(inspectOpts.verbose))
+	display.SetRedact(inspectOpts.redact)
+	var hashes torrent.InfoHashes
+	var validationResults []torrent.ValidationResult
+	var jsonResults []any
 	for _, path := range args {
-		mi, info, rawBytes, err := loadTorrentData(path)
+		if torrent.IsMagnetLink(path) {
+			if inspectOpts.validateTracker != "" {
+				return fmt.Errorf("cannot validate magnet link against tracker rules: torrent metadata is required")
+			}
+			m, err := torrent.ParseMagnet(path)
+			if err != nil {
+				return err
+			}
+			hashes = torrent.MagnetInfoHashes(m)
+			if jsonOutput {
+				out, err := inspectJSONOutput(torrent.GenerateMagnetInspectJSON(path, m))
+				if err != nil {
+					return err
+				}
+				jsonResults = append(jsonResults, out)
+				continue
+			}
+			display.ShowMagnetInfo(m)
+			continue
+		}
+
+		mi, info, rawBytes, err := loadTorrentData(path, inspectOpts.timeout)
 		if err != nil {
 			return err
 		}
+		hashes = torrent.TorrentInfoHashes(mi, info)
+
+		var results []torrent.ValidationResult
+		if inspectOpts.validateTracker != "" {
+			results = torrent.ValidateForTracker(mi, info, int64(len(rawBytes)), inspectOpts.validateTracker)
+			validationResults = append(validationResults, results...)
+		}
+
+		if jsonOutput {
+			j := torrent.GenerateInspectJSON(mi, info)
+			if inspectOpts.withPieces {
+				j.AddPieces(mi, info)
+			}
+			j.Validation = append(j.Validation, results...)
+			out, err := inspectJSONOutput(j)
+			if err != nil {
+				return err
+			}
+			jsonResults = append(jsonResults, out)
+			continue
+		}
 
 		displayStandardInfo(display, mi, info)
 
@@ -131,6 +295,37 @@ func runInspect(cmd *cobra.Command, args []string) error {
 			displayVerboseInfo(rawBytes, mi)
 			displayFileTreeIfNeeded(display, info)
 		}
+
+		if inspectOpts.validateTracker != "" {
+			display.ShowValidationResults(inspectOpts.validateTracker, results)
+		}
+	}
+
+	if jsonOutput {
+		// a single torrent prints an object, several print an array
+		var v any = jsonResults
+		if len(jsonResults) == 1 {
+			v = jsonResults[0]
+		}
+		if err := writeJSON(cmd.OutOrStdout(), v); err != nil {
+			return err
+		}
+	}
+
+	if inspectOpts.expectHash != "" {
+		if err := hashes.Match(inspectOpts.expectHash); err != nil {
+			return err
+		}
+		if !jsonOutput {
+			green := color.New(color.FgGreen).SprintFunc()
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\n", green("Info hash matches the expected hash"))
+		}
+	}
+
+	if inspectOpts.failOn != "" {
+		if highest := torrent.HighestSeverity(validationResults); highest >= failOn {
+			return fmt.Errorf("tracker validation found results at or above %q severity", failOn)
+		}
 	}
 
 	return nil
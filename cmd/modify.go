@@ -6,29 +6,37 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/autobrr/mkbrr/internal/preset"
 	"github.com/autobrr/mkbrr/torrent"
 )
 
 // modifyOptions encapsulates command-line flag values for the modify command
 type modifyOptions struct {
-	PresetName string
-	PresetFile string
-	Name       string
-	OutputDir  string
-	Output     string
-	Trackers   []string
-	Comment    string
-	Source     string
-	WebSeeds   []string
-	DryRun     bool
-	NoDate     bool
-	NoCreator  bool
-	Verbose    bool
-	Quiet      bool
-	SkipPrefix bool
-	Private    bool
-	NoPrivate  bool
-	Entropy    bool
+	PresetName     string
+	PresetFile     string
+	Name           string
+	OutputDir      string
+	Output         string
+	OutputPattern  string
+	Trackers       []string
+	AppendTrackers bool
+	TrackersFrom   string
+	Comment        string
+	Source         string
+	WebSeeds       []string
+	DryRun         bool
+	NoDate         bool
+	SetDate        string
+	NoCreator      bool
+	CreatedBy      string
+	Anonymous      bool
+	Verbose        bool
+	Quiet          bool
+	SkipPrefix     bool
+	Force          bool
+	Private        bool
+	NoPrivate      bool
+	Entropy        bool
 }
 
 var modifyOpts = modifyOptions{
@@ -41,7 +49,9 @@ var modifyCmd = &cobra.Command{
 	Long: `Modify existing torrent files using a preset or flags.
 This allows batch modification of torrent files with new tracker URLs, source tags, etc.
 Original files are preserved and new files are created with the tracker domain (without TLD) as prefix, e.g. "example_filename.torrent".
-A custom output filename can also be specified via --output.
+The filename follows the torrent's name, so --name also renames the output file.
+A custom output filename can also be specified via --output, or built from placeholders
+with --output-pattern (e.g. "{tracker}_{name}_{date}").
 
 Note: All unnecessary metadata will be stripped.`,
 	Args:                  cobra.MinimumNArgs(1),
@@ -54,12 +64,18 @@ func init() {
 	modifyCmd.Flags().SortFlags = false
 	modifyCmd.Flags().StringVarP(&modifyOpts.PresetName, "preset", "P", "", "use preset from config")
 	modifyCmd.Flags().StringVar(&modifyOpts.PresetFile, "preset-file", "", "preset config file (default: ~/.config/mkbrr/presets.yaml)")
-	modifyCmd.Flags().StringVar(&modifyOpts.Name, "name", "", "set the torrent's internal name")
+	modifyCmd.Flags().StringVar(&modifyOpts.Name, "name", "", "set the torrent's internal name (also used for the output filename)")
 	modifyCmd.Flags().StringVar(&modifyOpts.OutputDir, "output-dir", "", "output directory for modified files")
 	modifyCmd.Flags().StringVarP(&modifyOpts.Output, "output", "o", "", "custom output filename (without extension)")
+	modifyCmd.Flags().StringVar(&modifyOpts.OutputPattern, "output-pattern", "", "output filename pattern with placeholders {name}, {tracker}, {date}, {infohash}, {infohash8}, {size}")
 	modifyCmd.Flags().BoolVarP(&modifyOpts.NoDate, "no-date", "d", false, "don't update creation date")
+	modifyCmd.Flags().StringVar(&modifyOpts.SetDate, "set-date", "", "set the creation date (RFC 3339 or unix seconds)")
 	modifyCmd.Flags().BoolVarP(&modifyOpts.NoCreator, "no-creator", "", false, "don't write creator")
+	modifyCmd.Flags().StringVar(&modifyOpts.CreatedBy, "created-by", "", "set a custom creator string")
+	modifyCmd.Flags().BoolVar(&modifyOpts.Anonymous, "anonymous", false, "remove creator, creation date, comment and web seeds unless given explicitly")
 	modifyCmd.Flags().StringArrayVarP(&modifyOpts.Trackers, "tracker", "t", nil, "tracker URLs (can be specified multiple times)")
+	modifyCmd.Flags().BoolVar(&modifyOpts.AppendTrackers, "append-trackers", false, "add --tracker and --trackers-from trackers as new tiers after the existing ones instead of replacing them")
+	modifyCmd.Flags().StringVar(&modifyOpts.TrackersFrom, "trackers-from", "", "copy the trackers of another .torrent file, keeping its tiers (after any --tracker URLs)")
 	modifyCmd.Flags().StringArrayVarP(&modifyOpts.WebSeeds, "web-seed", "w", nil, "add web seed URLs")
 	modifyCmd.Flags().BoolVarP(&modifyOpts.Private, "private", "p", true, "make torrent private")
 	modifyCmd.Flags().BoolVar(&modifyOpts.NoPrivate, "no-private", false, "remove private flag entirely")
@@ -70,34 +86,45 @@ func init() {
 	modifyCmd.Flags().BoolVarP(&modifyOpts.Quiet, "quiet", "q", false, "reduced output mode (prints only final torrent paths)")
 	modifyCmd.Flags().BoolVarP(&modifyOpts.SkipPrefix, "skip-prefix", "", false, "don't add tracker domain prefix to output filename")
 	modifyCmd.Flags().BoolVarP(&modifyOpts.DryRun, "dry-run", "n", false, "show what would be modified without making changes")
+	modifyCmd.Flags().BoolVarP(&modifyOpts.Force, "force", "f", false, "overwrite output files that already exist")
 
 	modifyCmd.SetUsageTemplate(`Usage:
   {{.CommandPath}} [flags] [torrent files...]
 
 Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
 `)
 }
 
 // buildTorrentOptions creates a torrent.ModifyOptions struct from command-line flags
 func buildTorrentOptions(cmd *cobra.Command, opts modifyOptions) torrent.ModifyOptions {
 	torrentOpts := torrent.ModifyOptions{
-		PresetName:    opts.PresetName,
-		PresetFile:    opts.PresetFile,
-		Name:          opts.Name,
-		OutputDir:     opts.OutputDir,
-		OutputPattern: opts.Output,
-		NoDate:        opts.NoDate,
-		NoCreator:     opts.NoCreator,
-		DryRun:        opts.DryRun,
-		Verbose:       opts.Verbose,
-		Quiet:         opts.Quiet,
-		TrackerURLs:   opts.Trackers,
-		WebSeeds:      opts.WebSeeds,
-		Comment:       opts.Comment,
-		Source:        opts.Source,
-		Version:       version,
-		SkipPrefix:    opts.SkipPrefix,
+		PresetName:     opts.PresetName,
+		PresetFile:     opts.PresetFile,
+		Name:           opts.Name,
+		OutputDir:      opts.OutputDir,
+		OutputPattern:  opts.Output,
+		NoDate:         opts.NoDate,
+		NoCreator:      opts.NoCreator,
+		CreatedBy:      opts.CreatedBy,
+		DryRun:         opts.DryRun,
+		Verbose:        opts.Verbose,
+		Quiet:          opts.Quiet,
+		TrackerURLs:    opts.Trackers,
+		AppendTrackers: opts.AppendTrackers,
+		WebSeeds:       opts.WebSeeds,
+		Comment:        opts.Comment,
+		Source:         opts.Source,
+		Version:        version,
+		SkipPrefix:     opts.SkipPrefix,
+		Force:          opts.Force,
+	}
+
+	if opts.OutputPattern != "" {
+		torrentOpts.OutputPattern = opts.OutputPattern
 	}
 
 	if cmd.Flags().Changed("private") {
@@ -121,6 +148,24 @@ func buildTorrentOptions(cmd *cobra.Command, opts modifyOptions) torrent.ModifyO
 		torrentOpts.Entropy = &opts.Entropy
 	}
 
+	// --anonymous scrubs identifying metadata but leaves source and private alone,
+	// and anything given explicitly on the command line wins
+	if opts.Anonymous {
+		if !cmd.Flags().Changed("no-date") {
+			torrentOpts.NoDate = true
+		}
+		if !cmd.Flags().Changed("no-creator") {
+			torrentOpts.NoCreator = true
+		}
+		if !cmd.Flags().Changed("comment") {
+			torrentOpts.Comment = ""
+			torrentOpts.CommentSet = true
+		}
+		if !cmd.Flags().Changed("web-seed") {
+			torrentOpts.RemoveWebSeeds = true
+		}
+	}
+
 	return torrentOpts
 }
 
@@ -130,7 +175,7 @@ func displayModifyResults(results []*torrent.Result, opts modifyOptions, display
 
 	for _, result := range results {
 		if result.Error != nil {
-			display.ShowError(fmt.Sprintf("Error processing %s: %v", result.Path, result.Error))
+			display.ShowError(fmt.Sprintf("Error processing %s: %v", result.Path, withForceHint(result.Error)))
 			continue
 		}
 
@@ -169,12 +214,46 @@ func displayModifyResults(results []*torrent.Result, opts modifyOptions, display
 func runModify(cmd *cobra.Command, args []string) error {
 	start := time.Now()
 
+	// a no-creator default from the config file yields to --created-by
+	if modifyOpts.CreatedBy != "" && cmd.Flags().Changed("no-creator") && modifyOpts.NoCreator {
+		return fmt.Errorf("cannot use both --created-by and --no-creator")
+	}
+
+	if modifyOpts.OutputPattern != "" {
+		if modifyOpts.Output != "" {
+			return fmt.Errorf("cannot use both --output and --output-pattern")
+		}
+		if err := preset.ValidateOutputPattern(modifyOpts.OutputPattern); err != nil {
+			return err
+		}
+	}
+
 	display := torrent.NewDisplay(torrent.NewFormatter(modifyOpts.Verbose))
 	display.SetQuiet(modifyOpts.Quiet)
 	display.ShowMessage(fmt.Sprintf("Modifying %d torrent files...", len(args)))
 
+	var creationDate time.Time
+	if modifyOpts.SetDate != "" {
+		if cmd.Flags().Changed("no-date") && modifyOpts.NoDate {
+			return fmt.Errorf("cannot use both --set-date and --no-date")
+		}
+		var err error
+		if creationDate, err = torrent.ParseCreationDate(modifyOpts.SetDate); err != nil {
+			return err
+		}
+	}
+
 	// Build torrent options from command-line flags
 	torrentOpts := buildTorrentOptions(cmd, modifyOpts)
+	torrentOpts.CreationDate = creationDate
+
+	if modifyOpts.TrackersFrom != "" {
+		tiers, err := torrent.LoadTrackerTiers(modifyOpts.TrackersFrom)
+		if err != nil {
+			return fmt.Errorf("could not copy trackers: %w", err)
+		}
+		torrentOpts.AnnounceList = tiers
+	}
 
 	// Process the torrent files
 	results, err := torrent.ProcessTorrents(args, torrentOpts)
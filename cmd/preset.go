@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/autobrr/mkbrr/internal/preset"
+)
+
+// presetOptions encapsulates command-line flag values for the preset commands
+type presetOptions struct {
+	presetFile string
+	format     string
+}
+
+var presetOpts = presetOptions{}
+
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "List and show presets",
+	Long:  "List the presets in your preset file and show the options a preset resolves to.",
+}
+
+var presetListCmd = &cobra.Command{
+	Use:                   "list [flags]",
+	Short:                 "List available presets",
+	Args:                  cobra.NoArgs,
+	RunE:                  runPresetList,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+}
+
+var presetShowCmd = &cobra.Command{
+	Use:                   "show <name> [flags]",
+	Short:                 "Show the resolved options of a preset",
+	Long:                  "Show the options a preset resolves to once merged with the default section.",
+	Args:                  cobra.ExactArgs(1),
+	RunE:                  runPresetShow,
+	ValidArgsFunction:     completePresetArgs,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+}
+
+func init() {
+	presetCmd.PersistentFlags().StringVar(&presetOpts.presetFile, "preset-file", "", "preset config file (default ~/.config/mkbrr/presets.yaml)")
+	presetCmd.PersistentFlags().StringVarP(&presetOpts.format, "format", "f", "text", "output format (text, json)")
+	presetCmd.AddCommand(presetListCmd)
+	presetCmd.AddCommand(presetShowCmd)
+
+	presetListCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} [flags]
+
+Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+`)
+	presetShowCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} <name> [flags]
+
+Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+`)
+}
+
+// loadPresetConfig finds and loads the preset file, returning its path
+func loadPresetConfig() (*preset.Config, string, error) {
+	switch presetOpts.format {
+	case "text", "json":
+	default:
+		return nil, "", fmt.Errorf("invalid format %q: must be one of text, json", presetOpts.format)
+	}
+
+	presetPath, err := preset.FindPresetFile(presetOpts.presetFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not find preset file: %w", err)
+	}
+	config, err := preset.Load(presetPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return config, presetPath, nil
+}
+
+func runPresetList(cmd *cobra.Command, args []string) error {
+	config, presetPath, err := loadPresetConfig()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(config.Presets))
+	for name := range config.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := cmd.OutOrStdout()
+	if presetOpts.format == "json" {
+		return writeJSON(out, struct {
+			File    string   `json:"file"`
+			Presets []string `json:"presets"`
+		}{File: presetPath, Presets: names})
+	}
+
+	fmt.Fprintf(out, "%s %s\n", cyan("Presets in"), presetPath)
+	for _, name := range names {
+		p := config.Presets[name]
+		var details []string
+		if p.Source != "" {
+			details = append(details, "source "+p.Source)
+		}
+		if n := len(p.Trackers); n > 0 {
+			details = append(details, fmt.Sprintf("%d tracker(s)", n))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(out, "  %-20s %s\n", label(name), strings.Join(details, ", "))
+		} else {
+			fmt.Fprintf(out, "  %s\n", label(name))
+		}
+	}
+	return nil
+}
+
+func runPresetShow(cmd *cobra.Command, args []string) error {
+	config, _, err := loadPresetConfig()
+	if err != nil {
+		return err
+	}
+
+	opts, err := config.GetPreset(args[0])
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if presetOpts.format == "json" {
+		return writeJSON(out, opts)
+	}
+
+	fmt.Fprintf(out, "%s %s\n", cyan("Preset:"), args[0])
+	showPresetField(out, "Trackers:", strings.Join(opts.Trackers, "\n"+strings.Repeat(" ", 23)))
+	showPresetField(out, "Web seeds:", strings.Join(opts.WebSeeds, "\n"+strings.Repeat(" ", 23)))
+	showPresetField(out, "Private:", formatBoolPtr(opts.Private))
+	showPresetField(out, "Source:", opts.Source)
+	showPresetField(out, "Comment:", opts.Comment)
+	showPresetField(out, "Output dir:", opts.OutputDir)
+	showPresetField(out, "No date:", formatBoolPtr(opts.NoDate))
+	showPresetField(out, "No creator:", formatBoolPtr(opts.NoCreator))
+	showPresetField(out, "Skip prefix:", formatBoolPtr(opts.SkipPrefix))
+	showPresetField(out, "Entropy:", formatBoolPtr(opts.Entropy))
+	showPresetField(out, "Season check:", formatBoolPtr(opts.FailOnSeasonWarning))
+	showPresetField(out, "Exclude:", strings.Join(opts.ExcludePatterns, ", "))
+	showPresetField(out, "Include:", strings.Join(opts.IncludePatterns, ", "))
+	if opts.PieceLength != 0 {
+		showPresetField(out, "Piece length:", fmt.Sprintf("2^%d", opts.PieceLength))
+	}
+	if opts.MinPieceLength != 0 {
+		showPresetField(out, "Min piece len:", fmt.Sprintf("2^%d", opts.MinPieceLength))
+	}
+	if opts.MaxPieceLength != 0 {
+		showPresetField(out, "Max piece len:", fmt.Sprintf("2^%d", opts.MaxPieceLength))
+	}
+	if opts.TargetPieceCount != 0 {
+		showPresetField(out, "Target pieces:", fmt.Sprint(opts.TargetPieceCount))
+	}
+	if opts.Workers != 0 {
+		showPresetField(out, "Workers:", fmt.Sprint(opts.Workers))
+	}
+	return nil
+}
+
+// showPresetField prints a preset option, skipping unset values
+func showPresetField(out io.Writer, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(out, "  %-20s %s\n", label(name), value)
+}
+
+func formatBoolPtr(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return fmt.Sprint(*b)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// completePresetArgs completes preset names for preset show
+func completePresetArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completePresetNames(cmd, args, toComplete)
+}
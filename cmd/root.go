@@ -1,7 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/autobrr/mkbrr/internal/config"
+	"github.com/autobrr/mkbrr/torrent"
 )
 
 const banner = `         __   ___.                 
@@ -12,9 +27,10 @@ const banner = `         __   ___.
       \/     \/    \/              `
 
 var rootCmd = &cobra.Command{
-	Use:   "mkbrr",
-	Short: "A tool to inspect and create torrent files",
-	Long:  banner + "\n\nmkbrr is a tool to create and inspect torrent files.",
+	Use:               "mkbrr",
+	Short:             "A tool to inspect and create torrent files",
+	Long:              banner + "\n\nmkbrr is a tool to create and inspect torrent files.",
+	PersistentPreRunE: applyGlobalConfig,
 }
 
 func init() {
@@ -23,14 +39,189 @@ func init() {
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(inspectCmd)
 	rootCmd.AddCommand(modifyCmd)
+	rootCmd.AddCommand(presetCmd)
+	rootCmd.AddCommand(trackersCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(completionCmd)
+
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "color output (auto, always, never); auto colors only when stdout is a terminal")
+	rootCmd.PersistentFlags().BoolVar(&debugLogging, "debug", false, "write diagnostic logs (storage detection, io mode, workers, read retries) to stderr")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append diagnostic logs to this file instead of stderr (implies --debug)")
+}
+
+var (
+	colorMode    string // --color
+	debugLogging bool   // --debug
+	logFile      string // --log-file
+)
+
+// setupDebugLog routes the torrent package's diagnostic logs to --log-file, or to
+// stderr with --debug, starting with the version and command line for bug reports
+func setupDebugLog() error {
+	var w io.Writer
+	switch {
+	case logFile != "":
+		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("error opening log file: %w", err)
+		}
+		// left open until the process exits, logs are written until then
+		w = f
+	case debugLogging:
+		w = os.Stderr
+	default:
+		return nil
+	}
+
+	torrent.SetDebugLog(w)
+	log.New(w, "", log.LstdFlags|log.Lmicroseconds).Printf("mkbrr %s on %s/%s, %d CPUs: %s",
+		version, runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), strings.Join(redactArgs(os.Args[1:]), " "))
+	return nil
+}
+
+// redactArgs masks tracker passkeys in command line arguments, including values
+// given as --flag=url, so logs attached to bug reports don't leak them
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if name, value, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(name, "-") {
+			redacted[i] = name + "=" + torrent.RedactTrackerURL(value)
+			continue
+		}
+		redacted[i] = torrent.RedactTrackerURL(arg)
+	}
+	return redacted
+}
+
+// autoNoColor is fatih/color's own detection: no color unless stdout is a
+// terminal, or with NO_COLOR set or TERM=dumb
+var autoNoColor = color.NoColor
+
+// applyColorMode enables or disables colored output for all commands
+func applyColorMode(mode string) error {
+	switch mode {
+	case "auto":
+		color.NoColor = autoNoColor
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	default:
+		return fmt.Errorf("invalid color mode %q: must be one of auto, always, never", mode)
+	}
+	return nil
+}
+
+// globalConfig is the loaded ~/.config/mkbrr/config.yaml, nil if there is none
+var globalConfig *config.Config
+
+// globalConfigApplied is set once applyGlobalConfig ran for the executing command
+var globalConfigApplied bool
+
+// applyGlobalConfig fills in flags not given on the command line from
+// MKBRR_* environment variables and ~/.config/mkbrr/config.yaml, then sets up
+// diagnostic logging and the color mode. It runs before the command's Args
+// check (see applyConfigBeforeArgs) and again as PersistentPreRunE for commands
+// cobra adds itself, but only takes effect once.
+func applyGlobalConfig(cmd *cobra.Command, args []string) error {
+	if globalConfigApplied {
+		return nil
+	}
+	globalConfigApplied = true
+
+	configPath, err := config.FindConfigFile()
+	if err == nil {
+		if globalConfig, err = config.Load(configPath); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, config.ErrConfigFileNotFound) {
+		return err
+	}
+
+	if err := config.Apply(globalConfig, cmd.Name(), cmd.Flags()); err != nil {
+		return err
+	}
+	if err := setupDebugLog(); err != nil {
+		return err
+	}
+	return applyColorMode(colorMode)
+}
+
+// applyConfigBeforeArgs makes cmd and its subcommands apply the global config before
+// their Args check. Cobra validates args before PersistentPreRunE, and some checks
+// look at flags, e.g. create accepts no path when a batch file is set in the config.
+// Commands that only group subcommands keep cobra's unknown command check.
+func applyConfigBeforeArgs(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		applyConfigBeforeArgs(sub)
+	}
+	if !cmd.Runnable() {
+		return
+	}
+	validate := cmd.Args
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if err := applyGlobalConfig(cmd, args); err != nil {
+			return err
+		}
+		if validate == nil {
+			return cobra.ArbitraryArgs(cmd, args)
+		}
+		return validate(cmd, args)
+	}
+}
+
+// Process exit codes, see ExitCode
+const (
+	ExitOK         = 0   // success
+	ExitError      = 1   // the command failed, e.g. invalid arguments or an unreadable torrent
+	ExitIncomplete = 2   // check ran but the content is incomplete (bad pieces or missing files)
+	ExitCancelled  = 130 // interrupted with Ctrl-C, as shells report for SIGINT
+)
+
+// exitCodeError carries the process exit code for an error returned by a command
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// notifyInterrupt returns a context that is cancelled on the first Ctrl-C or SIGTERM,
+// so a command can stop its work and clean up instead of being killed. A second Ctrl-C
+// exits immediately. Call stop once the command is done.
+func notifyInterrupt(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	ctx, stop = signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
 }
 
 func Execute() error {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.SilenceUsage = false
 
+	if err := registerCompletions(); err != nil {
+		return fmt.Errorf("could not register shell completions: %w", err)
+	}
+	applyConfigBeforeArgs(rootCmd)
+
 	rootCmd.SetUsageTemplate(`Usage:
   {{.CommandPath}} [command]
 
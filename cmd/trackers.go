@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/autobrr/mkbrr/internal/trackers"
+	"github.com/autobrr/mkbrr/torrent"
+)
+
+// trackersOptions encapsulates command-line flag values for the trackers commands
+type trackersOptions struct {
+	size    string
+	files   int
+	format  string
+	timeout time.Duration
+}
+
+var trackersOpts = trackersOptions{}
+
+var trackersCmd = &cobra.Command{
+	Use:   "trackers",
+	Short: "Show tracker-specific rules",
+	Long:  "Show the rules mkbrr knows for a tracker and the settings it recommends for it.",
+}
+
+var trackersRecommendCmd = &cobra.Command{
+	Use:   "recommend <tracker-url> [flags]",
+	Short: "Show recommended settings for a tracker",
+	Long: `Show the settings mkbrr uses for a tracker: piece length for a content size,
+whether torrents must be private, the default source tag and the .torrent size limit.`,
+	Example: `  mkbrr trackers recommend https://tracker.example.com/announce --size 20GiB
+  mkbrr trackers recommend https://tracker.example.com/announce --size 700MB --format json
+  mkbrr trackers recommend https://tracker.example.com/announce --size 20GiB --files 20000`,
+	Args:                  cobra.ExactArgs(1),
+	RunE:                  runTrackersRecommend,
+	ValidArgsFunction:     completeTrackerURLs,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+}
+
+var trackersCheckCmd = &cobra.Command{
+	Use:   "check <tracker-url>... [flags]",
+	Short: "Check that trackers are reachable",
+	Long: `Check that trackers answer before uploading. HTTP(S) trackers get a plain GET of the
+announce URL, UDP trackers a connect request; nothing is announced. Passkeys are
+masked in the output. Exits with an error if any tracker is unreachable.`,
+	Example: `  mkbrr trackers check https://tracker.example.com/announce/PASSKEY
+  mkbrr trackers check udp://tracker.example.org:1337/announce --timeout 5s --format json`,
+	Args:                  cobra.MinimumNArgs(1),
+	RunE:                  runTrackersCheck,
+	ValidArgsFunction:     completeTrackerURLs,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+}
+
+func init() {
+	trackersCmd.AddCommand(trackersRecommendCmd)
+	trackersCmd.AddCommand(trackersCheckCmd)
+
+	trackersCheckCmd.Flags().DurationVar(&trackersOpts.timeout, "timeout", torrent.DefaultTrackerCheckTimeout, "how long to wait for each tracker")
+	trackersCheckCmd.Flags().StringVarP(&trackersOpts.format, "format", "f", "text", "output format (text, json)")
+	trackersCheckCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} <tracker-url>... [flags]
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
+`)
+
+	trackersRecommendCmd.Flags().StringVarP(&trackersOpts.size, "size", "s", "", "content size to recommend a piece length for, e.g. \"20GiB\" or \"700MB\"")
+	trackersRecommendCmd.Flags().IntVar(&trackersOpts.files, "files", 0, "number of files in the content, large counts raise the piece length as in create")
+	trackersRecommendCmd.Flags().StringVarP(&trackersOpts.format, "format", "f", "text", "output format (text, json)")
+
+	trackersRecommendCmd.SetUsageTemplate(`Usage:
+  {{.CommandPath}} <tracker-url> [flags]
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}
+`)
+}
+
+// trackerRecommendation holds the settings recommended for a tracker
+type trackerRecommendation struct {
+	Tracker         string `json:"tracker"`
+	Known           bool   `json:"known"`
+	Private         bool   `json:"private"`
+	Source          string `json:"source,omitempty"`
+	MaxPieceLength  uint   `json:"maxPieceLengthExp,omitempty"`
+	MaxTorrentSize  uint64 `json:"maxTorrentSize,omitempty"`
+	ContentSize     uint64 `json:"contentSize,omitempty"`
+	PieceLengthExp  uint   `json:"pieceLengthExp,omitempty"`
+	PieceLength     uint64 `json:"pieceLength,omitempty"`
+	PieceLengthRule string `json:"pieceLengthRule,omitempty"`
+}
+
+// recommendForTracker collects the known rules for trackerURL. A zero contentSize
+// leaves out the piece length recommendation.
+func recommendForTracker(trackerURL string, contentSize uint64, fileCount int) trackerRecommendation {
+	rec := trackerRecommendation{
+		Tracker: trackerURL,
+		Private: trackers.RequiresPrivate(trackerURL),
+	}
+	rec.Known = rec.Private
+	rec.Source, _ = trackers.GetTrackerDefaultSource(trackerURL)
+	rec.MaxPieceLength, _ = trackers.GetTrackerMaxPieceLength(trackerURL)
+	rec.MaxTorrentSize, _ = trackers.GetTrackerMaxTorrentSize(trackerURL)
+
+	if contentSize > 0 {
+		rec.ContentSize = contentSize
+		rec.PieceLengthExp = torrent.AutoPieceLengthExp(trackerURL, contentSize, fileCount)
+		rec.PieceLength = uint64(1) << rec.PieceLengthExp
+		switch {
+		case !rec.Known:
+			rec.PieceLengthRule = "default"
+		case trackers.UsesDefaultRanges(trackerURL):
+			rec.PieceLengthRule = "default ranges"
+		default:
+			if _, ok := trackers.GetTrackerPieceSizeExp(trackerURL, contentSize); ok {
+				rec.PieceLengthRule = "tracker ranges"
+			} else {
+				rec.PieceLengthRule = "default"
+			}
+		}
+	}
+	return rec
+}
+
+func runTrackersRecommend(cmd *cobra.Command, args []string) error {
+	switch trackersOpts.format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of text, json", trackersOpts.format)
+	}
+
+	var contentSize uint64
+	if trackersOpts.size != "" {
+		size, err := humanize.ParseBytes(trackersOpts.size)
+		if err != nil || size == 0 {
+			return fmt.Errorf("invalid --size %q: expected a size such as 20GiB", trackersOpts.size)
+		}
+		contentSize = size
+	}
+	if trackersOpts.files < 0 {
+		return fmt.Errorf("invalid --files %d: must not be negative", trackersOpts.files)
+	}
+
+	rec := recommendForTracker(args[0], contentSize, trackersOpts.files)
+
+	out := cmd.OutOrStdout()
+	if trackersOpts.format == "json" {
+		return writeJSON(out, rec)
+	}
+
+	fmt.Fprintf(out, "%s %s\n", cyan("Tracker:"), rec.Tracker)
+	if !rec.Known {
+		fmt.Fprintln(out, "  No tracker-specific rules known, showing defaults")
+	}
+	showPresetField(out, "Private:", fmt.Sprint(rec.Private))
+	showPresetField(out, "Source:", rec.Source)
+	if rec.MaxPieceLength != 0 {
+		showPresetField(out, "Max piece len:", humanize.IBytes(uint64(1)<<rec.MaxPieceLength))
+	}
+	if rec.MaxTorrentSize != 0 {
+		showPresetField(out, "Max .torrent:", humanize.IBytes(rec.MaxTorrentSize))
+	}
+	if rec.ContentSize != 0 {
+		showPresetField(out, "Content size:", humanize.IBytes(rec.ContentSize))
+		showPresetField(out, "Piece length:", fmt.Sprintf("%s (2^%d, %s)", humanize.IBytes(rec.PieceLength), rec.PieceLengthExp, rec.PieceLengthRule))
+	}
+	return nil
+}
+
+func runTrackersCheck(cmd *cobra.Command, args []string) error {
+	switch trackersOpts.format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of text, json", trackersOpts.format)
+	}
+
+	statuses := torrent.CheckTrackers(args, trackersOpts.timeout)
+
+	if trackersOpts.format == "json" {
+		if err := writeJSON(cmd.OutOrStdout(), statuses); err != nil {
+			return err
+		}
+	} else {
+		display := torrent.NewDisplay(torrent.NewFormatter(false))
+		display.SetOutput(cmd.OutOrStdout())
+		display.SetErrorOutput(cmd.ErrOrStderr())
+		display.ShowTrackerStatuses(statuses)
+	}
+
+	unreachable := 0
+	for _, s := range statuses {
+		if !s.Reachable {
+			unreachable++
+		}
+	}
+	if unreachable > 0 {
+		return fmt.Errorf("%d of %d trackers unreachable", unreachable, len(statuses))
+	}
+	return nil
+}
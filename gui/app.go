@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/autobrr/mkbrr/internal/preset"
 	"github.com/autobrr/mkbrr/internal/trackers"
@@ -38,6 +39,30 @@ type ProgressEvent struct {
 	Total     int     `json:"total"`
 	HashRate  float64 `json:"hashRate"`
 	Percent   float64 `json:"percent"`
+	ETA       float64 `json:"eta"` // estimated seconds remaining, 0 until known
+}
+
+// BadPieceEvent is sent to the frontend as "verify:badpiece" when a piece fails verification
+type BadPieceEvent struct {
+	PieceIndex int `json:"pieceIndex"`
+}
+
+// newProgressEvent builds a ProgressEvent, estimating the time remaining
+// from the average rate since start
+func newProgressEvent(start time.Time, completed, total int, hashRate float64) ProgressEvent {
+	ev := ProgressEvent{
+		Completed: completed,
+		Total:     total,
+		HashRate:  hashRate,
+	}
+	if total > 0 {
+		ev.Percent = float64(completed) / float64(total) * 100
+	}
+	if completed > 0 && completed < total {
+		elapsed := time.Since(start).Seconds()
+		ev.ETA = elapsed / float64(completed) * float64(total-completed)
+	}
+	return ev
 }
 
 // CreateRequest represents a torrent creation request from the frontend.
@@ -77,6 +102,9 @@ type TorrentResult struct {
 	Size           int64           `json:"size"`
 	PieceCount     int             `json:"pieceCount"`
 	FileCount      int             `json:"fileCount"`
+	BytesHashed    int64           `json:"bytesHashed"`
+	HashSeconds    float64         `json:"hashSeconds"`
+	HashRate       float64         `json:"hashRate"` // average MiB per second
 	Warning        string          `json:"warning,omitempty"`
 	SeasonPackInfo *SeasonPackInfo `json:"seasonPackInfo,omitempty"`
 }
@@ -93,6 +121,7 @@ type SeasonPackInfo struct {
 
 // InspectResult represents torrent metadata for inspection
 type InspectResult struct {
+	Path         string        `json:"path"`
 	Name         string        `json:"name"`
 	InfoHash     string        `json:"infoHash"`
 	Size         int64         `json:"size"`
@@ -149,21 +178,22 @@ type VerifyResult struct {
 //
 // Optional fields (all others): Only non-empty/non-nil values will be applied
 type ModifyRequest struct {
-	TorrentPath   string   `json:"torrentPath"`   // Required: path to .torrent file to modify
-	TrackerURLs   []string `json:"trackerUrls"`   // Optional: new tracker URLs (replaces existing)
-	WebSeeds      []string `json:"webSeeds"`      // Optional: new web seed URLs
-	Comment       string   `json:"comment"`       // Optional: new comment
-	Source        string   `json:"source"`        // Optional: new source tag
-	IsPrivate     *bool    `json:"isPrivate"`     // Optional: set private flag (nil = unchanged)
-	NoDate        bool     `json:"noDate"`        // Optional: remove creation date
-	NoCreator     bool     `json:"noCreator"`     // Optional: remove creator string
-	Entropy       *bool     `json:"entropy"`      // Optional: add entropy for unique hash
-	SkipPrefix    bool     `json:"skipPrefix"`    // Optional: don't prefix output filename
-	OutputDir     string   `json:"outputDir"`     // Optional: output directory for modified file
-	OutputPattern string   `json:"outputPattern"` // Optional: output filename pattern
-	PresetName    string   `json:"presetName"`    // Optional: preset to apply
-	PresetFile    string   `json:"presetFile"`    // Optional: path to preset file
-	DryRun        bool     `json:"dryRun"`        // Optional: simulate modification without writing
+	TorrentPath    string   `json:"torrentPath"`    // Required: path to .torrent file to modify
+	TrackerURLs    []string `json:"trackerUrls"`    // Optional: new tracker URLs (replaces existing unless AppendTrackers)
+	AppendTrackers bool     `json:"appendTrackers"` // Optional: add TrackerURLs after the existing trackers
+	WebSeeds       []string `json:"webSeeds"`       // Optional: new web seed URLs (replaces existing)
+	Comment        string   `json:"comment"`        // Optional: new comment
+	Source         string   `json:"source"`         // Optional: new source tag
+	IsPrivate      *bool    `json:"isPrivate"`      // Optional: set private flag (nil = unchanged)
+	NoDate         bool     `json:"noDate"`         // Optional: remove creation date
+	NoCreator      bool     `json:"noCreator"`      // Optional: remove creator string
+	Entropy        *bool    `json:"entropy"`        // Optional: add entropy for unique hash
+	SkipPrefix     bool     `json:"skipPrefix"`     // Optional: don't prefix output filename
+	OutputDir      string   `json:"outputDir"`      // Optional: output directory for modified file
+	OutputPattern  string   `json:"outputPattern"`  // Optional: output filename pattern
+	PresetName     string   `json:"presetName"`     // Optional: preset to apply
+	PresetFile     string   `json:"presetFile"`     // Optional: path to preset file
+	DryRun         bool     `json:"dryRun"`         // Optional: simulate modification without writing
 }
 
 // ModifyResult represents the result of torrent modification
@@ -180,6 +210,23 @@ type TrackerInfo struct {
 	HasCustomRules bool   `json:"hasCustomRules"`
 }
 
+// TorrentSizeEstimate is the projected .torrent file size against a tracker's size limit
+type TorrentSizeEstimate struct {
+	PieceLengthExp uint   `json:"pieceLengthExp"` // piece length the torrent ends up with
+	EstimatedSize  uint64 `json:"estimatedSize"`  // piece hashes only, the file list adds to it
+	MaxTorrentSize uint64 `json:"maxTorrentSize"` // 0 if the tracker has no limit
+	Raised         bool   `json:"raised"`         // creation raises the piece length to fit
+	Fits           bool   `json:"fits"`
+	NearLimit      bool   `json:"nearLimit"` // within 10% of the limit
+}
+
+// ValidationRow represents the outcome of one tracker rule check
+type ValidationRow struct {
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"` // pass, warn or fail
+}
+
 // PresetInfo represents a preset configuration
 type PresetInfo struct {
 	Name    string          `json:"name"`
@@ -194,40 +241,58 @@ type PresetsResult struct {
 
 // === File Dialogs ===
 
-// SelectPath opens a native directory picker
-func (a *App) SelectPath() (string, error) {
+// SelectPath opens a native directory picker, starting in defaultDir if it exists
+func (a *App) SelectPath(defaultDir string) (string, error) {
 	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select Content Directory or File",
+		Title:            "Select Content Directory or File",
+		DefaultDirectory: existingDir(defaultDir),
 	})
 }
 
-// SelectFile opens a native file picker
-func (a *App) SelectFile() (string, error) {
+// SelectFile opens a native file picker, starting in defaultDir if it exists
+func (a *App) SelectFile(defaultDir string) (string, error) {
 	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select File",
+		Title:            "Select File",
+		DefaultDirectory: existingDir(defaultDir),
 	})
 }
 
-// SelectTorrentFile opens a native file picker for .torrent files
-func (a *App) SelectTorrentFile() (string, error) {
+// SelectTorrentFile opens a native file picker for .torrent files, starting in defaultDir if it exists
+func (a *App) SelectTorrentFile(defaultDir string) (string, error) {
 	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select Torrent File",
+		Title:            "Select Torrent File",
+		DefaultDirectory: existingDir(defaultDir),
 		Filters: []runtime.FileFilter{
 			{DisplayName: "Torrent Files", Pattern: "*.torrent"},
 		},
 	})
 }
 
-// SelectMultipleTorrentFiles opens a native file picker for multiple .torrent files
-func (a *App) SelectMultipleTorrentFiles() ([]string, error) {
+// SelectMultipleTorrentFiles opens a native file picker for multiple .torrent files,
+// starting in defaultDir if it exists
+func (a *App) SelectMultipleTorrentFiles(defaultDir string) ([]string, error) {
 	return runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
-		Title: "Select Torrent Files",
+		Title:            "Select Torrent Files",
+		DefaultDirectory: existingDir(defaultDir),
 		Filters: []runtime.FileFilter{
 			{DisplayName: "Torrent Files", Pattern: "*.torrent"},
 		},
 	})
 }
 
+// existingDir returns dir if it is an existing directory, otherwise an empty string
+// so the dialog falls back to the platform default. A remembered directory may have
+// been removed since it was last used.
+func existingDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ""
+	}
+	return dir
+}
+
 // === Create Operations ===
 
 // CreateTorrent creates a new torrent file
@@ -282,6 +347,7 @@ func (a *App) CreateTorrent(req CreateRequest) (*TorrentResult, error) {
 		isPrivate = *req.IsPrivate
 	}
 
+	start := time.Now()
 	opts := torrent.CreateOptions{
 		Path:                    req.Path,
 		Name:                    req.Name,
@@ -302,21 +368,13 @@ func (a *App) CreateTorrent(req CreateRequest) (*TorrentResult, error) {
 		IncludePatterns:         req.IncludePatterns,
 		Workers:                 req.Workers,
 		FailOnSeasonPackWarning: req.FailOnSeasonWarning,
+		Force:                   true, // the GUI shows the output path, re-creating replaces it as before
 		Quiet:                   true, // Suppress CLI output
 		ProgressCallback: func(completed, total int, hashRate float64) {
 			if a.ctx == nil {
 				return
 			}
-			percent := 0.0
-			if total > 0 {
-				percent = float64(completed) / float64(total) * 100
-			}
-			runtime.EventsEmit(a.ctx, "create:progress", ProgressEvent{
-				Completed: completed,
-				Total:     total,
-				HashRate:  hashRate,
-				Percent:   percent,
-			})
+			runtime.EventsEmit(a.ctx, "create:progress", newProgressEvent(start, completed, total, hashRate))
 		},
 	}
 
@@ -335,32 +393,19 @@ func (a *App) CreateTorrent(req CreateRequest) (*TorrentResult, error) {
 		return nil, err
 	}
 
-	// Read back the created torrent to get accurate piece/file counts
-	pieceCount := 0
-	fileCount := 1
-	size := info.Size
-	var warning string
-
-	t, err := torrent.LoadFromFile(info.Path)
-	if err != nil {
-		log.Printf("Warning: failed to re-read created torrent for metadata: %v", err)
-		warning = fmt.Sprintf("Created torrent but failed to verify metadata: %v", err)
-	} else {
-		mi := t.GetInfo()
-		pieceCount = mi.NumPieces()
-		size = mi.TotalLength()
-		if len(mi.Files) > 0 {
-			fileCount = len(mi.Files)
-		}
-	}
+	// Create already checked the written torrent parses and returns its counts,
+	// so the pieces blob of a huge torrent isn't read back a second time
+	fileCount := max(info.Files, 1)
 
 	return &TorrentResult{
 		Path:           info.Path,
 		InfoHash:       info.InfoHash,
-		Size:           size,
-		PieceCount:     pieceCount,
+		Size:           info.Size,
+		PieceCount:     info.Pieces,
 		FileCount:      fileCount,
-		Warning:        warning,
+		BytesHashed:    info.Hash.BytesHashed,
+		HashSeconds:    info.Hash.Elapsed.Seconds(),
+		HashRate:       info.Hash.Rate() / (1024 * 1024),
 		SeasonPackInfo: seasonPackInfo,
 	}, nil
 }
@@ -438,6 +483,7 @@ func (a *App) InspectTorrent(path string) (*InspectResult, error) {
 	}
 
 	return &InspectResult{
+		Path:         path,
 		Name:         info.Name,
 		InfoHash:     infoHash,
 		Size:         info.TotalLength(),
@@ -467,6 +513,7 @@ func (a *App) VerifyTorrent(req VerifyRequest) (*VerifyResult, error) {
 		return nil, fmt.Errorf("content path is required")
 	}
 
+	start := time.Now()
 	opts := torrent.VerifyOptions{
 		TorrentPath: req.TorrentPath,
 		ContentPath: req.ContentPath,
@@ -475,16 +522,13 @@ func (a *App) VerifyTorrent(req VerifyRequest) (*VerifyResult, error) {
 			if a.ctx == nil {
 				return
 			}
-			percent := 0.0
-			if total > 0 {
-				percent = float64(completed) / float64(total) * 100
+			runtime.EventsEmit(a.ctx, "verify:progress", newProgressEvent(start, completed, total, hashRate))
+		},
+		BadPieceCallback: func(pieceIndex int) {
+			if a.ctx == nil {
+				return
 			}
-			runtime.EventsEmit(a.ctx, "verify:progress", ProgressEvent{
-				Completed: completed,
-				Total:     total,
-				HashRate:  hashRate, // Already in MiB/s from torrent package
-				Percent:   percent,
-			})
+			runtime.EventsEmit(a.ctx, "verify:badpiece", BadPieceEvent{PieceIndex: pieceIndex})
 		},
 	}
 
@@ -524,21 +568,23 @@ func (a *App) ModifyTorrent(req ModifyRequest) (*ModifyResult, error) {
 	}
 
 	opts := torrent.ModifyOptions{
-		TrackerURLs:   req.TrackerURLs,
-		WebSeeds:      req.WebSeeds,
-		Comment:       req.Comment,
-		Source:        req.Source,
-		IsPrivate:     req.IsPrivate,
-		NoDate:        req.NoDate,
-		NoCreator:     req.NoCreator,
-		Entropy:       req.Entropy,
-		SkipPrefix:    req.SkipPrefix,
-		OutputDir:     outputDir,
-		OutputPattern: req.OutputPattern,
-		PresetName:    req.PresetName,
-		PresetFile:    req.PresetFile,
-		DryRun:        req.DryRun,
-		Quiet:         true,
+		TrackerURLs:    req.TrackerURLs,
+		AppendTrackers: req.AppendTrackers,
+		WebSeeds:       req.WebSeeds,
+		Comment:        req.Comment,
+		Source:         req.Source,
+		IsPrivate:      req.IsPrivate,
+		NoDate:         req.NoDate,
+		NoCreator:      req.NoCreator,
+		Entropy:        req.Entropy,
+		SkipPrefix:     req.SkipPrefix,
+		OutputDir:      outputDir,
+		OutputPattern:  req.OutputPattern,
+		PresetName:     req.PresetName,
+		PresetFile:     req.PresetFile,
+		DryRun:         req.DryRun,
+		Force:          true, // the GUI shows the output path, re-running replaces it as before
+		Quiet:          true,
 	}
 
 	result, err := torrent.ModifyTorrent(req.TorrentPath, opts)
@@ -737,11 +783,59 @@ func (a *App) GetTrackerInfo(url string) *TrackerInfo {
 	}
 }
 
+// ValidateTorrent checks a torrent file against the known rules of a tracker
+func (a *App) ValidateTorrent(path string, trackerURL string) ([]ValidationRow, error) {
+	if trackerURL == "" {
+		return nil, fmt.Errorf("tracker URL is required")
+	}
+
+	t, err := torrent.LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	results := torrent.ValidateForTracker(t.MetaInfo, t.GetInfo(), stat.Size(), trackerURL)
+	rows := make([]ValidationRow, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, ValidationRow{
+			Check:    r.Check,
+			Message:  r.Message,
+			Severity: r.Severity.String(),
+		})
+	}
+	return rows, nil
+}
+
 // GetRecommendedPieceSize returns the recommended piece size for a tracker and content size
 func (a *App) GetRecommendedPieceSize(trackerURL string, contentSize uint64) uint {
 	return torrent.GetRecommendedPieceLengthExp(trackerURL, contentSize)
 }
 
+// EstimateTorrentSize projects the .torrent file size for contentSize bytes of content
+// in fileCount files against trackerURL's size limit, raising the piece length like
+// CreateTorrent does. A pieceLengthExp of 0 means automatic.
+func (a *App) EstimateTorrentSize(trackerURL string, contentSize uint64, fileCount int, pieceLengthExp uint) *TorrentSizeEstimate {
+	if pieceLengthExp == 0 {
+		pieceLengthExp = torrent.AutoPieceLengthExp(trackerURL, contentSize, fileCount)
+	}
+	maxTorrentSize, _ := trackers.GetTrackerMaxTorrentSize(trackerURL)
+	exp, fits := torrent.FitTorrentFileSize(trackerURL, contentSize, pieceLengthExp, nil)
+	size := torrent.EstimateTorrentFileSize(contentSize, exp)
+
+	return &TorrentSizeEstimate{
+		PieceLengthExp: exp,
+		EstimatedSize:  size,
+		MaxTorrentSize: maxTorrentSize,
+		Raised:         exp != pieceLengthExp,
+		Fits:           fits,
+		NearLimit:      maxTorrentSize > 0 && size*10 >= maxTorrentSize*9,
+	}
+}
+
 // GetContentSize returns the total size of the content at the given path
 func (a *App) GetContentSize(path string) (uint64, error) {
 	info, err := os.Stat(path)
@@ -767,6 +861,31 @@ func (a *App) GetContentSize(path string) (uint64, error) {
 	return totalSize, err
 }
 
+// GetContentFileCount returns the number of files at the given path
+func (a *App) GetContentFileCount(path string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if !info.IsDir() {
+		return 1, nil
+	}
+
+	var count int
+	err = filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
 // === Utility Functions ===
 
 // applyPresetToCreateOptions applies preset options to create options
@@ -809,6 +928,10 @@ func applyPresetToCreateOptions(opts *torrent.CreateOptions, presetOpts *preset.
 		pl := presetOpts.PieceLength
 		opts.PieceLengthExp = &pl
 	}
+	if presetOpts.MinPieceLength > 0 && opts.MinPieceLength == nil {
+		minpl := presetOpts.MinPieceLength
+		opts.MinPieceLength = &minpl
+	}
 	if presetOpts.MaxPieceLength > 0 && opts.MaxPieceLength == nil {
 		mpl := presetOpts.MaxPieceLength
 		opts.MaxPieceLength = &mpl
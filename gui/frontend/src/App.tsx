@@ -5,6 +5,7 @@ import { CreatePage } from '@/pages/Create';
 import { InspectPage } from '@/pages/Inspect';
 import { CheckPage } from '@/pages/Check';
 import { ModifyPage } from '@/pages/Modify';
+import { QueuePage } from '@/pages/Queue';
 import { SettingsPage } from '@/pages/Settings';
 import { Toaster } from '@/components/ui/sonner';
 import { ErrorBoundary } from '@/components/ErrorBoundary';
@@ -63,6 +64,7 @@ function App() {
             <Route path="/inspect" element={<InspectPage />} />
             <Route path="/check" element={<CheckPage />} />
             <Route path="/modify" element={<ModifyPage />} />
+            <Route path="/queue" element={<QueuePage />} />
             <Route path="/settings" element={<SettingsPage />} />
           </Route>
         </Routes>
@@ -1,6 +1,6 @@
 import { useState, useEffect } from 'react';
 import { NavLink } from 'react-router-dom';
-import { FilePlus, FileSearch, FileCheck, FileEdit, Settings, Moon, Sun, Monitor, Palette, ExternalLink, Heart } from 'lucide-react';
+import { FilePlus, FileSearch, FileCheck, FileEdit, ListOrdered, Settings, Moon, Sun, Monitor, Palette, ExternalLink, Heart } from 'lucide-react';
 import { cn } from '@/lib/utils';
 import { Button } from '@/components/ui/button';
 import { Separator } from '@/components/ui/separator';
@@ -20,6 +20,7 @@ const navItems = [
   { to: '/inspect', icon: FileSearch, label: 'Inspect' },
   { to: '/check', icon: FileCheck, label: 'Check' },
   { to: '/modify', icon: FileEdit, label: 'Modify' },
+  { to: '/queue', icon: ListOrdered, label: 'Queue' },
 ];
 
 export function AppSidebar() {
@@ -0,0 +1,62 @@
+import { SelectPath, SelectFile, SelectTorrentFile } from '../../wailsjs/go/main/App';
+
+const STORAGE_KEY = 'mkbrr-last-dirs';
+
+// Content dialogs (source paths and .torrent files) and output directory
+// dialogs remember their last directory separately.
+type DirKind = 'content' | 'output';
+
+function loadDirs(): Partial<Record<DirKind, string>> {
+  try {
+    const saved = localStorage.getItem(STORAGE_KEY);
+    const parsed = saved ? JSON.parse(saved) : null;
+    return parsed && typeof parsed === 'object' ? parsed : {};
+  } catch {
+    return {};
+  }
+}
+
+function rememberDir(kind: DirKind, dir: string) {
+  if (!dir) return;
+  try {
+    localStorage.setItem(STORAGE_KEY, JSON.stringify({ ...loadDirs(), [kind]: dir }));
+  } catch {
+    // Ignore – non-critical.
+  }
+}
+
+function parentDir(path: string): string {
+  const trimmed = path.replace(/[\\/]+$/, '');
+  const i = Math.max(trimmed.lastIndexOf('/'), trimmed.lastIndexOf('\\'));
+  if (i < 0) return '';
+  // Keep the separator for filesystem roots such as "/" or "C:\".
+  return i === 0 || trimmed[i - 1] === ':' ? trimmed.slice(0, i + 1) : trimmed.slice(0, i);
+}
+
+/** Pick a content file or folder; the next dialog opens beside it. */
+export async function selectContentDirectory(): Promise<string> {
+  const selected = await SelectPath(loadDirs().content ?? '');
+  if (selected) rememberDir('content', parentDir(selected));
+  return selected;
+}
+
+/** Pick a single content file; the next dialog opens in its folder. */
+export async function selectContentFile(): Promise<string> {
+  const selected = await SelectFile(loadDirs().content ?? '');
+  if (selected) rememberDir('content', parentDir(selected));
+  return selected;
+}
+
+/** Pick a .torrent file; shares the last directory with content dialogs. */
+export async function selectTorrentFile(): Promise<string> {
+  const selected = await SelectTorrentFile(loadDirs().content ?? '');
+  if (selected) rememberDir('content', parentDir(selected));
+  return selected;
+}
+
+/** Pick an output directory; the next output dialog reopens it. */
+export async function selectOutputDirectory(): Promise<string> {
+  const selected = await SelectPath(loadDirs().output ?? '');
+  if (selected) rememberDir('output', selected);
+  return selected;
+}
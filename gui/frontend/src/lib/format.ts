@@ -0,0 +1,9 @@
+/** Format an estimated number of seconds remaining, e.g. "45s", "3m 07s" or "1h 05m". */
+export function formatETA(seconds: number): string {
+  const s = Math.ceil(seconds);
+  if (s < 60) return `${s}s`;
+  const h = Math.floor(s / 3600);
+  const m = Math.floor((s % 3600) / 60);
+  const rest = String(s % 60).padStart(2, '0');
+  return h > 0 ? `${h}h ${String(m).padStart(2, '0')}m` : `${m}m ${rest}s`;
+}
@@ -7,7 +7,9 @@ import { Label } from '@/components/ui/label';
 import { Progress } from '@/components/ui/progress';
 import { Tooltip, TooltipContent, TooltipTrigger } from '@/components/ui/tooltip';
 import { FolderOpen, File, Loader2, CheckCircle, XCircle, X } from 'lucide-react';
-import { SelectTorrentFile, SelectPath, SelectFile, VerifyTorrent } from '../../wailsjs/go/main/App';
+import { VerifyTorrent } from '../../wailsjs/go/main/App';
+import { selectTorrentFile, selectContentDirectory, selectContentFile } from '@/lib/dialogs';
+import { formatETA } from '@/lib/format';
 import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { main } from '../../wailsjs/go/models';
 import { useFileDrop } from '@/hooks/useFileDrop';
@@ -21,8 +23,16 @@ interface ProgressEvent {
   total: number;
   hashRate: number;
   percent: number;
+  eta: number;
 }
 
+interface BadPieceEvent {
+  pieceIndex: number;
+}
+
+// Bad pieces listed while verifying; the rest are only counted
+const MAX_LIVE_BAD_PIECES = 20;
+
 function formatHashRate(mibPerSec: number): string {
   if (mibPerSec >= 1024) {
     return `${(mibPerSec / 1024).toFixed(2)} GiB/s`;
@@ -70,6 +80,8 @@ export function CheckPage() {
   const [contentPath, setContentPath] = useState(savedState.contentPath ?? '');
   const [isVerifying, setIsVerifying] = useState(false);
   const [progress, setProgress] = useState<ProgressEvent | null>(null);
+  const [badPieces, setBadPieces] = useState<number[]>([]);
+  const [badPieceCount, setBadPieceCount] = useState(0);
   const [result, setResult] = useState<VerifyResult | null>(null);
   const [error, setError] = useState('');
 
@@ -94,8 +106,15 @@ export function CheckPage() {
     const cancel = EventsOn('verify:progress', (data: ProgressEvent) => {
       setProgress(data);
     });
+    const cancelBadPiece = EventsOn('verify:badpiece', (data: BadPieceEvent) => {
+      setBadPieceCount((count) => count + 1);
+      setBadPieces((pieces) =>
+        pieces.length < MAX_LIVE_BAD_PIECES ? [...pieces, data.pieceIndex] : pieces
+      );
+    });
     return () => {
       cancel();
+      cancelBadPiece();
     };
   }, []);
 
@@ -109,7 +128,7 @@ export function CheckPage() {
 
   const handleSelectTorrent = async () => {
     try {
-      const path = await SelectTorrentFile();
+      const path = await selectTorrentFile();
       if (path) {
         setTorrentPath(path);
       }
@@ -120,7 +139,7 @@ export function CheckPage() {
 
   const handleSelectContentFolder = async () => {
     try {
-      const path = await SelectPath();
+      const path = await selectContentDirectory();
       if (path) {
         setContentPath(path);
       }
@@ -131,7 +150,7 @@ export function CheckPage() {
 
   const handleSelectContentFile = async () => {
     try {
-      const path = await SelectFile();
+      const path = await selectContentFile();
       if (path) {
         setContentPath(path);
       }
@@ -149,6 +168,8 @@ export function CheckPage() {
     setError('');
     setResult(null);
     setProgress(null);
+    setBadPieces([]);
+    setBadPieceCount(0);
     setIsVerifying(true);
 
     try {
@@ -272,8 +293,18 @@ export function CheckPage() {
               <Progress value={progress.percent} />
               <div className="flex justify-between text-xs text-muted-foreground">
                 <span>{progress.completed} / {progress.total} pieces</span>
+                {progress.eta > 0 && <span>ETA {formatETA(progress.eta)}</span>}
                 <span>{formatHashRate(progress.hashRate)}</span>
               </div>
+              {badPieceCount > 0 && (
+                <div className="text-xs">
+                  <span className="text-destructive font-medium">{badPieceCount} bad pieces so far: </span>
+                  <span className="font-mono text-muted-foreground">
+                    {[...badPieces].sort((a, b) => a - b).join(', ')}
+                    {badPieceCount > badPieces.length && ', ...'}
+                  </span>
+                </div>
+              )}
             </CardContent>
           </Card>
         )}
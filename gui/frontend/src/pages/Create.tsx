@@ -12,7 +12,9 @@ import { Progress } from '@/components/ui/progress';
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
 import { Tooltip, TooltipContent, TooltipTrigger } from '@/components/ui/tooltip';
 import { FolderOpen, File, Plus, X, Loader2, ChevronDown, Sparkles, FileSearch, AlertTriangle } from 'lucide-react';
-import { SelectPath, SelectFile, CreateTorrent, ListPresets, GetPreset, GetTrackerInfo, GetContentSize, GetRecommendedPieceSize, InspectTorrent } from '../../wailsjs/go/main/App';
+import { CreateTorrent, ListPresets, GetPreset, GetTrackerInfo, GetContentSize, GetContentFileCount, GetRecommendedPieceSize, EstimateTorrentSize, InspectTorrent } from '../../wailsjs/go/main/App';
+import { selectContentDirectory, selectContentFile, selectOutputDirectory } from '@/lib/dialogs';
+import { formatETA } from '@/lib/format';
 import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { getEffectiveWorkers } from './Settings';
 import { useFileDrop } from '@/hooks/useFileDrop';
@@ -25,12 +27,14 @@ type CreateRequest = main.CreateRequest;
 type TorrentResultType = main.TorrentResult;
 type PresetOptions = presetTypes.Options;
 type TrackerInfoType = main.TrackerInfo;
+type TorrentSizeEstimateType = main.TorrentSizeEstimate;
 
 interface ProgressEvent {
   completed: number;
   total: number;
   hashRate: number;
   percent: number;
+  eta: number;
 }
 
 function formatHashRate(mibPerSec: number): string {
@@ -163,12 +167,20 @@ export function CreatePage() {
   const [advancedOpen, setAdvancedOpen] = useState(false);
   const [trackerInfo, setTrackerInfo] = useState<TrackerInfoType | null>(null);
   const [contentSize, setContentSize] = useState<number>(0);
+  const [fileCount, setFileCount] = useState<number>(0);
   const [recommendedPieceSize, setRecommendedPieceSize] = useState<number>(0);
+  const [sizeEstimate, setSizeEstimate] = useState<TorrentSizeEstimateType | null>(null);
   const [dialogOpen, setDialogOpen] = useState(false);
 
-  // Drag-and-drop: accept any file or folder and use it as the source path.
+  // Drag-and-drop: accept a file or folder and use it as the source path.
+  // A dropped .torrent is almost always meant for another page, so reject it.
   const { isDragging } = useFileDrop((paths) => {
-    setPath(paths[0]);
+    const dropped = paths[0];
+    if (dropped.toLowerCase().endsWith('.torrent')) {
+      toast.error('Drop the content to create a torrent from, not a .torrent file');
+      return;
+    }
+    setPath(dropped);
     toast.success('Path set from dropped item');
   });
 
@@ -250,6 +262,7 @@ export function CreatePage() {
   useEffect(() => {
     if (!path) {
       setContentSize(0);
+      setFileCount(0);
       return;
     }
 
@@ -257,9 +270,11 @@ export function CreatePage() {
       try {
         const size = await GetContentSize(path);
         setContentSize(size);
+        setFileCount(await GetContentFileCount(path));
       } catch (e) {
         toast.error('Failed to get content size: ' + String(e));
         setContentSize(0);
+        setFileCount(0);
       }
     };
 
@@ -293,6 +308,26 @@ export function CreatePage() {
     calculatePieceSize();
   }, [trackerInfo, contentSize, trackers]);
 
+  // Project the .torrent size against the size limit of the first tracker, the one
+  // creation checks, so a torrent that is too large is caught before hashing
+  useEffect(() => {
+    const tracker = trackers.find(t => t.trim() !== '');
+    if (!trackerInfo?.maxTorrentSize || !tracker || contentSize === 0) {
+      setSizeEstimate(null);
+      return;
+    }
+
+    const estimate = async () => {
+      try {
+        const result = await EstimateTorrentSize(tracker, contentSize, fileCount, pieceLengthExp);
+        setSizeEstimate(result.maxTorrentSize > 0 ? result : null);
+      } catch {
+        setSizeEstimate(null);
+      }
+    };
+    estimate();
+  }, [trackerInfo, contentSize, fileCount, trackers, pieceLengthExp]);
+
   // Reset piece length if it exceeds tracker's max
   useEffect(() => {
     if (trackerInfo?.maxPieceLength && pieceLengthExp > trackerInfo.maxPieceLength) {
@@ -302,7 +337,7 @@ export function CreatePage() {
 
   const handleSelectFolder = async () => {
     try {
-      const selected = await SelectPath();
+      const selected = await selectContentDirectory();
       if (selected) {
         setPath(selected);
       }
@@ -313,7 +348,7 @@ export function CreatePage() {
 
   const handleSelectFile = async () => {
     try {
-      const selected = await SelectFile();
+      const selected = await selectContentFile();
       if (selected) {
         setPath(selected);
       }
@@ -324,7 +359,7 @@ export function CreatePage() {
 
   const handleSelectOutputDir = async () => {
     try {
-      const selected = await SelectPath();
+      const selected = await selectOutputDirectory();
       if (selected) {
         setOutputDir(selected);
       }
@@ -348,6 +383,7 @@ export function CreatePage() {
     setError('');
     setTrackerInfo(null);
     setContentSize(0);
+    setFileCount(0);
     setRecommendedPieceSize(0);
     clearFormState(); // Also clear localStorage
   };
@@ -667,6 +703,18 @@ export function CreatePage() {
                       <span>Content: <span className="font-medium text-foreground">{formatBytes(contentSize)}</span></span>
                     )}
                   </div>
+                  {sizeEstimate && (!sizeEstimate.fits || sizeEstimate.raised || sizeEstimate.nearLimit) && (
+                    <div className={`flex items-start gap-2 mt-2 text-xs ${sizeEstimate.fits ? 'text-amber-600 dark:text-amber-400' : 'text-destructive'}`}>
+                      <AlertTriangle className="h-3.5 w-3.5 mt-0.5 flex-shrink-0" />
+                      <span>
+                        {!sizeEstimate.fits
+                          ? `The .torrent file will be at least ${formatBytes(sizeEstimate.estimatedSize)}, over the ${formatBytes(sizeEstimate.maxTorrentSize)} limit even with ${formatPieceSize(sizeEstimate.pieceLengthExp)} pieces. Creation will fail after hashing.`
+                          : sizeEstimate.raised
+                            ? `The .torrent file would exceed the ${formatBytes(sizeEstimate.maxTorrentSize)} limit, so the piece size will be raised to ${formatPieceSize(sizeEstimate.pieceLengthExp)} (about ${formatBytes(sizeEstimate.estimatedSize)}).`
+                            : `The .torrent file will be at least ${formatBytes(sizeEstimate.estimatedSize)}, close to the ${formatBytes(sizeEstimate.maxTorrentSize)} limit. Many files can push it over.`}
+                      </span>
+                    </div>
+                  )}
                 </div>
               </div>
             )}
@@ -826,6 +874,7 @@ export function CreatePage() {
                 {progress && (
                   <div className="flex justify-between text-xs text-muted-foreground">
                     <span>{progress.completed} / {progress.total} pieces</span>
+                    {progress.eta > 0 && <span>ETA {formatETA(progress.eta)}</span>}
                     <span>{formatHashRate(progress.hashRate)}</span>
                   </div>
                 )}
@@ -881,6 +930,12 @@ export function CreatePage() {
                 <span>{result.pieceCount}</span>
                 <span className="text-muted-foreground">Files</span>
                 <span>{result.fileCount}</span>
+                {result.bytesHashed > 0 && (
+                  <>
+                    <span className="text-muted-foreground">Hashed</span>
+                    <span>{formatBytes(result.bytesHashed)} in {result.hashSeconds.toFixed(1)}s ({formatHashRate(result.hashRate)})</span>
+                  </>
+                )}
               </div>
               <DialogFooter>
                 <Button variant="outline" onClick={handleInspectResult}>
@@ -4,8 +4,11 @@ import { Button } from '@/components/ui/button';
 import { Card, CardContent } from '@/components/ui/card';
 import { Collapsible, CollapsibleContent, CollapsibleTrigger } from '@/components/ui/collapsible';
 import { Input } from '@/components/ui/input';
-import { FileSearch, FolderOpen, File, Folder, Loader2, ChevronDown, ChevronRight, Lock, Globe, Copy, Check, RotateCcw, Search, X, ChevronsUpDown, Tag, User, Calendar } from 'lucide-react';
-import { SelectTorrentFile, InspectTorrent } from '../../wailsjs/go/main/App';
+import { Label } from '@/components/ui/label';
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
+import { FileSearch, FolderOpen, File, Folder, Loader2, ChevronDown, ChevronRight, Lock, Globe, Copy, Check, RotateCcw, Search, X, ChevronsUpDown, Tag, User, Calendar, ShieldCheck } from 'lucide-react';
+import { InspectTorrent, ValidateTorrent, ListPresets, GetPreset } from '../../wailsjs/go/main/App';
+import { selectTorrentFile } from '@/lib/dialogs';
 import { main } from '../../wailsjs/go/models';
 import { useFileDrop } from '@/hooks/useFileDrop';
 import { DropOverlay } from '@/components/ui/drop-overlay';
@@ -13,6 +16,7 @@ import { DropOverlay } from '@/components/ui/drop-overlay';
 type InspectResult = main.InspectResult;
 type FileInfo = main.FileInfo;
 type TrackerTier = main.TrackerTier;
+type ValidationRow = main.ValidationRow;
 
 const STORAGE_KEY = 'mkbrr-inspect-state';
 
@@ -34,6 +38,7 @@ function sanitizeInspectResult(info: unknown): InspectResult | null {
     : [];
 
   return {
+    path: toStr(i.path),
     name: toStr(i.name),
     infoHash: toStr(i.infoHash),
     size: toNum(i.size),
@@ -382,6 +387,127 @@ function MetadataBadge({ icon, label, value, copyable }: MetadataBadgeProps) {
   );
 }
 
+const severityStyles: Record<string, string> = {
+  pass: 'bg-emerald-500/15 text-emerald-600 dark:text-emerald-400',
+  warn: 'bg-amber-500/15 text-amber-600 dark:text-amber-400',
+  fail: 'bg-destructive/15 text-destructive',
+};
+
+function ValidationPanel({ torrentInfo }: { torrentInfo: InspectResult }) {
+  const [presets, setPresets] = useState<string[]>([]);
+  const [presetName, setPresetName] = useState('');
+  const [trackerURL, setTrackerURL] = useState(torrentInfo.trackers?.[0] ?? '');
+  const [rows, setRows] = useState<ValidationRow[] | null>(null);
+  const [isValidating, setIsValidating] = useState(false);
+
+  useEffect(() => {
+    ListPresets().then(setPresets).catch((e) => console.error('Failed to load presets:', e));
+  }, []);
+
+  // Reset when a different torrent is inspected
+  useEffect(() => {
+    setTrackerURL(torrentInfo.trackers?.[0] ?? '');
+    setPresetName('');
+    setRows(null);
+  }, [torrentInfo.infoHash]);
+
+  const handlePresetChange = async (value: string) => {
+    setRows(null);
+    if (value === 'none') {
+      setPresetName('');
+      setTrackerURL(torrentInfo.trackers?.[0] ?? '');
+      return;
+    }
+
+    setPresetName(value);
+    try {
+      const preset = await GetPreset(value);
+      const trackerList = preset?.trackers || (preset as any)?.Trackers;
+      if (trackerList && trackerList.length > 0) {
+        setTrackerURL(trackerList[0]);
+      } else {
+        toast.error(`Preset "${value}" has no trackers`);
+      }
+    } catch (e) {
+      toast.error('Failed to load preset: ' + String(e));
+    }
+  };
+
+  const handleValidate = async () => {
+    if (!torrentInfo.path) {
+      toast.error('Select the torrent again to validate it');
+      return;
+    }
+    try {
+      setIsValidating(true);
+      setRows(await ValidateTorrent(torrentInfo.path, trackerURL.trim()));
+    } catch (e) {
+      toast.error(String(e));
+      setRows(null);
+    } finally {
+      setIsValidating(false);
+    }
+  };
+
+  return (
+    <div className="px-5 py-3 border-b space-y-3">
+      <div className="grid grid-cols-[200px_1fr_auto] gap-2 items-end">
+        <div className="space-y-1.5">
+          <Label className="text-xs">Preset</Label>
+          <Select value={presetName} onValueChange={handlePresetChange}>
+            <SelectTrigger className="h-8 text-sm">
+              <SelectValue placeholder="Select a preset" />
+            </SelectTrigger>
+            <SelectContent>
+              <SelectItem value="none">None</SelectItem>
+              {presets.map((p) => (
+                <SelectItem key={p} value={p}>{p}</SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+        </div>
+        <div className="space-y-1.5">
+          <Label className="text-xs">Tracker URL</Label>
+          <Input
+            value={trackerURL}
+            onChange={(e) => {
+              setTrackerURL(e.target.value);
+              setRows(null);
+            }}
+            placeholder="https://tracker.example.com/announce"
+            className="h-8 text-sm font-mono"
+          />
+        </div>
+        <Button size="sm" className="h-8" onClick={handleValidate} disabled={isValidating || !trackerURL.trim()}>
+          {isValidating ? (
+            <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+          ) : (
+            <ShieldCheck className="mr-2 h-4 w-4" />
+          )}
+          Validate
+        </Button>
+      </div>
+
+      {rows && rows.length === 0 && (
+        <p className="text-sm text-muted-foreground">No rules are known for this tracker</p>
+      )}
+      {rows && rows.length > 0 && (
+        <div className="space-y-1.5">
+          {rows.map((row, i) => (
+            <div key={i} className="flex items-start gap-2 text-sm">
+              <span className={`px-1.5 py-0.5 rounded text-xs font-semibold uppercase w-12 text-center flex-shrink-0 ${severityStyles[row.severity] ?? 'bg-muted text-muted-foreground'}`}>
+                {row.severity}
+              </span>
+              <span className="font-medium w-32 flex-shrink-0">{row.check}</span>
+              <span className="text-muted-foreground break-all">{row.message}</span>
+            </div>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+}
+
 function getTierLabel(tier: number): string {
   if (tier === 0) return 'Primary';
   return `Backup ${tier}`;
@@ -393,6 +519,7 @@ export function InspectPage() {
   const [isLoading, setIsLoading] = useState(false);
   const [trackersOpen, setTrackersOpen] = useState(true);
   const [filesOpen, setFilesOpen] = useState(true);
+  const [validationOpen, setValidationOpen] = useState(false);
 
   // Drag-and-drop: accept .torrent files and inspect them immediately.
   const { isDragging } = useFileDrop(async (paths) => {
@@ -431,7 +558,7 @@ export function InspectPage() {
     try {
       setError('');
       setIsLoading(true);
-      const path = await SelectTorrentFile();
+      const path = await selectTorrentFile();
       if (path) {
         const info = await InspectTorrent(path);
         setTorrentInfo(info);
@@ -627,6 +754,19 @@ export function InspectPage() {
                 </Collapsible>
               )}
 
+              {/* Tracker validation section */}
+              <Collapsible open={validationOpen} onOpenChange={setValidationOpen}>
+                <CollapsibleTrigger asChild>
+                  <div className="flex items-center justify-between px-5 py-2.5 border-b cursor-pointer hover:bg-muted/50 transition-colors">
+                    <span className="text-sm font-medium">Tracker validation</span>
+                    <ChevronDown className={`h-4 w-4 text-muted-foreground transition-transform ${validationOpen ? 'rotate-180' : ''}`} />
+                  </div>
+                </CollapsibleTrigger>
+                <CollapsibleContent>
+                  <ValidationPanel torrentInfo={torrentInfo} />
+                </CollapsibleContent>
+              </Collapsible>
+
               {/* Files section */}
               {torrentInfo.files && torrentInfo.files.length > 0 && (
                 <Collapsible open={filesOpen} onOpenChange={setFilesOpen}>
@@ -9,7 +9,8 @@ import { Label } from '@/components/ui/label';
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
 import { Switch } from '@/components/ui/switch';
 import { FolderOpen, Plus, X, Loader2, ChevronDown, FileSearch } from 'lucide-react';
-import { SelectTorrentFile, SelectPath, ModifyTorrent, ListPresets, GetPreset, InspectTorrent } from '../../wailsjs/go/main/App';
+import { ModifyTorrent, ListPresets, GetPreset, InspectTorrent } from '../../wailsjs/go/main/App';
+import { selectTorrentFile, selectOutputDirectory } from '@/lib/dialogs';
 import { useFileDrop } from '@/hooks/useFileDrop';
 import { DropOverlay } from '@/components/ui/drop-overlay';
 
@@ -32,6 +33,8 @@ interface ModifyFormState {
   torrentPath: string;
   outputDir: string;
   trackers: string[];
+  appendTrackers: boolean;
+  webSeeds: string[];
   setPrivate: boolean | undefined;
   source: string;
   comment: string;
@@ -76,6 +79,10 @@ export function ModifyPage() {
   const [trackers, setTrackers] = useState<string[]>(
     Array.isArray(savedState.trackers) && savedState.trackers.length > 0 ? savedState.trackers : ['']
   );
+  const [appendTrackers, setAppendTrackers] = useState(savedState.appendTrackers ?? false);
+  const [webSeeds, setWebSeeds] = useState<string[]>(
+    Array.isArray(savedState.webSeeds) && savedState.webSeeds.length > 0 ? savedState.webSeeds : ['']
+  );
   const [setPrivate, setSetPrivate] = useState<boolean | undefined>(savedState.setPrivate);
   const [source, setSource] = useState(savedState.source ?? '');
   const [comment, setComment] = useState(savedState.comment ?? '');
@@ -111,6 +118,8 @@ export function ModifyPage() {
       torrentPath,
       outputDir,
       trackers,
+      appendTrackers,
+      webSeeds,
       setPrivate,
       source,
       comment,
@@ -118,12 +127,14 @@ export function ModifyPage() {
       noCreator,
       presetName,
     });
-  }, [torrentPath, outputDir, trackers, setPrivate, source, comment, noDate, noCreator, presetName]);
+  }, [torrentPath, outputDir, trackers, appendTrackers, webSeeds, setPrivate, source, comment, noDate, noCreator, presetName]);
 
   const handleReset = () => {
     setTorrentPath('');
     setOutputDir('');
     setTrackers(['']);
+    setAppendTrackers(false);
+    setWebSeeds(['']);
     setSetPrivate(undefined);
     setSource('');
     setComment('');
@@ -163,7 +174,7 @@ export function ModifyPage() {
 
   const handleSelectInput = async () => {
     try {
-      const path = await SelectTorrentFile();
+      const path = await selectTorrentFile();
       if (path) {
         setTorrentPath(path);
       }
@@ -174,7 +185,7 @@ export function ModifyPage() {
 
   const handleSelectOutputDir = async () => {
     try {
-      const path = await SelectPath();
+      const path = await selectOutputDirectory();
       if (path) {
         setOutputDir(path);
       }
@@ -197,6 +208,20 @@ export function ModifyPage() {
     setTrackers(newTrackers);
   };
 
+  const addWebSeed = () => {
+    setWebSeeds([...webSeeds, '']);
+  };
+
+  const removeWebSeed = (index: number) => {
+    setWebSeeds(webSeeds.filter((_, i) => i !== index));
+  };
+
+  const updateWebSeed = (index: number, value: string) => {
+    const newWebSeeds = [...webSeeds];
+    newWebSeeds[index] = value;
+    setWebSeeds(newWebSeeds);
+  };
+
   const handlePrivateChange = (value: string) => {
     if (value === 'unchanged') {
       setSetPrivate(undefined);
@@ -230,7 +255,8 @@ export function ModifyPage() {
       const req: ModifyRequest = {
         torrentPath,
         trackerUrls: trackers.filter(t => t.trim() !== ''),
-        webSeeds: [],
+        appendTrackers,
+        webSeeds: webSeeds.filter(w => w.trim() !== ''),
         isPrivate: setPrivate,
         source,
         comment,
@@ -317,7 +343,7 @@ export function ModifyPage() {
 
             {/* Trackers */}
             <div className="space-y-1.5">
-              <Label>Add Trackers</Label>
+              <Label>Trackers</Label>
               <div className="space-y-2">
                 {trackers.map((tracker, index) => (
                   <div key={index} className="flex gap-2">
@@ -343,6 +369,19 @@ export function ModifyPage() {
                   Add Tracker
                 </Button>
               </div>
+              <div className="flex items-center gap-2 pt-1">
+                <Switch
+                  id="appendTrackers"
+                  checked={appendTrackers}
+                  onCheckedChange={setAppendTrackers}
+                />
+                <Label htmlFor="appendTrackers" className="text-sm">Append to existing trackers</Label>
+              </div>
+              <p className="text-xs text-muted-foreground">
+                {appendTrackers
+                  ? 'New trackers are added after the existing ones'
+                  : 'New trackers replace the existing ones; leave empty to keep them'}
+              </p>
             </div>
 
             {/* Private Flag */}
@@ -449,6 +488,36 @@ export function ModifyPage() {
                   </div>
                 </div>
 
+                {/* Web Seeds */}
+                <div className="space-y-1.5">
+                  <Label>Web Seeds</Label>
+                  <div className="space-y-2">
+                    {webSeeds.map((webSeed, index) => (
+                      <div key={index} className="flex gap-2">
+                        <Input
+                          value={webSeed}
+                          onChange={(e) => updateWebSeed(index, e.target.value)}
+                          placeholder="Leave empty to keep unchanged"
+                          className="flex-1"
+                        />
+                        {webSeeds.length > 1 && (
+                          <Button
+                            variant="outline"
+                            size="icon"
+                            onClick={() => removeWebSeed(index)}
+                          >
+                            <X className="h-4 w-4" />
+                          </Button>
+                        )}
+                      </div>
+                    ))}
+                    <Button variant="outline" size="sm" onClick={addWebSeed}>
+                      <Plus className="mr-2 h-4 w-4" />
+                      Add Web Seed
+                    </Button>
+                  </div>
+                </div>
+
                 {/* Toggles */}
                 <div className="flex flex-wrap gap-6">
                   <div className="flex items-center gap-2">
@@ -0,0 +1,416 @@
+import { useState, useEffect, useRef } from 'react';
+import { toast } from 'sonner';
+import { Button } from '@/components/ui/button';
+import { Card, CardContent } from '@/components/ui/card';
+import { Input } from '@/components/ui/input';
+import { Label } from '@/components/ui/label';
+import { Switch } from '@/components/ui/switch';
+import { Progress } from '@/components/ui/progress';
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
+import { FolderOpen, Plus, X, Loader2, Play, Square, Trash2, CheckCircle2, XCircle, Circle, ListOrdered } from 'lucide-react';
+import { CreateTorrent, ListPresets, GetPreset } from '../../wailsjs/go/main/App';
+import { selectContentDirectory, selectOutputDirectory } from '@/lib/dialogs';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import { main } from '../../wailsjs/go/models';
+import { getEffectiveWorkers } from './Settings';
+import { useFileDrop } from '@/hooks/useFileDrop';
+import { DropOverlay } from '@/components/ui/drop-overlay';
+
+type CreateRequest = main.CreateRequest;
+
+interface ProgressEvent {
+  completed: number;
+  total: number;
+  hashRate: number;
+  percent: number;
+  eta: number;
+}
+
+type ItemStatus = 'pending' | 'running' | 'done' | 'failed';
+
+interface QueueItem {
+  id: number;
+  path: string;
+  status: ItemStatus;
+  output?: string;
+  error?: string;
+}
+
+const STORAGE_KEY = 'mkbrr-queue-settings';
+
+// Shared settings that persist across navigation and restarts
+interface QueueSettings {
+  presetName: string;
+  tracker: string;
+  isPrivate: boolean;
+  outputDir: string;
+}
+
+function loadQueueSettings(): Partial<QueueSettings> {
+  try {
+    const saved = localStorage.getItem(STORAGE_KEY);
+    const parsed = saved ? JSON.parse(saved) : null;
+    return parsed && typeof parsed === 'object' ? parsed : {};
+  } catch (e) {
+    console.error('Failed to load queue settings from localStorage:', e);
+    return {};
+  }
+}
+
+function saveQueueSettings(settings: QueueSettings) {
+  try {
+    localStorage.setItem(STORAGE_KEY, JSON.stringify(settings));
+  } catch (e) {
+    console.error('Failed to save queue settings to localStorage:', e);
+  }
+}
+
+function baseName(path: string): string {
+  const parts = path.split(/[\\/]/).filter(Boolean);
+  return parts[parts.length - 1] ?? path;
+}
+
+function StatusIcon({ status }: { status: ItemStatus }) {
+  switch (status) {
+    case 'running':
+      return <Loader2 className="h-4 w-4 animate-spin text-primary flex-shrink-0" />;
+    case 'done':
+      return <CheckCircle2 className="h-4 w-4 text-emerald-500 flex-shrink-0" />;
+    case 'failed':
+      return <XCircle className="h-4 w-4 text-destructive flex-shrink-0" />;
+    default:
+      return <Circle className="h-4 w-4 text-muted-foreground flex-shrink-0" />;
+  }
+}
+
+export function QueuePage() {
+  const [items, setItems] = useState<QueueItem[]>([]);
+  const [presets, setPresets] = useState<string[]>([]);
+  const savedSettings = loadQueueSettings();
+  const [presetName, setPresetName] = useState(savedSettings.presetName ?? '');
+  const [tracker, setTracker] = useState(savedSettings.tracker ?? '');
+  const [isPrivate, setIsPrivate] = useState(savedSettings.isPrivate ?? true);
+  const [outputDir, setOutputDir] = useState(savedSettings.outputDir ?? '');
+  const [isRunning, setIsRunning] = useState(false);
+  const [itemPercent, setItemPercent] = useState(0);
+
+  const nextId = useRef(1);
+  // Checked between items; the torrent being hashed always finishes.
+  const stopRequested = useRef(false);
+
+  const addPaths = (paths: string[]) => {
+    const existing = new Set(items.map((i) => i.path));
+    const fresh = paths.filter((p) => !existing.has(p));
+    if (fresh.length === 0) return;
+    setItems((prev) => [
+      ...prev,
+      ...fresh.map((path) => ({ id: nextId.current++, path, status: 'pending' as ItemStatus })),
+    ]);
+  };
+
+  // Drag-and-drop: every dropped file or folder becomes a queue item.
+  const { isDragging } = useFileDrop((paths) => {
+    const content = paths.filter((p) => !p.toLowerCase().endsWith('.torrent'));
+    if (content.length < paths.length) {
+      toast.error('Skipped dropped .torrent files');
+    }
+    addPaths(content);
+  });
+
+  useEffect(() => {
+    ListPresets().then((names) => setPresets(names ?? [])).catch((e) => console.error('Failed to load presets:', e));
+  }, []);
+
+  useEffect(() => {
+    saveQueueSettings({ presetName, tracker, isPrivate, outputDir });
+  }, [presetName, tracker, isPrivate, outputDir]);
+
+  useEffect(() => {
+    const cancel = EventsOn('create:progress', (data: ProgressEvent) => {
+      setItemPercent(data.percent);
+    });
+    return () => {
+      cancel();
+      // Leaving the page stops the queue after the current item.
+      stopRequested.current = true;
+    };
+  }, []);
+
+  const handlePresetChange = async (value: string) => {
+    if (value === 'none') {
+      setPresetName('');
+      return;
+    }
+
+    setPresetName(value);
+    try {
+      const preset = await GetPreset(value);
+      const trackerList = preset?.trackers || (preset as any)?.Trackers;
+      const privateVal = preset?.private ?? (preset as any)?.Private;
+      if (trackerList && trackerList.length > 0) setTracker(trackerList[0]);
+      if (privateVal !== undefined) setIsPrivate(privateVal);
+    } catch (e) {
+      toast.error('Failed to load preset: ' + String(e));
+    }
+  };
+
+  const handleAddPath = async () => {
+    try {
+      const selected = await selectContentDirectory();
+      if (selected) addPaths([selected]);
+    } catch (e) {
+      toast.error(String(e));
+    }
+  };
+
+  const handleSelectOutputDir = async () => {
+    try {
+      const selected = await selectOutputDirectory();
+      if (selected) setOutputDir(selected);
+    } catch (e) {
+      toast.error(String(e));
+    }
+  };
+
+  const updateItem = (id: number, update: Partial<QueueItem>) => {
+    setItems((prev) => prev.map((i) => (i.id === id ? { ...i, ...update } : i)));
+  };
+
+  const handleStart = async () => {
+    const pending = items.filter((i) => i.status === 'pending');
+    if (pending.length === 0) return;
+
+    stopRequested.current = false;
+    setIsRunning(true);
+    const workers = getEffectiveWorkers();
+    let succeeded = 0;
+    let failed = 0;
+
+    for (const item of pending) {
+      if (stopRequested.current) break;
+
+      setItemPercent(0);
+      updateItem(item.id, { status: 'running' });
+
+      const req: CreateRequest = {
+        path: item.path,
+        name: '',
+        trackerUrls: tracker.trim() ? [tracker.trim()] : [],
+        webSeeds: [],
+        isPrivate,
+        comment: '',
+        source: '',
+        pieceLengthExp: 0,
+        maxPieceLength: 0,
+        outputPath: '',
+        outputDir,
+        noDate: false,
+        noCreator: false,
+        entropy: false,
+        skipPrefix: false,
+        excludePatterns: [],
+        includePatterns: [],
+        presetName,
+        presetFile: '',
+        workers,
+        failOnSeasonWarning: false,
+      };
+
+      try {
+        const res = await CreateTorrent(req);
+        updateItem(item.id, { status: 'done', output: res.path });
+        succeeded++;
+      } catch (e) {
+        updateItem(item.id, { status: 'failed', error: String(e) });
+        failed++;
+      }
+    }
+
+    setIsRunning(false);
+    setItemPercent(0);
+    if (failed > 0) {
+      toast.error(`Queue finished: ${succeeded} created, ${failed} failed`);
+    } else if (succeeded > 0) {
+      toast.success(`Queue finished: ${succeeded} created`);
+    }
+  };
+
+  const handleStop = () => {
+    stopRequested.current = true;
+    toast.info('Stopping after the current torrent');
+  };
+
+  const removeItem = (id: number) => {
+    setItems((prev) => prev.filter((i) => i.id !== id));
+  };
+
+  const clearFinished = () => {
+    setItems((prev) => prev.filter((i) => i.status === 'pending' || i.status === 'running'));
+  };
+
+  const finishedCount = items.filter((i) => i.status === 'done' || i.status === 'failed').length;
+  const runningCount = items.filter((i) => i.status === 'running').length;
+  const pendingCount = items.filter((i) => i.status === 'pending').length;
+  const overallPercent = items.length > 0
+    ? ((finishedCount + (runningCount > 0 ? itemPercent / 100 : 0)) / items.length) * 100
+    : 0;
+
+  return (
+    <div className="h-full overflow-auto">
+      <DropOverlay visible={isDragging} label="Drop files or folders to queue them" />
+      <div className="p-6 space-y-4">
+        <div className="flex items-center justify-between">
+          <div>
+            <h1 className="text-2xl font-semibold">Queue</h1>
+            <p className="text-sm text-muted-foreground">Create torrents for many paths with shared settings</p>
+          </div>
+          <div className="flex gap-2">
+            {finishedCount > 0 && !isRunning && (
+              <Button variant="outline" onClick={clearFinished}>
+                <Trash2 className="mr-2 h-4 w-4" />
+                Clear Finished
+              </Button>
+            )}
+            {isRunning ? (
+              <Button variant="outline" onClick={handleStop}>
+                <Square className="mr-2 h-4 w-4" />
+                Stop
+              </Button>
+            ) : (
+              <Button onClick={handleStart} disabled={pendingCount === 0}>
+                <Play className="mr-2 h-4 w-4" />
+                Start ({pendingCount})
+              </Button>
+            )}
+          </div>
+        </div>
+
+        {/* Shared settings */}
+        <Card>
+          <CardContent className="pt-6 space-y-4">
+            <div className="flex gap-4 items-end">
+              {presets.length > 0 && (
+                <div className="flex-1 space-y-1.5">
+                  <Label>Preset</Label>
+                  <Select value={presetName} onValueChange={handlePresetChange} disabled={isRunning}>
+                    <SelectTrigger>
+                      <SelectValue placeholder="Select a preset" />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="none">None</SelectItem>
+                      {presets.map((p) => (
+                        <SelectItem key={p} value={p}>
+                          {p}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                </div>
+              )}
+              <div className="flex items-center gap-2 pb-2">
+                <Switch
+                  id="queue-private"
+                  checked={isPrivate}
+                  onCheckedChange={setIsPrivate}
+                  disabled={isRunning}
+                />
+                <Label htmlFor="queue-private" className="text-sm">Private</Label>
+              </div>
+            </div>
+
+            <div className="space-y-1.5">
+              <Label>Tracker</Label>
+              <Input
+                value={tracker}
+                onChange={(e) => setTracker(e.target.value)}
+                placeholder="https://tracker.example.com/announce"
+                disabled={isRunning}
+              />
+            </div>
+
+            <div className="space-y-1.5">
+              <Label>Output Directory</Label>
+              <div className="flex gap-2">
+                <Input
+                  value={outputDir}
+                  onChange={(e) => setOutputDir(e.target.value)}
+                  placeholder="Same as source"
+                  className="flex-1"
+                  disabled={isRunning}
+                />
+                <Button variant="outline" size="icon" onClick={handleSelectOutputDir} disabled={isRunning}>
+                  <FolderOpen className="h-4 w-4" />
+                </Button>
+              </div>
+            </div>
+          </CardContent>
+        </Card>
+
+        {/* Overall progress */}
+        {isRunning && (
+          <Card>
+            <CardContent className="py-4 space-y-3">
+              <div className="flex justify-between text-sm">
+                <span className="font-medium">Processing queue</span>
+                <span className="text-muted-foreground">{finishedCount} / {items.length}</span>
+              </div>
+              <Progress value={overallPercent} />
+            </CardContent>
+          </Card>
+        )}
+
+        {/* Items */}
+        <Card>
+          <CardContent className="p-0">
+            <div className="flex items-center justify-between px-5 py-2.5 border-b">
+              <span className="text-sm font-medium">Items ({items.length})</span>
+              <Button variant="outline" size="sm" onClick={handleAddPath} disabled={isRunning}>
+                <Plus className="mr-2 h-4 w-4" />
+                Add Path
+              </Button>
+            </div>
+            {items.length === 0 ? (
+              <div className="flex flex-col items-center justify-center py-16 text-center">
+                <ListOrdered className="h-12 w-12 text-muted-foreground/50 mb-4" />
+                <p className="text-muted-foreground">Add or drop files and folders to queue them</p>
+              </div>
+            ) : (
+              <div className="divide-y">
+                {items.map((item) => (
+                  <div key={item.id} className="flex items-start gap-3 px-5 py-2.5 group">
+                    <StatusIcon status={item.status} />
+                    <div className="flex-1 min-w-0">
+                      <p className="text-sm font-medium truncate" title={item.path}>{baseName(item.path)}</p>
+                      <p className="text-xs text-muted-foreground font-mono truncate" title={item.path}>{item.path}</p>
+                      {item.status === 'done' && item.output && (
+                        <p className="text-xs text-emerald-600 dark:text-emerald-400 font-mono truncate" title={item.output}>
+                          {item.output}
+                        </p>
+                      )}
+                      {item.status === 'failed' && item.error && (
+                        <p className="text-xs text-destructive break-all">{item.error}</p>
+                      )}
+                      {item.status === 'running' && (
+                        <Progress value={itemPercent} className="h-1.5 mt-1.5" />
+                      )}
+                    </div>
+                    {item.status !== 'running' && (
+                      <button
+                        onClick={() => removeItem(item.id)}
+                        className="p-1 hover:bg-muted rounded opacity-0 group-hover:opacity-100 transition-opacity"
+                        title="Remove"
+                        disabled={isRunning}
+                      >
+                        <X className="h-3.5 w-3.5 text-muted-foreground" />
+                      </button>
+                    )}
+                  </div>
+                ))}
+              </div>
+            )}
+          </CardContent>
+        </Card>
+      </div>
+    </div>
+  );
+}
@@ -9,10 +9,14 @@ export function CreateTorrent(arg1:main.CreateRequest):Promise<main.TorrentResul
 
 export function DeletePreset(arg1:string):Promise<void>;
 
+export function EstimateTorrentSize(arg1:string,arg2:number,arg3:number,arg4:number):Promise<main.TorrentSizeEstimate>;
+
 export function FormatBytes(arg1:number):Promise<string>;
 
 export function GetAllPresets():Promise<main.PresetsResult>;
 
+export function GetContentFileCount(arg1:string):Promise<number>;
+
 export function GetContentSize(arg1:string):Promise<number>;
 
 export function GetPreset(arg1:string):Promise<preset.Options>;
@@ -35,12 +39,14 @@ export function OpenURL(arg1:string):Promise<void>;
 
 export function SavePreset(arg1:string,arg2:preset.Options):Promise<void>;
 
-export function SelectFile():Promise<string>;
+export function SelectFile(arg1:string):Promise<string>;
+
+export function SelectMultipleTorrentFiles(arg1:string):Promise<Array<string>>;
 
-export function SelectMultipleTorrentFiles():Promise<Array<string>>;
+export function SelectPath(arg1:string):Promise<string>;
 
-export function SelectPath():Promise<string>;
+export function SelectTorrentFile(arg1:string):Promise<string>;
 
-export function SelectTorrentFile():Promise<string>;
+export function ValidateTorrent(arg1:string,arg2:string):Promise<Array<main.ValidationRow>>;
 
 export function VerifyTorrent(arg1:main.VerifyRequest):Promise<main.VerifyResult>;
@@ -14,6 +14,10 @@ export function DeletePreset(arg1) {
   return window['go']['main']['App']['DeletePreset'](arg1);
 }
 
+export function EstimateTorrentSize(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['EstimateTorrentSize'](arg1, arg2, arg3, arg4);
+}
+
 export function FormatBytes(arg1) {
   return window['go']['main']['App']['FormatBytes'](arg1);
 }
@@ -22,6 +26,10 @@ export function GetAllPresets() {
   return window['go']['main']['App']['GetAllPresets']();
 }
 
+export function GetContentFileCount(arg1) {
+  return window['go']['main']['App']['GetContentFileCount'](arg1);
+}
+
 export function GetContentSize(arg1) {
   return window['go']['main']['App']['GetContentSize'](arg1);
 }
@@ -66,20 +74,24 @@ export function SavePreset(arg1, arg2) {
   return window['go']['main']['App']['SavePreset'](arg1, arg2);
 }
 
-export function SelectFile() {
-  return window['go']['main']['App']['SelectFile']();
+export function SelectFile(arg1) {
+  return window['go']['main']['App']['SelectFile'](arg1);
+}
+
+export function SelectMultipleTorrentFiles(arg1) {
+  return window['go']['main']['App']['SelectMultipleTorrentFiles'](arg1);
 }
 
-export function SelectMultipleTorrentFiles() {
-  return window['go']['main']['App']['SelectMultipleTorrentFiles']();
+export function SelectPath(arg1) {
+  return window['go']['main']['App']['SelectPath'](arg1);
 }
 
-export function SelectPath() {
-  return window['go']['main']['App']['SelectPath']();
+export function SelectTorrentFile(arg1) {
+  return window['go']['main']['App']['SelectTorrentFile'](arg1);
 }
 
-export function SelectTorrentFile() {
-  return window['go']['main']['App']['SelectTorrentFile']();
+export function ValidateTorrent(arg1, arg2) {
+  return window['go']['main']['App']['ValidateTorrent'](arg1, arg2);
 }
 
 export function VerifyTorrent(arg1) {
@@ -81,6 +81,7 @@ export namespace main {
 	    }
 	}
 	export class InspectResult {
+	    path: string;
 	    name: string;
 	    infoHash: string;
 	    size: number;
@@ -103,6 +104,7 @@ export namespace main {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
 	        this.name = source["name"];
 	        this.infoHash = source["infoHash"];
 	        this.size = source["size"];
@@ -141,6 +143,7 @@ export namespace main {
 	export class ModifyRequest {
 	    torrentPath: string;
 	    trackerUrls: string[];
+	    appendTrackers: boolean;
 	    webSeeds: string[];
 	    comment: string;
 	    source: string;
@@ -163,6 +166,7 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.torrentPath = source["torrentPath"];
 	        this.trackerUrls = source["trackerUrls"];
+	        this.appendTrackers = source["appendTrackers"];
 	        this.webSeeds = source["webSeeds"];
 	        this.comment = source["comment"];
 	        this.source = source["source"];
@@ -252,6 +256,9 @@ export namespace main {
 	    size: number;
 	    pieceCount: number;
 	    fileCount: number;
+	    bytesHashed: number;
+	    hashSeconds: number;
+	    hashRate: number;
 	    warning?: string;
 	    seasonPackInfo?: SeasonPackInfo;
 	
@@ -266,6 +273,9 @@ export namespace main {
 	        this.size = source["size"];
 	        this.pieceCount = source["pieceCount"];
 	        this.fileCount = source["fileCount"];
+	        this.bytesHashed = source["bytesHashed"];
+	        this.hashSeconds = source["hashSeconds"];
+	        this.hashRate = source["hashRate"];
 	        this.warning = source["warning"];
 	        this.seasonPackInfo = this.convertValues(source["seasonPackInfo"], SeasonPackInfo);
 	    }
@@ -288,6 +298,28 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class TorrentSizeEstimate {
+	    pieceLengthExp: number;
+	    estimatedSize: number;
+	    maxTorrentSize: number;
+	    raised: boolean;
+	    fits: boolean;
+	    nearLimit: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TorrentSizeEstimate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pieceLengthExp = source["pieceLengthExp"];
+	        this.estimatedSize = source["estimatedSize"];
+	        this.maxTorrentSize = source["maxTorrentSize"];
+	        this.raised = source["raised"];
+	        this.fits = source["fits"];
+	        this.nearLimit = source["nearLimit"];
+	    }
+	}
 	export class TrackerInfo {
 	    maxPieceLength: number;
 	    maxTorrentSize: number;
@@ -307,6 +339,22 @@ export namespace main {
 	    }
 	}
 	
+	export class ValidationRow {
+	    check: string;
+	    message: string;
+	    severity: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.check = source["check"];
+	        this.message = source["message"];
+	        this.severity = source["severity"];
+	    }
+	}
 	export class VerifyRequest {
 	    torrentPath: string;
 	    contentPath: string;
@@ -363,6 +411,7 @@ export namespace preset {
 	    excludePatterns?: string[];
 	    includePatterns?: string[];
 	    pieceLength?: number;
+	    minPieceLength?: number;
 	    maxPieceLength?: number;
 	    targetPieceCount?: number;
 	    workers?: number;
@@ -387,6 +436,7 @@ export namespace preset {
 	        this.excludePatterns = source["excludePatterns"];
 	        this.includePatterns = source["includePatterns"];
 	        this.pieceLength = source["pieceLength"];
+	        this.minPieceLength = source["minPieceLength"];
 	        this.maxPieceLength = source["maxPieceLength"];
 	        this.targetPieceCount = source["targetPieceCount"];
 	        this.workers = source["workers"];
@@ -0,0 +1,35 @@
+// Package clipboard copies text to the system clipboard using the clipboard
+// tools of the platform, in the style of github.com/atotto/clipboard.
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard can be used, e.g. on a headless system
+var ErrUnavailable = errors.New("no clipboard available")
+
+// WriteAll copies text to the system clipboard
+func WriteAll(text string) error {
+	for _, args := range commands() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := bytes.TrimSpace(out); len(msg) > 0 {
+				return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+			}
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		return nil
+	}
+	return ErrUnavailable
+}
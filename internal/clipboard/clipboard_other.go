@@ -0,0 +1,7 @@
+//go:build !(darwin || windows || linux || freebsd || netbsd || openbsd || dragonfly || solaris)
+
+package clipboard
+
+func commands() [][]string {
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly || solaris
+
+package clipboard
+
+import "os"
+
+// commands returns the clipboard tools for the running display server. Without
+// one (e.g. over ssh) only Termux's clipboard can be used.
+func commands() [][]string {
+	var cmds [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		cmds = append(cmds,
+			[]string{"xclip", "-in", "-selection", "clipboard"},
+			[]string{"xsel", "--input", "--clipboard"},
+		)
+	}
+	return append(cmds, []string{"termux-clipboard-set"})
+}
@@ -0,0 +1,45 @@
+//go:build linux || freebsd || netbsd || openbsd || dragonfly || solaris
+
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAll_Headless(t *testing.T) {
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("PATH", t.TempDir())
+
+	if err := WriteAll("magnet:?xt=urn:btih:0"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("WriteAll() error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestWriteAll_UsesClipboardTool(t *testing.T) {
+	binDir := t.TempDir()
+	outPath := filepath.Join(binDir, "clipboard.txt")
+	// PATH only holds the fake tool, so the script sticks to shell builtins
+	script := "#!/bin/sh\nIFS= read -r line\nprintf '%s' \"$line\" > " + outPath + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "xclip"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("PATH", binDir)
+
+	if err := WriteAll("magnet:?xt=urn:btih:0"); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "magnet:?xt=urn:btih:0" {
+		t.Errorf("clipboard = %q, want the magnet link", got)
+	}
+}
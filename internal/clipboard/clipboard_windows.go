@@ -0,0 +1,5 @@
+package clipboard
+
+func commands() [][]string {
+	return [][]string{{"clip"}}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to upper-cased flag names to form environment variable names,
+// e.g. --output-dir can be set with MKBRR_OUTPUT_DIR
+const EnvPrefix = "MKBRR_"
+
+// ErrConfigFileNotFound is returned when no config file can be found in known locations
+var ErrConfigFileNotFound = errors.New("could not find config file in known locations")
+
+// Config holds personal defaults for command-line flags.
+// Keys are flag names, e.g. "output-dir" or "no-date" (output_dir is also accepted).
+//
+//	version: 1
+//	defaults:        # applied to every command that has the flag
+//	  workers: 4
+//	create:          # applied to a single command, overrides defaults
+//	  output-dir: ~/torrents
+//	  no-date: true
+//	tracker_aliases: # short names for tracker URLs, e.g. for inspect -T red
+//	  red: https://flacsfor.me/announce
+type Config struct {
+	Defaults       map[string]any            `yaml:"defaults"`
+	TrackerAliases map[string]string         `yaml:"tracker_aliases"`
+	Commands       map[string]map[string]any `yaml:",inline"`
+	Version        int                       `yaml:"version"`
+}
+
+// FindConfigFile returns the path of the global config file.
+// MKBRR_CONFIG takes precedence over the default locations.
+func FindConfigFile() (string, error) {
+	if path := os.Getenv(EnvPrefix + "CONFIG"); path != "" {
+		return path, nil
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, loc := range []string{
+			filepath.Join(home, ".config", "mkbrr", "config.yaml"), // ~/.config/mkbrr/
+			filepath.Join(home, ".mkbrr", "config.yaml"),           // ~/.mkbrr/
+		} {
+			if _, err := os.Stat(loc); err == nil {
+				return loc, nil
+			}
+		}
+	}
+
+	return "", ErrConfigFileNotFound
+}
+
+// Load loads the global config from a file
+func Load(configPath string) (*Config, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse config: %w", err)
+	}
+
+	if config.Version != 1 {
+		return nil, fmt.Errorf("unsupported config version: %d", config.Version)
+	}
+
+	return &config, nil
+}
+
+// TrackerAlias returns the tracker URL for an alias from tracker_aliases.
+// Aliases are matched case-insensitively.
+func (c *Config) TrackerAlias(name string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	for alias, url := range c.TrackerAliases {
+		if strings.EqualFold(alias, name) && url != "" {
+			return url, true
+		}
+	}
+	return "", false
+}
+
+// Apply sets flags that were not given on the command line, first from
+// MKBRR_* environment variables and then from the config (if not nil).
+// Precedence is flags > env > config > built-in defaults. Flags set this way
+// are not marked as changed, so presets still take precedence over them.
+func Apply(config *Config, command string, flags *pflag.FlagSet) error {
+	var defaults, commandValues map[string]any
+	if config != nil {
+		defaults = normalizeKeys(config.Defaults)
+		commandValues = normalizeKeys(config.Commands[command])
+	}
+
+	for name := range commandValues {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("config: unknown option %q for %s", name, command)
+		}
+	}
+
+	var applyErr error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if applyErr != nil || f.Changed || f.Name == "help" {
+			return
+		}
+
+		envName := EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if value, ok := os.LookupEnv(envName); ok {
+			if err := setFlag(f, value); err != nil {
+				applyErr = fmt.Errorf("invalid value for %s: %w", envName, err)
+			}
+			return
+		}
+
+		value, ok := commandValues[f.Name]
+		if !ok {
+			value, ok = defaults[f.Name]
+		}
+		if ok {
+			if err := setFlag(f, value); err != nil {
+				applyErr = fmt.Errorf("config: invalid value for %q: %w", f.Name, err)
+			}
+		}
+	})
+
+	return applyErr
+}
+
+// normalizeKeys converts snake_case keys to the kebab-case used by flags
+func normalizeKeys(values map[string]any) map[string]any {
+	normalized := make(map[string]any, len(values))
+	for key, value := range values {
+		normalized[strings.ReplaceAll(key, "_", "-")] = value
+	}
+	return normalized
+}
+
+// setFlag sets a flag's value without marking it as changed
+func setFlag(f *pflag.Flag, value any) error {
+	if list, ok := value.([]any); ok {
+		values := make([]string, len(list))
+		for i, v := range list {
+			values[i] = expandHome(fmt.Sprint(v))
+		}
+		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+			return sliceValue.Replace(values)
+		}
+		return f.Value.Set(strings.Join(values, ","))
+	}
+
+	switch v := value.(type) {
+	case string:
+		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+			return sliceValue.Replace([]string{expandHome(v)})
+		}
+		return f.Value.Set(expandHome(v))
+	case bool:
+		return f.Value.Set(strconv.FormatBool(v))
+	default:
+		return f.Value.Set(fmt.Sprint(v))
+	}
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func newTestFlags() (*pflag.FlagSet, *string, *bool, *int, *[]string) {
+	flags := pflag.NewFlagSet("create", pflag.ContinueOnError)
+	outputDir := flags.String("output-dir", "", "")
+	noDate := flags.Bool("no-date", false, "")
+	workers := flags.Int("workers", 0, "")
+	trackers := flags.StringArray("tracker", nil, "")
+	return flags, outputDir, noDate, workers, trackers
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadAndApply(t *testing.T) {
+	path := writeConfig(t, `version: 1
+defaults:
+  workers: 2
+  no-date: false
+create:
+  output_dir: /tmp/torrents
+  no-date: true
+  tracker:
+    - https://tracker.example.com/announce
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	flags, outputDir, noDate, workers, trackers := newTestFlags()
+	if err := flags.Parse([]string{"--workers", "8"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if err := Apply(cfg, "create", flags); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if *outputDir != "/tmp/torrents" {
+		t.Errorf("output-dir = %q, want /tmp/torrents", *outputDir)
+	}
+	if !*noDate {
+		t.Error("no-date = false, want command value to override defaults")
+	}
+	if *workers != 8 {
+		t.Errorf("workers = %d, want command-line value 8", *workers)
+	}
+	if len(*trackers) != 1 || (*trackers)[0] != "https://tracker.example.com/announce" {
+		t.Errorf("tracker = %v, want config tracker", *trackers)
+	}
+	if flags.Changed("output-dir") {
+		t.Error("config values should not mark flags as changed")
+	}
+}
+
+func TestApplyEnvOverridesConfig(t *testing.T) {
+	t.Setenv("MKBRR_OUTPUT_DIR", "/from/env")
+
+	cfg := &Config{Version: 1, Commands: map[string]map[string]any{
+		"create": {"output-dir": "/from/config"},
+	}}
+
+	flags, outputDir, _, _, _ := newTestFlags()
+	if err := Apply(cfg, "create", flags); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if *outputDir != "/from/env" {
+		t.Errorf("output-dir = %q, want /from/env", *outputDir)
+	}
+
+	flags, outputDir, _, _, _ = newTestFlags()
+	if err := flags.Parse([]string{"--output-dir", "/from/flag"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := Apply(cfg, "create", flags); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if *outputDir != "/from/flag" {
+		t.Errorf("output-dir = %q, want /from/flag", *outputDir)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{
+			name: "unknown option for command",
+			cfg:  &Config{Version: 1, Commands: map[string]map[string]any{"create": {"bogus": true}}},
+		},
+		{
+			name: "invalid value",
+			cfg:  &Config{Version: 1, Defaults: map[string]any{"workers": "many"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, _, _, _ := newTestFlags()
+			if err := Apply(tt.cfg, "create", flags); err == nil {
+				t.Error("Apply() expected error")
+			}
+		})
+	}
+}
+
+func TestApplyIgnoresDefaultsForOtherCommands(t *testing.T) {
+	cfg := &Config{Version: 1, Defaults: map[string]any{"skip-prefix": true}}
+
+	flags, _, _, _, _ := newTestFlags()
+	if err := Apply(cfg, "create", flags); err != nil {
+		t.Errorf("Apply() error = %v, want defaults for missing flags to be skipped", err)
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	path := writeConfig(t, "version: 2\n")
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected error for unsupported version")
+	}
+}
+
+func TestTrackerAlias(t *testing.T) {
+	path := writeConfig(t, `version: 1
+create:
+  no-date: true
+tracker_aliases:
+  red: https://flacsfor.me/abc/announce
+  PTP: https://please.passthepopcorn.me/abc/announce
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := cfg.Commands["tracker_aliases"]; ok {
+		t.Error("tracker_aliases was parsed as a command section")
+	}
+
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"red", "https://flacsfor.me/abc/announce", true},
+		{"RED", "https://flacsfor.me/abc/announce", true},
+		{"ptp", "https://please.passthepopcorn.me/abc/announce", true},
+		{"ops", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.TrackerAlias(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("TrackerAlias(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	var none *Config
+	if _, ok := none.TrackerAlias("red"); ok {
+		t.Error("TrackerAlias() on a nil config should not resolve")
+	}
+}
@@ -1,6 +1,7 @@
 package preset
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -40,6 +41,7 @@ type Options struct {
 	ExcludePatterns     []string `yaml:"exclude_patterns" json:"excludePatterns,omitempty"`
 	IncludePatterns     []string `yaml:"include_patterns" json:"includePatterns,omitempty"`
 	PieceLength         uint     `yaml:"piece_length" json:"pieceLength,omitempty"`
+	MinPieceLength      uint     `yaml:"min_piece_length" json:"minPieceLength,omitempty"`
 	MaxPieceLength      uint     `yaml:"max_piece_length" json:"maxPieceLength,omitempty"`
 	TargetPieceCount    uint     `yaml:"target_piece_count" json:"targetPieceCount,omitempty"`
 	Workers             int      `yaml:"workers" json:"workers,omitempty"`
@@ -126,8 +128,19 @@ func LoadOrCreate(configPath string) (*Config, error) {
 	return &config, nil
 }
 
-// Save saves the config to a YAML file
+// renameFile replaces the preset file with the fully written temp file; tests swap it
+// out to simulate a save interrupted before the file is replaced
+var renameFile = os.Rename
+
+// Save saves the config to a YAML file. If the file exists, its comments and key order
+// are kept for entries that didn't change. The file is written to a temp file first and
+// renamed into place, so an interrupted save leaves the previous file intact.
 func Save(configPath string, config *Config) error {
+	// write through symlinks instead of replacing them
+	if resolved, err := filepath.EvalSymlinks(configPath); err == nil {
+		configPath = resolved
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(configPath)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
@@ -137,30 +150,94 @@ func Save(configPath string, config *Config) error {
 		return fmt.Errorf("could not secure config directory: %w", err)
 	}
 
-	// Marshal to YAML
-	data, err := yaml.Marshal(config)
+	data, err := marshalConfig(configPath, config)
 	if err != nil {
 		return fmt.Errorf("could not marshal config: %w", err)
 	}
 
-	// Write file
-	if err := os.WriteFile(configPath, data, 0o600); err != nil {
+	if err := writeFileAtomic(configPath, data); err != nil {
 		return fmt.Errorf("could not write config file: %w", err)
 	}
-	if err := os.Chmod(configPath, 0o600); err != nil {
-		return fmt.Errorf("could not secure config file: %w", err)
-	}
 
 	return nil
 }
 
-// GetDefaultPresetPath returns the default preset file path (~/.config/mkbrr/presets.yaml)
+// marshalConfig encodes config, updating the existing file at configPath in place
+// when it can be parsed so hand-written comments and ordering are preserved
+func marshalConfig(configPath string, config *Config) ([]byte, error) {
+	var doc yaml.Node
+	existing, err := os.ReadFile(configPath)
+	if err == nil && yaml.Unmarshal(existing, &doc) == nil {
+		merged, err := mergeConfigNode(&doc, config)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			return encodeYAML(&doc)
+		}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return nil, err
+	}
+	return encodeYAML(compact(&node))
+}
+
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temp file next to path with mode 0600, syncs it
+// and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return renameFile(tmpPath, path)
+}
+
+// GetDefaultPresetPath returns the default preset file path (~/.config/mkbrr/presets.yaml),
+// creating its directory if needed
 func GetDefaultPresetPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("could not get home directory: %w", err)
 	}
-	return filepath.Join(home, ".config", "mkbrr", "presets.yaml"), nil
+	dir := filepath.Join(home, ".config", "mkbrr")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("could not create config directory: %w", err)
+	}
+	return filepath.Join(dir, "presets.yaml"), nil
 }
 
 // GetPreset returns a preset by name, merged with default settings
@@ -208,6 +285,7 @@ func (c *Config) GetPreset(name string) (*Options, error) {
 		merged.Source = c.Default.Source
 		merged.OutputDir = c.Default.OutputDir
 		merged.PieceLength = c.Default.PieceLength
+		merged.MinPieceLength = c.Default.MinPieceLength
 		merged.MaxPieceLength = c.Default.MaxPieceLength
 		merged.TargetPieceCount = c.Default.TargetPieceCount
 		merged.Workers = c.Default.Workers
@@ -242,6 +320,9 @@ func (c *Config) GetPreset(name string) (*Options, error) {
 		merged.PieceLength = preset.PieceLength
 		merged.TargetPieceCount = 0 // mutually exclusive: preset override clears inherited value
 	}
+	if preset.MinPieceLength != 0 {
+		merged.MinPieceLength = preset.MinPieceLength
+	}
 	if preset.MaxPieceLength != 0 {
 		merged.MaxPieceLength = preset.MaxPieceLength
 	}
@@ -1,9 +1,12 @@
 package preset
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -68,11 +71,11 @@ func TestPresetTargetPieceCountMerge(t *testing.T) {
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
 
 	tests := []struct {
-		name             string
-		config           string
-		presetName       string
-		wantPieceLength  uint
-		wantTargetCount  uint
+		name            string
+		config          string
+		presetName      string
+		wantPieceLength uint
+		wantTargetCount uint
 	}{
 		{
 			name: "preset with both values: last writer wins (target_piece_count clears piece_length)",
@@ -258,3 +261,171 @@ func TestSaveFixesExistingPresetFilePermissions(t *testing.T) {
 		t.Fatalf("preset dir mode = %o, want 700", got)
 	}
 }
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "presets.yaml")
+	private := true
+	noDate := false
+	config := &Config{
+		Version: 1,
+		Default: &Options{Private: &private, OutputDir: "/torrents"},
+		Presets: map[string]Options{
+			"ptp": {
+				Source:          "PTP",
+				NoDate:          &noDate,
+				Trackers:        []string{"https://please.passthe.tea/announce"},
+				ExcludePatterns: []string{"*.nfo", "*sample*"},
+				PieceLength:     20,
+			},
+			"public": {
+				Trackers: []string{"udp://tracker.opentrackr.org:1337/announce"},
+				Workers:  2,
+			},
+		},
+	}
+
+	if err := Save(configPath, config); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, config) {
+		t.Fatalf("loaded config differs from saved config:\ngot  %+v\nwant %+v", loaded, config)
+	}
+
+	// saving the loaded config again must not change the file
+	before, _ := os.ReadFile(configPath)
+	if err := Save(configPath, loaded); err != nil {
+		t.Fatalf("second Save() error = %v", err)
+	}
+	after, _ := os.ReadFile(configPath)
+	if string(before) != string(after) {
+		t.Errorf("re-saving an unchanged config rewrote the file:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+}
+
+func TestSavePreservesCommentsAndOrder(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "presets.yaml")
+	original := `version: 1
+
+# defaults for every preset
+default:
+  private: true
+  output_dir: "/torrents" # where torrents go
+
+presets:
+  zeta:
+    source: "ZETA" # tracker source tag
+    trackers:
+      - "https://zeta.example/announce"
+  # alpha sorts first but is listed second
+  alpha:
+    source: "ALPHA"
+  old:
+    source: "OLD"
+`
+	if err := os.WriteFile(configPath, []byte(original), 0o600); err != nil {
+		t.Fatalf("write preset file: %v", err)
+	}
+
+	config, err := LoadOrCreate(configPath)
+	if err != nil {
+		t.Fatalf("LoadOrCreate() error = %v", err)
+	}
+	zeta := config.Presets["zeta"]
+	zeta.Source = "Z"
+	config.Presets["zeta"] = zeta
+	delete(config.Presets, "old")
+	config.Presets["new"] = Options{Comment: "added"}
+
+	if err := Save(configPath, config); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read saved file: %v", err)
+	}
+	saved := string(data)
+
+	for _, want := range []string{
+		"# defaults for every preset",
+		`output_dir: "/torrents" # where torrents go`,
+		`source: "Z" # tracker source tag`,
+		"# alpha sorts first but is listed second",
+		"comment: added",
+	} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("saved file is missing %q:\n%s", want, saved)
+		}
+	}
+	if strings.Contains(saved, "OLD") {
+		t.Errorf("deleted preset is still in the saved file:\n%s", saved)
+	}
+	if strings.Contains(saved, "null") {
+		t.Errorf("saved file contains unset fields:\n%s", saved)
+	}
+	if strings.Index(saved, "zeta:") > strings.Index(saved, "alpha:") || strings.Index(saved, "alpha:") > strings.Index(saved, "new:") {
+		t.Errorf("preset order was not preserved:\n%s", saved)
+	}
+
+	reloaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(reloaded, config) {
+		t.Errorf("reloaded config differs:\ngot  %+v\nwant %+v", reloaded, config)
+	}
+}
+
+func TestSaveInterruptedKeepsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "presets.yaml")
+	original := "version: 1\npresets:\n  keep:\n    source: KEEP\n"
+	if err := os.WriteFile(configPath, []byte(original), 0o600); err != nil {
+		t.Fatalf("write preset file: %v", err)
+	}
+
+	renameFile = func(string, string) error { return errors.New("simulated crash") }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	config := &Config{Version: 1, Presets: map[string]Options{"other": {Source: "OTHER"}}}
+	if err := Save(configPath, config); err == nil {
+		t.Fatal("Save() expected error when the rename fails")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("read preset file: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("preset file changed by an interrupted save:\n%s", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the preset file to remain, found %d entries", len(entries))
+	}
+}
+
+func TestGetDefaultPresetPathCreatesDirectory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	path, err := GetDefaultPresetPath()
+	if err != nil {
+		t.Fatalf("GetDefaultPresetPath() error = %v", err)
+	}
+	if want := filepath.Join(home, ".config", "mkbrr", "presets.yaml"); path != want {
+		t.Errorf("GetDefaultPresetPath() = %q, want %q", path, want)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		t.Errorf("expected %s to be created, got error: %v", filepath.Dir(path), err)
+	}
+}
@@ -0,0 +1,120 @@
+package preset
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OutputTemplateData holds the values available to output pattern placeholders
+type OutputTemplateData struct {
+	Date     time.Time
+	Name     string
+	Tracker  string // tracker URL, expanded to its domain prefix
+	InfoHash string // hex encoded info hash
+	Size     int64  // total content size in bytes
+}
+
+// OutputTemplateTokens lists the placeholders supported in output patterns
+var OutputTemplateTokens = []string{"{name}", "{tracker}", "{date}", "{infohash}", "{infohash8}", "{size}"}
+
+var outputTokenPattern = regexp.MustCompile(`\{[a-z0-9]+\}`)
+
+// SourceTemplateTokens lists the placeholders supported in source tags. The source is part
+// of the info dictionary, so placeholders derived from the info hash are not available.
+var SourceTemplateTokens = []string{"{tracker}"}
+
+// ValidateOutputPattern returns an error if the pattern contains unknown placeholders
+func ValidateOutputPattern(pattern string) error {
+	return validatePattern(pattern, OutputTemplateTokens, "output pattern")
+}
+
+// ValidateSourcePattern returns an error if the source contains unknown placeholders
+func ValidateSourcePattern(source string) error {
+	return validatePattern(source, SourceTemplateTokens, "source")
+}
+
+func validatePattern(pattern string, tokens []string, what string) error {
+	for _, token := range outputTokenPattern.FindAllString(pattern, -1) {
+		if !slices.Contains(tokens, token) {
+			return fmt.Errorf("unknown placeholder %s in %s (available: %s)", token, what, strings.Join(tokens, ", "))
+		}
+	}
+	return nil
+}
+
+// ExpandSource returns the source tag for a torrent announced to trackerURL.
+// An entry in sourceMap whose key is the tracker's host or a parent domain of it
+// (e.g. "example.com" for tracker.example.com) takes precedence, the longest key
+// winning if several match. Otherwise {tracker} in source is replaced with the
+// tracker's domain prefix.
+func ExpandSource(source string, sourceMap map[string]string, trackerURL string) string {
+	if trackerURL == "" {
+		return strings.ReplaceAll(source, "{tracker}", "")
+	}
+
+	var host string
+	if u, err := url.Parse(strings.TrimSpace(trackerURL)); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	matched := ""
+	for key := range sourceMap {
+		domain := strings.ToLower(key)
+		if key == "" || len(key) <= len(matched) || host == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			matched = key
+		}
+	}
+	if matched != "" {
+		return sourceMap[matched]
+	}
+
+	return strings.ReplaceAll(source, "{tracker}", GetDomainPrefix(trackerURL))
+}
+
+// ExpandOutputPattern replaces the placeholders in pattern with values from data.
+// Expanded values are sanitized for use in filenames; unknown placeholders are left as is.
+func ExpandOutputPattern(pattern string, data OutputTemplateData) string {
+	if !strings.Contains(pattern, "{") {
+		return pattern
+	}
+
+	date := data.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	infoHash8 := data.InfoHash
+	if len(infoHash8) > 8 {
+		infoHash8 = infoHash8[:8]
+	}
+
+	replacer := strings.NewReplacer(
+		"{name}", sanitizeFilename(data.Name),
+		"{tracker}", GetDomainPrefix(data.Tracker),
+		"{date}", date.Format("2006-01-02"),
+		"{infohash}", data.InfoHash,
+		"{infohash8}", infoHash8,
+		"{size}", formatSizeToken(data.Size),
+	)
+	return replacer.Replace(pattern)
+}
+
+// formatSizeToken formats a size compactly for filenames, e.g. "4.2GiB"
+func formatSizeToken(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,98 @@
+package preset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandOutputPattern(t *testing.T) {
+	data := OutputTemplateData{
+		Date:     time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+		Name:     "Some Show S01",
+		Tracker:  "https://tracker.example.com/announce?passkey=123",
+		InfoHash: "0123456789abcdef0123456789abcdef01234567",
+		Size:     4509715661,
+	}
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "{tracker}_{name}_{date}", want: "example_Some_Show_S01_2024-03-09"},
+		{pattern: "{name}-{infohash8}", want: "Some_Show_S01-01234567"},
+		{pattern: "{infohash}", want: "0123456789abcdef0123456789abcdef01234567"},
+		{pattern: "{name} [{size}]", want: "Some_Show_S01 [4.2GiB]"},
+		{pattern: "plain", want: "plain"},
+		{pattern: "{unknown}_{name}", want: "{unknown}_Some_Show_S01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := ExpandOutputPattern(tt.pattern, data); got != tt.want {
+				t.Errorf("ExpandOutputPattern(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOutputPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "{tracker}_{name}_{date}"},
+		{pattern: "{infohash8}_{size}"},
+		{pattern: "no placeholders"},
+		{pattern: "{name}_{hash}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateOutputPattern(tt.pattern)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateOutputPattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestExpandSource(t *testing.T) {
+	sourceMap := map[string]string{
+		"example.com":         "EX",
+		"private.example.com": "PRIV",
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		sourceMap map[string]string
+		tracker   string
+		want      string
+	}{
+		{name: "static", source: "MySource", tracker: "https://tracker.example.com/announce", want: "MySource"},
+		{name: "tracker token", source: "{tracker}", tracker: "https://tracker.example.com/announce", want: "example"},
+		{name: "token with text", source: "{tracker}-web", tracker: "https://other.org/announce", want: "other-web"},
+		{name: "no tracker", source: "{tracker}", want: ""},
+		{name: "mapped", source: "{tracker}", sourceMap: sourceMap, tracker: "https://tracker.example.com/announce", want: "EX"},
+		{name: "longest key wins", source: "fallback", sourceMap: sourceMap, tracker: "https://private.example.com/announce", want: "PRIV"},
+		{name: "unmapped tracker", source: "{tracker}", sourceMap: sourceMap, tracker: "https://other.org/announce", want: "other"},
+		{name: "exact host", source: "fallback", sourceMap: map[string]string{"ptp.example": "PTP"}, tracker: "https://PTP.example:443/announce", want: "PTP"},
+		{name: "host only contains key", source: "fallback", sourceMap: map[string]string{"ptp.example": "PTP"}, tracker: "https://notptp.example/announce", want: "fallback"},
+		{name: "key in path", source: "fallback", sourceMap: sourceMap, tracker: "https://other.org/example.com/announce", want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandSource(tt.source, tt.sourceMap, tt.tracker); got != tt.want {
+				t.Errorf("ExpandSource(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSourcePattern(t *testing.T) {
+	if err := ValidateSourcePattern("{tracker}-web"); err != nil {
+		t.Errorf("ValidateSourcePattern() unexpected error: %v", err)
+	}
+	if err := ValidateSourcePattern("{name}"); err == nil {
+		t.Error("ValidateSourcePattern() expected error for {name}")
+	}
+}
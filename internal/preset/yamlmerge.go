@@ -0,0 +1,161 @@
+package preset
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// mergeConfigNode updates a parsed preset file in place with the values of config,
+// keeping the file's comments, key order and quoting for everything that didn't change.
+// Presets missing from config are removed. It returns false if the document can't be
+// updated safely (e.g. it uses anchors or aliases), in which case it must be rewritten.
+func mergeConfigNode(doc *yaml.Node, config *Config) (bool, error) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return false, nil
+	}
+	if usesAnchors(doc) {
+		return false, nil
+	}
+
+	var fresh yaml.Node
+	if err := fresh.Encode(config); err != nil {
+		return false, err
+	}
+
+	root := doc.Content[0]
+	mergeMapping(root, &fresh)
+
+	// presets are keyed by name, so a name missing from config was deleted
+	if dst, src := mappingValue(root, "presets"), mappingValue(&fresh, "presets"); dst != nil && src != nil &&
+		dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(dst.Content); {
+			if mappingValue(src, dst.Content[i].Value) == nil {
+				dst.Content = append(dst.Content[:i], dst.Content[i+2:]...)
+				continue
+			}
+			i += 2
+		}
+	}
+
+	return true, nil
+}
+
+// mergeMapping copies the keys of src into dst. Existing keys are updated in place,
+// null values remove the key and new keys are appended unless they are empty.
+// Keys only present in dst are kept, so unknown fields survive a save.
+func mergeMapping(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+
+		idx := mappingIndex(dst, key.Value)
+		switch {
+		case idx >= 0 && isNull(value):
+			dst.Content = append(dst.Content[:idx], dst.Content[idx+2:]...)
+		case idx >= 0:
+			mergeValue(dst.Content[idx+1], value)
+		case !isEmpty(value):
+			dst.Content = append(dst.Content, key, compact(value))
+		}
+	}
+}
+
+// mergeValue updates dst with src, keeping the comments attached to dst
+func mergeValue(dst, src *yaml.Node) {
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		mergeMapping(dst, src)
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				mergeValue(dst.Content[i], item)
+			} else {
+				dst.Content = append(dst.Content, compact(item))
+			}
+		}
+		dst.Content = dst.Content[:len(src.Content)]
+		if len(src.Content) == 0 {
+			dst.Style = yaml.FlowStyle // "[]" rather than an invalid empty block sequence
+		}
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		if dst.Value == src.Value && dst.ShortTag() == src.ShortTag() {
+			return
+		}
+		if dst.ShortTag() != src.ShortTag() {
+			dst.Tag, dst.Style = src.Tag, src.Style
+		}
+		dst.Value = src.Value
+	default:
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *compact(src)
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+	}
+}
+
+// compact removes empty values from mappings in n, so new entries only contain
+// the fields that are actually set
+func compact(n *yaml.Node) *yaml.Node {
+	switch n.Kind {
+	case yaml.MappingNode:
+		content := n.Content[:0]
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if !isEmpty(n.Content[i+1]) {
+				content = append(content, n.Content[i], compact(n.Content[i+1]))
+			}
+		}
+		n.Content = content
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			compact(item)
+		}
+	}
+	return n
+}
+
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if idx := mappingIndex(mapping, key); idx >= 0 {
+		return mapping.Content[idx+1]
+	}
+	return nil
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// isEmpty reports whether n holds a zero value that doesn't need to be written
+func isEmpty(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return true
+		case "!!str":
+			return n.Value == ""
+		case "!!int":
+			return n.Value == "0"
+		}
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
+
+func usesAnchors(n *yaml.Node) bool {
+	if n.Kind == yaml.AliasNode || n.Anchor != "" {
+		return true
+	}
+	for _, child := range n.Content {
+		if usesAnchors(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package trackers
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // TrackerConfig holds tracker-specific configuration
 type TrackerConfig struct {
@@ -369,6 +372,13 @@ func GetTrackerPieceSizeExp(trackerURL string, contentSize uint64) (uint, bool)
 	return exp, true
 }
 
+// UsesDefaultRanges reports whether a tracker uses DefaultPieceSizeRanges rather than its own
+// piece size rules, so the automatic piece length may be tuned further, e.g. for file count
+func UsesDefaultRanges(trackerURL string) bool {
+	config := findTrackerConfig(trackerURL)
+	return config != nil && len(config.PieceSizeRanges) == 0 && config.UseDefaultRanges
+}
+
 // GetTrackerMaxTorrentSize returns the maximum allowed .torrent file size for a tracker if known
 func GetTrackerMaxTorrentSize(trackerURL string) (uint64, bool) {
 	if config := findTrackerConfig(trackerURL); config != nil {
@@ -384,3 +394,19 @@ func GetTrackerDefaultSource(trackerURL string) (string, bool) {
 	}
 	return "", false
 }
+
+// RequiresPrivate reports whether a tracker only accepts private torrents.
+// Every tracker in trackerConfigs is a private tracker, so any known tracker qualifies.
+func RequiresPrivate(trackerURL string) bool {
+	return findTrackerConfig(trackerURL) != nil
+}
+
+// KnownTrackers returns the hosts of all trackers with a known config, sorted
+func KnownTrackers() []string {
+	var hosts []string
+	for _, config := range trackerConfigs {
+		hosts = append(hosts, config.URLs...)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
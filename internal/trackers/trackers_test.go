@@ -304,3 +304,68 @@ func Test_trackerConfigConsistency(t *testing.T) {
 		}
 	}
 }
+
+func Test_RequiresPrivate(t *testing.T) {
+	tests := []struct {
+		name       string
+		trackerURL string
+		want       bool
+	}{
+		{
+			name:       "ptp requires private",
+			trackerURL: "https://passthepopcorn.me/announce?passkey=123",
+			want:       true,
+		},
+		{
+			name:       "upload.cx requires private",
+			trackerURL: "https://upload.cx/announce/123",
+			want:       true,
+		},
+		{
+			name:       "unknown tracker does not require private",
+			trackerURL: "udp://tracker.opentrackr.org:1337/announce",
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequiresPrivate(tt.trackerURL); got != tt.want {
+				t.Errorf("RequiresPrivate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_KnownTrackers(t *testing.T) {
+	hosts := KnownTrackers()
+	if len(hosts) == 0 {
+		t.Fatal("KnownTrackers() returned no hosts")
+	}
+
+	for i, host := range hosts {
+		if !RequiresPrivate("https://" + host + "/announce") {
+			t.Errorf("KnownTrackers() host %q has no tracker config", host)
+		}
+		if i > 0 && hosts[i-1] > host {
+			t.Errorf("KnownTrackers() not sorted: %q before %q", hosts[i-1], host)
+		}
+	}
+}
+
+func Test_UsesDefaultRanges(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://empornium.sx/announce?passkey=123", want: true},
+		{url: "https://please.passthepopcorn.me:2710/passkey/announce", want: false},
+		{url: "https://unknown.tracker.com/announce", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := UsesDefaultRanges(tt.url); got != tt.want {
+			t.Errorf("UsesDefaultRanges(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -14,6 +14,6 @@ var (
 func main() {
 	cmd.SetVersion(version, buildTime)
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
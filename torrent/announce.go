@@ -0,0 +1,102 @@
+package torrent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// LoadAnnounceListFile reads tracker tiers from a file.
+// See ParseAnnounceList for the file format.
+func LoadAnnounceListFile(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open announce list file: %w", err)
+	}
+	defer f.Close()
+
+	tiers, err := ParseAnnounceList(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid announce list file %q: %w", path, err)
+	}
+	return tiers, nil
+}
+
+// LoadTrackerTiers returns the announce list of the torrent at path, with its tier
+// structure intact, so its trackers can be copied onto another torrent.
+func LoadTrackerTiers(path string) ([][]string, error) {
+	mi, err := LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tiers [][]string
+	for _, tier := range mi.UpvertedAnnounceList() {
+		if len(tier) > 0 {
+			tiers = append(tiers, append([]string{}, tier...))
+		}
+	}
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("torrent %q has no trackers to copy", path)
+	}
+	return tiers, nil
+}
+
+// ParseAnnounceList parses announce URLs, one per line.
+// A blank line starts a new tier and lines starting with '#' are ignored.
+func ParseAnnounceList(r io.Reader) ([][]string, error) {
+	var tiers [][]string
+	var current []string
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "" {
+			if len(current) > 0 {
+				tiers = append(tiers, current)
+				current = nil
+			}
+			continue
+		}
+		if err := validateAnnounceURL(line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		current = append(current, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(current) > 0 {
+		tiers = append(tiers, current)
+	}
+
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("no announce URLs found")
+	}
+	return tiers, nil
+}
+
+// validateAnnounceURL checks that an announce URL has a supported scheme and a host
+func validateAnnounceURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid announce URL %q: %w", rawURL, err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "udp", "ws", "wss":
+	default:
+		return fmt.Errorf("invalid announce URL %q: unsupported scheme %q", rawURL, u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid announce URL %q: missing host", rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package torrent
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAnnounceList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:  "single tier",
+			input: "https://a.example/announce\nudp://b.example:1337/announce\n",
+			want:  [][]string{{"https://a.example/announce", "udp://b.example:1337/announce"}},
+		},
+		{
+			name: "tiers with comments and extra blank lines",
+			input: `# primary
+https://a.example/announce
+
+
+# backups
+udp://b.example:1337/announce
+  https://c.example/announce  
+`,
+			want: [][]string{
+				{"https://a.example/announce"},
+				{"udp://b.example:1337/announce", "https://c.example/announce"},
+			},
+		},
+		{
+			name:    "invalid scheme",
+			input:   "ftp://a.example/announce\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing host",
+			input:   "https:///announce\n",
+			wantErr: true,
+		},
+		{
+			name:    "only comments",
+			input:   "# nothing here\n\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAnnounceList(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAnnounceList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAnnounceList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
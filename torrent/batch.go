@@ -1,10 +1,18 @@
 package torrent
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"gopkg.in/yaml.v3"
 
@@ -13,28 +21,43 @@ import (
 
 // BatchConfig represents the YAML configuration for batch torrent creation
 type BatchConfig struct {
-	Jobs    []BatchJob `yaml:"jobs"`
-	Version int        `yaml:"version"`
+	Jobs            []BatchJob        `yaml:"jobs"`
+	SourceMap       map[string]string `yaml:"source_map"`        // applies to jobs without their own source_map
+	PathsRelativeTo string            `yaml:"paths_relative_to"` // "config" (default) or "cwd"
+	Version         int               `yaml:"version"`
 }
 
+// values for BatchConfig.PathsRelativeTo
+const (
+	pathsRelativeToConfig = "config"
+	pathsRelativeToCWD    = "cwd"
+)
+
 // BatchJob represents a single torrent creation job within a batch
 type BatchJob struct {
-	Output              string   `yaml:"output"`
-	Path                string   `yaml:"path"`
-	Name                string   `yaml:"-"`
-	Comment             string   `yaml:"comment"`
-	Source              string   `yaml:"source"`
-	Trackers            []string `yaml:"trackers"`
-	WebSeeds            []string `yaml:"webseeds"`
-	ExcludePatterns     []string `yaml:"exclude_patterns"`
-	IncludePatterns     []string `yaml:"include_patterns"`
-	PieceLength         uint     `yaml:"piece_length"`
-	TargetPieceCount    uint     `yaml:"target_piece_count"`
-	Private             bool     `yaml:"private"`
-	NoDate              bool     `yaml:"no_date"`
-	SkipPrefix          bool     `yaml:"skip_prefix"`
-	Entropy             bool     `yaml:"entropy"`
-	FailOnSeasonWarning bool     `yaml:"fail_on_season_warning"`
+	Output              string            `yaml:"output"`
+	Path                string            `yaml:"path"`
+	Name                string            `yaml:"-"`
+	Comment             string            `yaml:"comment"`
+	Source              string            `yaml:"source"` // may contain {tracker}
+	Trackers            []string          `yaml:"trackers"`
+	WebSeeds            []string          `yaml:"webseeds"`
+	ExcludePatterns     []string          `yaml:"exclude_patterns"`
+	IncludePatterns     []string          `yaml:"include_patterns"`
+	ExcludeRegex        []string          `yaml:"exclude_regex"`
+	IncludeRegex        []string          `yaml:"include_regex"`
+	PieceLength         uint              `yaml:"piece_length"`
+	MaxPieceLength      uint              `yaml:"max_piece_length"`
+	TargetPieceCount    uint              `yaml:"target_piece_count"`
+	Private             bool              `yaml:"private"`
+	NoDate              bool              `yaml:"no_date"`
+	SkipPrefix          bool              `yaml:"skip_prefix"`
+	Entropy             bool              `yaml:"entropy"`
+	Padded              bool              `yaml:"padded"`
+	FailOnSeasonWarning bool              `yaml:"fail_on_season_warning"`
+	Force               bool              `yaml:"force"` // overwrite an existing output file
+	SourceMap           map[string]string `yaml:"source_map"`
+	Exec                string            `yaml:"exec"` // command run after the torrent is written, see RunExec
 }
 
 // ToCreateOptions converts a BatchJob to CreateOptions
@@ -54,9 +77,15 @@ func (j *BatchJob) ToCreateOptions(verbose bool, quiet bool, infoOnly bool, vers
 		Version:                 version,
 		SkipPrefix:              j.SkipPrefix,
 		Entropy:                 j.Entropy,
+		Padded:                  j.Padded,
+		SourceMap:               j.SourceMap,
 		ExcludePatterns:         j.ExcludePatterns,
 		IncludePatterns:         j.IncludePatterns,
+		ExcludeRegex:            j.ExcludeRegex,
+		IncludeRegex:            j.IncludeRegex,
 		FailOnSeasonPackWarning: j.FailOnSeasonWarning,
+		Force:                   j.Force,
+		Batch:                   true,
 	}
 
 	if j.PieceLength != 0 {
@@ -64,6 +93,11 @@ func (j *BatchJob) ToCreateOptions(verbose bool, quiet bool, infoOnly bool, vers
 		opts.PieceLengthExp = &pieceLen
 	}
 
+	if j.MaxPieceLength != 0 {
+		maxPieceLen := j.MaxPieceLength
+		opts.MaxPieceLength = &maxPieceLen
+	}
+
 	if j.TargetPieceCount != 0 {
 		count := j.TargetPieceCount
 		opts.TargetPieceCount = &count
@@ -81,17 +115,30 @@ type BatchResult struct {
 	Success  bool
 }
 
+// errJobSkipped is recorded for jobs that were not started because an earlier job failed
+var errJobSkipped = errors.New("skipped after an earlier job failed")
+
 // ProcessBatch processes a batch configuration file and creates multiple torrents.
 // It reads a YAML configuration file containing multiple torrent creation jobs
 // and processes them in parallel for efficient batch operations.
-func ProcessBatch(configPath string, verbose bool, quiet bool, infoOnly bool, version string) ([]BatchResult, error) {
+//
+// A job that fails is recorded as a failed BatchResult and the remaining jobs still
+// run, so every job has a result. With stopOnError, jobs run one at a time and the
+// jobs after the first failure are skipped. Only invalid configuration returns an error.
+func ProcessBatch(configPath string, verbose bool, quiet bool, infoOnly bool, stopOnError bool, version string) ([]BatchResult, error) {
+	return ProcessBatchContext(context.Background(), configPath, verbose, quiet, infoOnly, stopOnError, version)
+}
+
+// ProcessBatchContext is ProcessBatch with a context. When ctx is done, running jobs
+// stop as in CreateContext and the jobs not started yet fail with ErrCancelled.
+func ProcessBatchContext(ctx context.Context, configPath string, verbose bool, quiet bool, infoOnly bool, stopOnError bool, version string) ([]BatchResult, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read batch config: %w", err)
 	}
 
-	var config BatchConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	config, err := parseBatchConfig(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse batch config: %w", err)
 	}
 
@@ -103,18 +150,39 @@ func ProcessBatch(configPath string, verbose bool, quiet bool, infoOnly bool, ve
 		return nil, fmt.Errorf("no jobs defined in batch config")
 	}
 
+	// relative job paths are resolved against the batch file's directory so batch
+	// files work from anywhere, unless the file asks for the working directory
+	switch config.PathsRelativeTo {
+	case "", pathsRelativeToConfig:
+		baseDir := filepath.Dir(configPath)
+		for i := range config.Jobs {
+			config.Jobs[i].Path = resolveBatchPath(baseDir, config.Jobs[i].Path)
+			config.Jobs[i].Output = resolveBatchPath(baseDir, config.Jobs[i].Output)
+		}
+	case pathsRelativeToCWD:
+	default:
+		return nil, fmt.Errorf("invalid paths_relative_to %q: must be %q or %q", config.PathsRelativeTo, pathsRelativeToConfig, pathsRelativeToCWD)
+	}
+
 	// validate all jobs before processing
-	for _, job := range config.Jobs {
+	for i, job := range config.Jobs {
 		if err := validateJob(job); err != nil {
 			return nil, fmt.Errorf("invalid job configuration: %w", err)
 		}
+		if job.SourceMap == nil {
+			config.Jobs[i].SourceMap = config.SourceMap
+		}
 	}
 
 	results := make([]BatchResult, len(config.Jobs))
 	var wg sync.WaitGroup
+	var failed atomic.Bool
 
 	// process jobs in parallel with a worker pool
 	workers := min(len(config.Jobs), 4) // limit concurrent jobs
+	if stopOnError {
+		workers = 1 // run in order so nothing after the first failure starts
+	}
 	jobs := make(chan int, len(config.Jobs))
 
 	// start workers
@@ -123,7 +191,18 @@ func ProcessBatch(configPath string, verbose bool, quiet bool, infoOnly bool, ve
 		go func() {
 			defer wg.Done()
 			for idx := range jobs {
-				results[idx] = processJob(config.Jobs[idx], verbose, quiet, infoOnly, version)
+				if err := ctxErr(ctx); err != nil {
+					results[idx] = BatchResult{Job: config.Jobs[idx], Trackers: config.Jobs[idx].Trackers, Error: err}
+					continue
+				}
+				if stopOnError && failed.Load() {
+					results[idx] = BatchResult{Job: config.Jobs[idx], Trackers: config.Jobs[idx].Trackers, Error: errJobSkipped}
+					continue
+				}
+				results[idx] = processJobSafe(ctx, config.Jobs[idx], verbose, quiet, infoOnly, version)
+				if !results[idx].Success {
+					failed.Store(true)
+				}
 			}
 		}()
 	}
@@ -138,6 +217,36 @@ func ProcessBatch(configPath string, verbose bool, quiet bool, infoOnly bool, ve
 	return results, nil
 }
 
+// unknownFieldPattern matches yaml.v3's message for a key that is not in the target struct
+var unknownFieldPattern = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// parseBatchConfig decodes a batch file, rejecting keys that are not part of the format
+// so a typo like "tracker:" for "trackers:" fails instead of being silently ignored.
+// Errors name the unknown key and its line.
+func parseBatchConfig(data []byte) (BatchConfig, error) {
+	var config BatchConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			for i, msg := range typeErr.Errors {
+				typeErr.Errors[i] = unknownFieldPattern.ReplaceAllString(msg, `unknown field "$1"`)
+			}
+		}
+		return BatchConfig{}, err
+	}
+	return config, nil
+}
+
+// resolveBatchPath joins a relative path onto baseDir, leaving empty and absolute paths as is
+func resolveBatchPath(baseDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
 func validateJob(job BatchJob) error {
 	if job.Path == "" {
 		return fmt.Errorf("path is required")
@@ -151,18 +260,35 @@ func validateJob(job BatchJob) error {
 		return fmt.Errorf("output is required")
 	}
 
-	if job.PieceLength != 0 && (job.PieceLength < 14 || job.PieceLength > 24) {
-		return fmt.Errorf("piece length must be between 14 and 24")
-	}
-
 	if job.PieceLength != 0 && job.TargetPieceCount != 0 {
 		return fmt.Errorf("cannot set both piece_length and target_piece_count; use one or the other")
 	}
 
+	if err := preset.ValidateSourcePattern(job.Source); err != nil {
+		return err
+	}
+
+	if job.Exec != "" {
+		if err := ValidateExec(job.Exec); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func processJob(job BatchJob, verbose bool, quiet bool, infoOnly bool, version string) BatchResult {
+// processJobSafe runs processJob, turning a panic into a failed result so one
+// job can't take down the rest of the batch
+func processJobSafe(ctx context.Context, job BatchJob, verbose bool, quiet bool, infoOnly bool, version string) (result BatchResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = BatchResult{Job: job, Trackers: job.Trackers, Error: fmt.Errorf("job panicked: %v", r)}
+		}
+	}()
+	return processJob(ctx, job, verbose, quiet, infoOnly, version)
+}
+
+func processJob(ctx context.Context, job BatchJob, verbose bool, quiet bool, infoOnly bool, version string) BatchResult {
 	result := BatchResult{
 		Job:      job,
 		Trackers: job.Trackers,
@@ -193,35 +319,85 @@ func processJob(job BatchJob, verbose bool, quiet bool, infoOnly bool, version s
 	// convert job to CreateOptions
 	opts := job.ToCreateOptions(verbose, quiet, infoOnly, version)
 
+	// piece lengths depend on the job's tracker, so an invalid one only fails this job
+	if err := ValidatePieceLength(opts.PieceLengthExp, opts.MaxPieceLength, firstTracker(job.Trackers)); err != nil {
+		result.Error = fmt.Errorf("invalid piece length: %w", err)
+		return result
+	}
+
+	// fail before hashing when the output already exists
+	if !job.Force {
+		if err := checkOutputFree(output); err != nil {
+			result.Error = fmt.Errorf("failed to create output file: %w", err)
+			return result
+		}
+	}
+
 	// create the torrent
-	mi, err := CreateTorrent(opts)
+	mi, err := CreateTorrentContext(ctx, opts)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create torrent: %w", err)
 		return result
 	}
 
 	// write the torrent file
-	f, err := os.Create(output)
+	f, err := createOutputFile(output, job.Force)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create output file: %w", err)
 		return result
 	}
-	defer f.Close()
-
-	if err := mi.Write(f); err != nil {
+	if err := writeOutputFile(ctx, f, mi); err != nil {
 		result.Error = fmt.Errorf("failed to write torrent file: %w", err)
 		return result
 	}
+	if err := checkWritten(output, mi.HashInfoBytes()); err != nil {
+		result.Error = err
+		return result
+	}
 
 	// collect torrent info
 	info := mi.GetInfo()
 	result.Success = true
 	result.Info = &TorrentInfo{
-		Path:     output,
-		Size:     info.TotalLength(),
-		InfoHash: mi.HashInfoBytes().String(),
-		Files:    len(info.Files),
+		MetaInfo:    mi.MetaInfo,
+		Path:        output,
+		Size:        info.TotalLength(),
+		InfoHash:    mi.HashInfoBytes().String(),
+		Files:       len(info.Files),
+		Source:      info.Source,
+		Private:     info.Private != nil && *info.Private,
+		PieceLength: info.PieceLength,
+	}
+
+	if job.Exec != "" {
+		out, err := RunExec(job.Exec, result.Info)
+		if verbose && len(out) > 0 {
+			// written in one go so output of concurrent jobs doesn't interleave
+			os.Stdout.Write(append([]byte(fmt.Sprintf("exec output for %s:\n", output)), out...))
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = fmt.Errorf("torrent written but post-create command failed: %w", err)
+		}
 	}
 
 	return result
 }
+
+// ReadPathList reads newline-delimited content paths, e.g. from stdin.
+// Blank lines are skipped and surrounding whitespace is trimmed.
+func ReadPathList(r io.Reader) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read path list: %w", err)
+	}
+	return paths, nil
+}
@@ -1,9 +1,13 @@
 package torrent
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -50,14 +54,12 @@ func TestProcessBatch(t *testing.T) {
 jobs:
   - output: %s
     path: %s
-    name: "Test File 1"
     trackers:
       - udp://tracker.example.com:1337/announce
     private: true
     piece_length: 16
   - output: %s
     path: %s
-    name: "Test Directory"
     trackers:
       - udp://tracker.example.com:1337/announce
     webseeds:
@@ -74,7 +76,7 @@ jobs:
 	}
 
 	// process batch
-	results, err := ProcessBatch(configPath, true, false, false, "test-version")
+	results, err := ProcessBatch(configPath, true, false, false, false, "test-version")
 	if err != nil {
 		t.Fatalf("ProcessBatch failed: %v", err)
 	}
@@ -158,7 +160,7 @@ jobs:
 		t.Fatalf("Failed to write config file: %v", err)
 	}
 
-	results, err := ProcessBatch(configPath, false, false, false, "test-version")
+	results, err := ProcessBatch(configPath, false, false, false, false, "test-version")
 	if err != nil {
 		t.Fatalf("ProcessBatch failed: %v", err)
 	}
@@ -179,11 +181,149 @@ jobs:
 	}
 }
 
+func TestBatchSourceTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFile := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(testFile, []byte("test content for source templates"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// the top-level source_map applies to jobs without their own
+	configPath := filepath.Join(tmpDir, "batch.yaml")
+	configContent := []byte(fmt.Sprintf(`version: 1
+source_map:
+  mapped.org: MAPPED
+jobs:
+  - output: %s
+    path: %s
+    source: "{tracker}"
+    trackers:
+      - https://tracker.example.com/announce
+  - output: %s
+    path: %s
+    source: "{tracker}"
+    trackers:
+      - https://tracker.mapped.org/announce
+  - output: %s
+    path: %s
+    trackers:
+      - https://tracker.mapped.org/announce
+    source_map:
+      mapped.org: JOB
+`,
+		filepath.Join(tmpDir, "example.torrent"), testFile,
+		filepath.Join(tmpDir, "mapped.torrent"), testFile,
+		filepath.Join(tmpDir, "job.torrent"), testFile))
+
+	if err := os.WriteFile(configPath, configContent, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+	if err != nil {
+		t.Fatalf("ProcessBatch failed: %v", err)
+	}
+
+	wantSources := []string{"example", "MAPPED", "JOB"}
+	for i, result := range results {
+		if !result.Success {
+			t.Fatalf("Job %d failed: %v", i, result.Error)
+		}
+		mi, err := LoadFromFile(result.Info.Path)
+		if err != nil {
+			t.Fatalf("Failed to load torrent %s: %v", result.Info.Path, err)
+		}
+		if got := mi.GetInfo().Source; got != wantSources[i] {
+			t.Errorf("job %d: source = %q, want %q", i, got, wantSources[i])
+		}
+	}
+}
+
+func TestBatchRelativePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "configs")
+	contentDir := filepath.Join(tmpDir, "content")
+	for _, dir := range []string{configDir, contentDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, "file.txt"), []byte("relative path content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		setting    string
+		cwd        string
+		path       string
+		wantOutput string
+	}{
+		{
+			name:       "default resolves against batch file",
+			path:       "../content/file.txt",
+			cwd:        contentDir,
+			wantOutput: filepath.Join(configDir, "out.torrent"),
+		},
+		{
+			name:       "config resolves against batch file",
+			setting:    "paths_relative_to: config\n",
+			path:       "../content/file.txt",
+			cwd:        contentDir,
+			wantOutput: filepath.Join(configDir, "out.torrent"),
+		},
+		{
+			name:       "cwd resolves against working directory",
+			setting:    "paths_relative_to: cwd\n",
+			path:       "file.txt",
+			cwd:        contentDir,
+			wantOutput: filepath.Join(contentDir, "out.torrent"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(tt.cwd)
+
+			configPath := filepath.Join(configDir, "batch.yaml")
+			config := fmt.Sprintf("version: 1\n%sjobs:\n  - output: out.torrent\n    path: %s\n", tt.setting, tt.path)
+			if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+			if err != nil {
+				t.Fatalf("ProcessBatch failed: %v", err)
+			}
+			if !results[0].Success {
+				t.Fatalf("job failed: %v", results[0].Error)
+			}
+			if got, _ := filepath.Abs(results[0].Info.Path); got != tt.wantOutput {
+				t.Errorf("output = %q, want %q", got, tt.wantOutput)
+			}
+			if _, err := os.Stat(tt.wantOutput); err != nil {
+				t.Errorf("expected torrent at %q: %v", tt.wantOutput, err)
+			}
+			os.Remove(tt.wantOutput)
+		})
+	}
+
+	configPath := filepath.Join(configDir, "batch.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\npaths_relative_to: home\njobs:\n  - output: out.torrent\n    path: file.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessBatch(configPath, false, true, false, false, "test-version"); err == nil {
+		t.Error("expected an error for an invalid paths_relative_to")
+	}
+}
+
 func TestBatchValidation(t *testing.T) {
 	tests := []struct {
 		name        string
 		config      string
 		expectError bool
+		wantErr     string
 	}{
 		{
 			name: "invalid version",
@@ -208,29 +348,41 @@ jobs:
 			expectError: true,
 		},
 		{
-			name: "invalid piece length",
+			name: "empty jobs",
+			config: `version: 1
+jobs: []`,
+			expectError: true,
+		},
+		{
+			name: "piece_length and target_piece_count conflict",
 			config: `version: 1
 jobs:
   - output: test.torrent
     path: test.txt
-    piece_length: 25`,
+    piece_length: 20
+    target_piece_count: 1000`,
 			expectError: true,
 		},
 		{
-			name: "empty jobs",
+			name: "unknown job field",
 			config: `version: 1
-jobs: []`,
+jobs:
+  - output: test.torrent
+    path: test.txt
+    tracker: https://example.com/announce`,
 			expectError: true,
+			wantErr:     `line 5: unknown field "tracker"`,
 		},
 		{
-			name: "piece_length and target_piece_count conflict",
+			name: "unknown top-level field",
 			config: `version: 1
+source-map:
+  example.com: EX
 jobs:
   - output: test.torrent
-    path: test.txt
-    piece_length: 20
-    target_piece_count: 1000`,
+    path: test.txt`,
 			expectError: true,
+			wantErr:     `line 2: unknown field "source-map"`,
 		},
 	}
 
@@ -247,7 +399,10 @@ jobs:
 				t.Fatalf("Failed to write config file: %v", err)
 			}
 
-			_, err = ProcessBatch(configPath, false, false, false, "test-version")
+			_, err = ProcessBatch(configPath, false, false, false, false, "test-version")
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got nil")
 			}
@@ -257,3 +412,357 @@ jobs:
 		})
 	}
 }
+
+// TestProcessBatch_UsesCreateHasher checks that batch jobs go through the same
+// CreateTorrent and piece hasher path as single torrent creation.
+func TestProcessBatch_UsesCreateHasher(t *testing.T) {
+	tmpDir := t.TempDir()
+	packDir := filepath.Join(tmpDir, "Show.S01.1080p")
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"Show.S01E01.mkv", "Show.S01E03.mkv"} {
+		if err := os.WriteFile(filepath.Join(packDir, name), []byte("episode "+name), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	job := BatchJob{
+		Output:              filepath.Join(tmpDir, "pack.torrent"),
+		Path:                packDir,
+		Private:             true,
+		FailOnSeasonWarning: true,
+	}
+
+	opts := job.ToCreateOptions(false, true, false, "test-version")
+	if !opts.Batch {
+		t.Error("Expected batch jobs to mark their display as batch")
+	}
+
+	_, createErr := CreateTorrent(opts)
+	if createErr == nil {
+		t.Fatal("Expected CreateTorrent to fail on incomplete season pack")
+	}
+
+	configPath := filepath.Join(tmpDir, "batch.yaml")
+	configContent := fmt.Sprintf(`version: 1
+jobs:
+  - output: %s
+    path: %s
+    private: true
+    fail_on_season_warning: true
+`, job.Output, job.Path)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+	if err != nil {
+		t.Fatalf("ProcessBatch failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Success {
+		t.Fatalf("Expected single failed result, got %+v", results)
+	}
+	if !strings.Contains(results[0].Error.Error(), createErr.Error()) {
+		t.Errorf("Expected batch error %q to wrap create error %q", results[0].Error, createErr)
+	}
+}
+
+// TestHashDisplay checks that batch jobs and single torrents get their hasher
+// display from the same helper, differing only in the batch flag.
+func TestHashDisplay(t *testing.T) {
+	job := BatchJob{Output: "pack.torrent", Path: "pack"}
+
+	batchDisplay, ok := hashDisplay(job.ToCreateOptions(true, false, false, "test-version")).(*Display)
+	if !ok {
+		t.Fatal("Expected a batch job to hash with *Display")
+	}
+	if !batchDisplay.isBatch {
+		t.Error("Expected the batch job display to be in batch mode")
+	}
+	if !batchDisplay.formatter.verbose {
+		t.Error("Expected the batch job display to keep the verbose flag")
+	}
+
+	singleDisplay, ok := hashDisplay(CreateOptions{Path: "pack"}).(*Display)
+	if !ok {
+		t.Fatal("Expected a single torrent to hash with *Display")
+	}
+	if singleDisplay.isBatch {
+		t.Error("Expected the single torrent display not to be in batch mode")
+	}
+
+	callback := hashDisplay(CreateOptions{Path: "pack", ProgressCallback: func(int, int, float64) {}})
+	if _, ok := callback.(*callbackDisplayer); !ok {
+		t.Errorf("Expected a progress callback to hash with *callbackDisplayer, got %T", callback)
+	}
+}
+
+func TestReadPathList(t *testing.T) {
+	input := "/data/one\n\n  /data/two  \r\n\t\n/data/with space\n"
+
+	paths, err := ReadPathList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadPathList failed: %v", err)
+	}
+
+	expected := []string{"/data/one", "/data/two", "/data/with space"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %d paths, got %d: %v", len(expected), len(paths), paths)
+	}
+	for i, p := range expected {
+		if paths[i] != p {
+			t.Errorf("path %d: expected %q, got %q", i, p, paths[i])
+		}
+	}
+}
+
+func TestProcessBatch_FailedJobDoesNotStopBatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, name := range []string{"good1", "bad", "good2"} {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "file.bin"), []byte("content of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// root and Windows can still read a file without permissions, so there the bad
+	// job writes into a missing directory instead
+	badOutput := filepath.Join(tmpDir, "bad.torrent")
+	if os.Geteuid() == 0 || runtime.GOOS == "windows" {
+		badOutput = filepath.Join(tmpDir, "missing", "bad.torrent")
+	} else {
+		unreadable := filepath.Join(tmpDir, "bad", "file.bin")
+		if err := os.Chmod(unreadable, 0); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(unreadable, 0644) })
+	}
+
+	// the subtests run the same batch, so the jobs overwrite each other's output
+	configPath := filepath.Join(tmpDir, "batch.yaml")
+	config := fmt.Sprintf(`version: 1
+jobs:
+  - output: %s
+    path: %s
+    force: true
+  - output: %s
+    path: %s
+    force: true
+  - output: %s
+    path: %s
+    force: true
+`,
+		filepath.Join(tmpDir, "good1.torrent"), filepath.Join(tmpDir, "good1"),
+		badOutput, filepath.Join(tmpDir, "bad"),
+		filepath.Join(tmpDir, "good2.torrent"), filepath.Join(tmpDir, "good2"))
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("continue", func(t *testing.T) {
+		results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+		if err != nil {
+			t.Fatalf("ProcessBatch failed: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		if !results[0].Success || !results[2].Success {
+			t.Errorf("expected good jobs to succeed, got %v and %v", results[0].Error, results[2].Error)
+		}
+		if results[1].Success || results[1].Error == nil {
+			t.Error("expected bad job to fail with an error")
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "good2.torrent")); err != nil {
+			t.Errorf("expected job after the failure to write its torrent: %v", err)
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		os.Remove(filepath.Join(tmpDir, "good2.torrent"))
+
+		results, err := ProcessBatch(configPath, false, true, false, true, "test-version")
+		if err != nil {
+			t.Fatalf("ProcessBatch failed: %v", err)
+		}
+		if !results[0].Success {
+			t.Errorf("expected first job to succeed, got %v", results[0].Error)
+		}
+		if results[1].Success {
+			t.Error("expected bad job to fail")
+		}
+		if results[2].Success || results[2].Error != errJobSkipped {
+			t.Errorf("expected job after the failure to be skipped, got %v", results[2].Error)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "good2.torrent")); !os.IsNotExist(err) {
+			t.Errorf("expected skipped job not to write its torrent, got %v", err)
+		}
+	})
+}
+
+// TestProcessBatch_InvalidPieceLengthFailsJob checks that piece lengths are validated
+// against each job's tracker and an invalid one fails only that job
+func TestProcessBatch_InvalidPieceLengthFailsJob(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(content, make([]byte, 1<<16), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// morethantv.me allows at most 2^23, passthepopcorn.me 2^24
+	configPath := filepath.Join(tmpDir, "batch.yaml")
+	config := `version: 1
+jobs:
+  - output: valid.torrent
+    path: content.bin
+    trackers: [https://passthepopcorn.me/announce]
+    piece_length: 24
+  - output: too-large.torrent
+    path: content.bin
+    trackers: [https://morethantv.me/announce]
+    piece_length: 24
+  - output: max-too-large.torrent
+    path: content.bin
+    trackers: [https://morethantv.me/announce]
+    max_piece_length: 24
+  - output: valid-max.torrent
+    path: content.bin
+    max_piece_length: 20
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+	if err != nil {
+		t.Fatalf("ProcessBatch failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, i := range []int{0, 3} {
+		if !results[i].Success {
+			t.Errorf("job %d: expected success, got %v", i, results[i].Error)
+		}
+	}
+	if results[0].Success && results[0].Info.PieceLength != 1<<24 {
+		t.Errorf("job 0: piece length = %d, want %d", results[0].Info.PieceLength, 1<<24)
+	}
+	for i, output := range map[int]string{1: "too-large.torrent", 2: "max-too-large.torrent"} {
+		if results[i].Success {
+			t.Errorf("job %d: expected failure", i)
+			continue
+		}
+		if !errors.Is(results[i].Error, ErrPieceLengthOutOfRange) {
+			t.Errorf("job %d: error = %v, want ErrPieceLengthOutOfRange", i, results[i].Error)
+		}
+		if !strings.Contains(results[i].Error.Error(), "between") {
+			t.Errorf("job %d: error %q does not name the allowed range", i, results[i].Error)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, output)); !os.IsNotExist(err) {
+			t.Errorf("job %d: expected no torrent to be written, got %v", i, err)
+		}
+	}
+}
+
+func TestProcessBatch_ExistingOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(content, []byte("existing output test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"kept.torrent", "forced.torrent"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("previous"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configPath := filepath.Join(tmpDir, "batch.yaml")
+	config := `version: 1
+jobs:
+  - output: kept.torrent
+    path: content.bin
+  - output: forced.torrent
+    path: content.bin
+    force: true
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ProcessBatch(configPath, false, true, false, false, "test-version")
+	if err != nil {
+		t.Fatalf("ProcessBatch failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !errors.Is(results[0].Error, ErrOutputExists) {
+		t.Errorf("job 0: error = %v, want ErrOutputExists", results[0].Error)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "kept.torrent")); string(data) != "previous" {
+		t.Error("job 0: existing output file was changed")
+	}
+	if !results[1].Success {
+		t.Fatalf("job 1: expected success, got %v", results[1].Error)
+	}
+	if _, err := LoadFromFile(filepath.Join(tmpDir, "forced.torrent")); err != nil {
+		t.Errorf("job 1: expected the output to be overwritten with a torrent: %v", err)
+	}
+}
+
+func TestWriteBatchReport(t *testing.T) {
+	results := []BatchResult{
+		{
+			Job:     BatchJob{Path: "/data/Show.S01"},
+			Info:    &TorrentInfo{Path: "/torrents/Show.S01.torrent", InfoHash: "0123456789abcdef0123456789abcdef01234567", Size: 1 << 30},
+			Success: true,
+		},
+		{
+			Job:   BatchJob{Path: "/data/missing, with comma"},
+			Error: errors.New("error checking path: no such file"),
+		},
+	}
+	tmpDir := t.TempDir()
+
+	jsonPath := filepath.Join(tmpDir, "report.json")
+	if err := WriteBatchReport(jsonPath, results); err != nil {
+		t.Fatalf("WriteBatchReport(json) failed: %v", err)
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []BatchReportEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, data)
+	}
+	want := []BatchReportEntry{
+		{Path: "/data/Show.S01", Output: "/torrents/Show.S01.torrent", InfoHash: "0123456789abcdef0123456789abcdef01234567", Size: 1 << 30, Success: true},
+		{Path: "/data/missing, with comma", Error: "error checking path: no such file"},
+	}
+	if len(entries) != len(want) || entries[0] != want[0] || entries[1] != want[1] {
+		t.Errorf("Expected entries %+v, got %+v", want, entries)
+	}
+
+	csvPath := filepath.Join(tmpDir, "report.CSV")
+	if err := WriteBatchReport(csvPath, results); err != nil {
+		t.Fatalf("WriteBatchReport(csv) failed: %v", err)
+	}
+	data, err = os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `path,output,info_hash,size,success,error
+/data/Show.S01,/torrents/Show.S01.torrent,0123456789abcdef0123456789abcdef01234567,1073741824,true,
+"/data/missing, with comma",,,0,false,error checking path: no such file
+`
+	if string(data) != wantCSV {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", wantCSV, data)
+	}
+}
@@ -0,0 +1,85 @@
+package torrent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BatchReportEntry is the outcome of one batch job as written by WriteBatchReport
+type BatchReportEntry struct {
+	Path     string `json:"path"`
+	Output   string `json:"output,omitempty"`
+	InfoHash string `json:"infoHash,omitempty"`
+	Size     int64  `json:"size"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NewBatchReport turns batch results into report entries, in job order
+func NewBatchReport(results []BatchResult) []BatchReportEntry {
+	entries := make([]BatchReportEntry, 0, len(results))
+	for _, result := range results {
+		entry := BatchReportEntry{
+			Path:    result.Job.Path,
+			Success: result.Success,
+		}
+		if result.Info != nil {
+			entry.Output = result.Info.Path
+			entry.InfoHash = result.Info.InfoHash
+			entry.Size = result.Info.Size
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// WriteBatchReport writes the outcome of each job to path, as CSV when the file name
+// ends in .csv and as a JSON array otherwise
+func WriteBatchReport(path string, results []BatchResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create report: %w", err)
+	}
+	defer f.Close()
+
+	entries := NewBatchReport(results)
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = writeBatchReportCSV(f, entries)
+	} else {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+	return nil
+}
+
+// writeBatchReportCSV writes entries as CSV with a header row
+func writeBatchReportCSV(w io.Writer, entries []BatchReportEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"path", "output", "info_hash", "size", "success", "error"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{e.Path, e.Output, e.InfoHash, strconv.FormatInt(e.Size, 10), strconv.FormatBool(e.Success), e.Error}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,71 @@
+package torrent
+
+import (
+	"fmt"
+
+	"github.com/anacrolix/torrent/bencode"
+)
+
+// canonicalBencode re-encodes a bencoded value with dictionary keys sorted, as
+// BEP 3 requires. bencode.Marshal already sorts keys; this pass keeps written
+// torrents byte-identical for equal input even if a value was encoded elsewhere
+// or the library changes its ordering.
+func canonicalBencode(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("could not decode bencode: empty value")
+	}
+
+	// decoding into interface values rejects unsorted keys, so containers are
+	// decoded as raw values and each element is canonicalized on its own
+	switch data[0] {
+	case 'd':
+		var dict map[string]bencode.Bytes
+		if err := bencode.Unmarshal(data, &dict); err != nil {
+			return nil, fmt.Errorf("could not decode bencode: %w", err)
+		}
+		for key, value := range dict {
+			canonical, err := canonicalBencode(value)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = canonical
+		}
+		return bencode.Marshal(dict)
+	case 'l':
+		var list []bencode.Bytes
+		if err := bencode.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("could not decode bencode: %w", err)
+		}
+		for i, value := range list {
+			canonical, err := canonicalBencode(value)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = canonical
+		}
+		return bencode.Marshal(list)
+	default:
+		var v any
+		if err := bencode.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("could not decode bencode: %w", err)
+		}
+		return bencode.Marshal(v)
+	}
+}
+
+// canonicalMetaInfo encodes the top-level keys of a torrent in canonical form.
+// The info dictionary is kept as is, since re-encoding a non-canonical one from
+// another tool would change its info hash.
+func canonicalMetaInfo(root map[string]bencode.Bytes) ([]byte, error) {
+	for key, value := range root {
+		if key == "info" {
+			continue
+		}
+		canonical, err := canonicalBencode(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q: %w", key, err)
+		}
+		root[key] = canonical
+	}
+	return bencode.Marshal(root)
+}
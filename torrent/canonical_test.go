@@ -0,0 +1,118 @@
+package torrent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestCanonicalBencode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"sorted dict unchanged", "d1:ai1e1:bi2ee", "d1:ai1e1:bi2ee"},
+		{"unsorted dict", "d1:bi2e1:ai1ee", "d1:ai1e1:bi2ee"},
+		{"nested", "d1:zl1:xd1:qi0e1:pi0eee1:a0:e", "d1:a0:1:zl1:xd1:pi0e1:qi0eeee"},
+		{"string", "4:spam", "4:spam"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalBencode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("canonicalBencode(%q) error: %v", tt.input, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("canonicalBencode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := canonicalBencode([]byte("d1:a")); err == nil {
+		t.Error("canonicalBencode() on truncated input should fail")
+	}
+}
+
+func TestWriteMetaInfo_KeepsNonCanonicalInfo(t *testing.T) {
+	// info dictionaries from other tools are hashed as they are, even with unsorted keys
+	info := []byte("d6:lengthi1e4:name1:x12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaae")
+	mi := &metainfo.MetaInfo{InfoBytes: info, Announce: "https://example.com/announce"}
+
+	var buf bytes.Buffer
+	if err := writeMetaInfo(&buf, mi, nil); err != nil {
+		t.Fatalf("writeMetaInfo() error: %v", err)
+	}
+
+	var decoded metainfo.MetaInfo
+	if err := bencode.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("written torrent does not decode: %v", err)
+	}
+	if !bytes.Equal(decoded.InfoBytes, info) {
+		t.Errorf("info bytes changed: got %q, want %q", decoded.InfoBytes, info)
+	}
+	if decoded.HashInfoBytes() != mi.HashInfoBytes() {
+		t.Error("info hash changed by writeMetaInfo")
+	}
+}
+
+func TestCreateTorrent_ByteIdentical(t *testing.T) {
+	// two separately built torrents from equal content and options must encode identically
+	build := func() []byte {
+		dir := filepath.Join(t.TempDir(), "content")
+		if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range map[string]string{
+			"b.txt":     "second file",
+			"a.txt":     "first file",
+			"sub/c.bin": "nested file",
+		} {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		pieceLength := uint(16)
+		tor, err := CreateTorrent(CreateOptions{
+			Path:           dir,
+			TrackerURLs:    []string{"https://example.com/announce", "https://backup.example.com/announce"},
+			WebSeeds:       []string{"https://seed.example.com/"},
+			Comment:        "reproducible",
+			Source:         "TEST",
+			IsPrivate:      true,
+			PieceLengthExp: &pieceLength,
+			CreationDate:   time.Unix(1700000000, 0),
+			Version:        "test",
+			Quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent() error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := tor.Write(&buf); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	first, second := build(), build()
+	if !bytes.Equal(first, second) {
+		t.Errorf("torrents differ:\n%q\n%q", first, second)
+	}
+
+	canonical, err := canonicalBencode(first)
+	if err != nil {
+		t.Fatalf("canonicalBencode() error: %v", err)
+	}
+	if !bytes.Equal(first, canonical) {
+		t.Errorf("written torrent is not canonical:\n%q\n%q", first, canonical)
+	}
+}
@@ -1,12 +1,16 @@
 package torrent
 
 import (
+	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math/bits"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,8 +32,8 @@ func formatPieceSize(exp uint) string {
 }
 
 // calculatePieceLengthFromTarget derives a piece length exponent from a target piece count.
-// The result is clamped to [minExp, maxExp] where maxExp considers tracker and user constraints.
-func calculatePieceLengthFromTarget(totalSize int64, targetCount uint, maxPieceLength *uint, trackerURLs []string, verbose bool) uint {
+// The result is clamped to [minExp, maxExp] where minExp and maxExp consider tracker and user constraints.
+func calculatePieceLengthFromTarget(totalSize int64, targetCount uint, minPieceLength, maxPieceLength *uint, trackerURLs []string, verbose bool) uint {
 	minExp := uint(16) // 64 KiB minimum
 	maxExp := uint(24) // default max 16 MiB, same as auto-calc
 
@@ -57,6 +61,11 @@ func calculatePieceLengthFromTarget(totalSize int64, targetCount uint, maxPieceL
 	// ensure maxExp is at least minExp
 	maxExp = max(maxExp, minExp)
 
+	// user floor can raise the minimum but never above the ceiling
+	if minPieceLength != nil {
+		minExp = min(max(*minPieceLength, minExp), maxExp)
+	}
+
 	// guard: targetCount == 0 or totalSize < targetCount → ratio would be 0
 	ratio := uint64(0)
 	if targetCount > 0 && totalSize > 0 {
@@ -84,9 +93,41 @@ func calculatePieceLengthFromTarget(totalSize int64, targetCount uint, maxPieceL
 	return clamped
 }
 
-// calculatePieceLength calculates the optimal piece length based on total size.
-// The min/max bounds (2^16 to 2^24) take precedence over other constraints
-func calculatePieceLength(totalSize int64, maxPieceLength *uint, trackerURLs []string, verbose bool) uint {
+// manyFilesThreshold is the file count from which the automatic piece length is raised,
+// one step per tenfold increase (10k files: 2x, 100k files: 4x, ...)
+const manyFilesThreshold = 10_000
+
+// fileCountBias returns how many steps the piece length exponent is raised for fileCount files.
+// Content with many small files otherwise gets small pieces and a large pieces blob on top
+// of the already large file list.
+func fileCountBias(fileCount int) uint {
+	var bias uint
+	for n := manyFilesThreshold; fileCount >= n; n *= 10 {
+		bias++
+	}
+	return bias
+}
+
+// applyFileCountBias raises exp by fileCountBias, bounded by maxExp
+func applyFileCountBias(exp, maxExp uint, fileCount int, verbose bool) uint {
+	bias := fileCountBias(fileCount)
+	if bias == 0 || exp >= maxExp {
+		return exp
+	}
+
+	biased := min(exp+bias, maxExp)
+	if verbose {
+		display := NewDisplay(NewFormatter(verbose))
+		display.ShowMessage(fmt.Sprintf("raising piece length for %d files: using %s pieces instead of %s",
+			fileCount, formatPieceSize(biased), formatPieceSize(exp)))
+	}
+	return biased
+}
+
+// calculatePieceLength calculates the optimal piece length based on total size and file count.
+// The min/max bounds (2^16 to 2^24) take precedence over other constraints.
+// A user minimum raises the result but never above the maximum.
+func calculatePieceLength(totalSize int64, fileCount int, minPieceLength, maxPieceLength *uint, trackerURLs []string, verbose bool) uint {
 	minExp := uint(16)
 	maxExp := uint(24) // default max 16 MiB for automatic calculation, can be overridden up to 2^27
 
@@ -100,6 +141,13 @@ func calculatePieceLength(totalSize int64, maxPieceLength *uint, trackerURLs []s
 		if exp, ok := trackers.GetTrackerPieceSizeExp(trackerURLs[0], uint64(totalSize)); ok {
 			// ensure we stay within bounds
 			exp = min(max(exp, minExp), maxExp)
+			// trackers with their own ranges get exactly what their rules ask for
+			if trackers.UsesDefaultRanges(trackerURLs[0]) {
+				exp = applyFileCountBias(exp, maxExp, fileCount, verbose)
+			}
+			if minPieceLength != nil {
+				exp = max(exp, min(*minPieceLength, maxExp))
+			}
 			if verbose {
 				display := NewDisplay(NewFormatter(verbose))
 				display.ShowMessage(fmt.Sprintf("using tracker-specific range for content size: %d MiB (recommended: %s pieces)",
@@ -128,8 +176,13 @@ func calculatePieceLength(totalSize int64, maxPieceLength *uint, trackerURLs []s
 		}
 	}
 
+	exp = applyFileCountBias(exp, maxExp, fileCount, verbose)
+
 	// ensure we stay within bounds
 	exp = min(exp, maxExp)
+	if minPieceLength != nil {
+		exp = max(exp, min(*minPieceLength, maxExp))
+	}
 
 	return exp
 }
@@ -155,6 +208,112 @@ func GetRecommendedPieceLengthExp(trackerURL string, contentSize uint64) uint {
 	return min(max(exp, minExp), maxExp)
 }
 
+// maxPieceLengthExp returns the largest piece length exponent allowed for trackerURL,
+// 27 (128 MiB) unless the tracker sets a lower limit
+func maxPieceLengthExp(trackerURL string) uint {
+	if trackerURL != "" {
+		if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(trackerURL); ok {
+			return trackerMaxExp
+		}
+	}
+	return 27
+}
+
+// ValidatePieceLength checks an explicit piece length exponent and a max piece length
+// exponent against the limits for trackerURL, which may be empty. Either exponent may
+// be nil; as in CreateTorrent, the max is only checked without an explicit piece length.
+func ValidatePieceLength(pieceLengthExp, maxPieceLength *uint, trackerURL string) error {
+	maxExp := maxPieceLengthExp(trackerURL)
+	if pieceLengthExp != nil {
+		if *pieceLengthExp >= 16 && *pieceLengthExp <= maxExp {
+			return nil
+		}
+		if trackerURL != "" {
+			return withKind(ErrPieceLengthOutOfRange, fmt.Errorf("piece length exponent must be between 16 (64 KiB) and %d (%d MiB) for %s, got: %d",
+				maxExp, 1<<(maxExp-20), trackerURL, *pieceLengthExp))
+		}
+		return withKind(ErrPieceLengthOutOfRange, fmt.Errorf("piece length exponent must be between 16 (64 KiB) and %d (%d MiB), got: %d",
+			maxExp, 1<<(maxExp-20), *pieceLengthExp))
+	}
+	if maxPieceLength != nil && (*maxPieceLength < 14 || *maxPieceLength > maxExp) {
+		return withKind(ErrPieceLengthOutOfRange, fmt.Errorf("max piece length exponent must be between 14 (16 KiB) and %d (%d MiB), got: %d",
+			maxExp, 1<<(maxExp-20), *maxPieceLength))
+	}
+	return nil
+}
+
+// firstTracker returns the first of urls, or "" if there is none
+func firstTracker(urls []string) string {
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
+
+// sizeLimitCeilingExp returns the largest piece length exponent CreateTorrent raises
+// the piece length to when a torrent exceeds trackerURL's .torrent size limit
+func sizeLimitCeilingExp(trackerURL string, maxPieceLength *uint) uint {
+	ceiling := uint(24) // default ceiling
+	trackerMaxExp, hasTrackerCap := trackers.GetTrackerMaxPieceLength(trackerURL)
+	if hasTrackerCap {
+		ceiling = trackerMaxExp
+	}
+	if maxPieceLength != nil {
+		if hasTrackerCap {
+			// tracker cap is a hard ceiling; user can lower but not exceed it
+			ceiling = min(*maxPieceLength, ceiling)
+		} else {
+			// no tracker cap; user can raise above default 24
+			ceiling = min(*maxPieceLength, 27)
+		}
+	}
+	return ceiling
+}
+
+// EstimateTorrentFileSize returns a lower bound for the size of a .torrent file for
+// contentSize bytes in pieces of 2^pieceLengthExp bytes: the 20 byte SHA-1 hash of
+// every piece. The file list, trackers and other fields come on top.
+func EstimateTorrentFileSize(contentSize uint64, pieceLengthExp uint) uint64 {
+	pieceLength := uint64(1) << pieceLengthExp
+	return (contentSize + pieceLength - 1) / pieceLength * 20
+}
+
+// FitTorrentFileSize returns the piece length exponent CreateTorrent ends up with for
+// trackerURL's .torrent size limit, by the estimate of EstimateTorrentFileSize:
+// pieceLengthExp, raised one step at a time while the torrent is too large and the
+// ceiling allows. ok is false if it is still too large at the ceiling. Trackers
+// without a limit always fit.
+func FitTorrentFileSize(trackerURL string, contentSize uint64, pieceLengthExp uint, maxPieceLength *uint) (exp uint, ok bool) {
+	maxSize, hasLimit := trackers.GetTrackerMaxTorrentSize(trackerURL)
+	if !hasLimit {
+		return pieceLengthExp, true
+	}
+	ceiling := sizeLimitCeilingExp(trackerURL, maxPieceLength)
+	exp = pieceLengthExp
+	for EstimateTorrentFileSize(contentSize, exp) > maxSize && exp < ceiling {
+		exp++
+	}
+	return exp, EstimateTorrentFileSize(contentSize, exp) <= maxSize
+}
+
+// smallestFittingExp returns the smallest piece length exponent from minExp up to
+// ceiling whose estimated .torrent size is within maxSize, or ceiling if none is
+func smallestFittingExp(contentSize, maxSize uint64, minExp, ceiling uint) uint {
+	for exp := minExp; exp < ceiling; exp++ {
+		if EstimateTorrentFileSize(contentSize, exp) <= maxSize {
+			return exp
+		}
+	}
+	return max(minExp, ceiling)
+}
+
+// AutoPieceLengthExp returns the piece length exponent create would pick automatically
+// for contentSize bytes in fileCount files announced to trackerURL, falling back to the
+// default ranges for unknown trackers or an empty URL.
+func AutoPieceLengthExp(trackerURL string, contentSize uint64, fileCount int) uint {
+	return calculatePieceLength(int64(contentSize), fileCount, nil, nil, []string{trackerURL}, false)
+}
+
 func (t *Torrent) GetInfo() *metainfo.Info {
 	info := &metainfo.Info{}
 	_ = bencode.Unmarshal(t.InfoBytes, info)
@@ -169,11 +328,127 @@ func generateRandomString() (string, error) {
 	return fmt.Sprintf("%x", b), nil
 }
 
+// torrentPathComponents splits a relative file path into the components stored in
+// the info dictionary. Torrent paths always use forward slashes, so the OS separator
+// is normalized first; empty and "." components (e.g. from trailing slashes) are dropped.
+func torrentPathComponents(relPath string, separator rune) []string {
+	if separator != '/' {
+		relPath = strings.ReplaceAll(relPath, string(separator), "/")
+	}
+
+	parts := strings.Split(relPath, "/")
+	components := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" && part != "." {
+			components = append(components, part)
+		}
+	}
+	return components
+}
+
+// addLegacyUTF8 mirrors the name and file paths into name.utf-8 and path.utf-8.
+// Names are always UTF-8 already, the extra fields are for older clients that
+// only trust those keys. Padding files keep only their regular path.
+func addLegacyUTF8(info *metainfo.Info) {
+	info.NameUtf8 = info.Name
+	for i := range info.Files {
+		if isPaddingFile(info.Files[i]) {
+			continue
+		}
+		info.Files[i].PathUtf8 = info.Files[i].Path
+	}
+}
+
+// topLevelEmptyDirs returns the directories that contain no files, relative to basePath.
+// Directories nested inside an empty directory are not listed separately.
+func topLevelEmptyDirs(dirs []string, dirsWithFiles map[string]bool, basePath string) []string {
+	empty := make(map[string]bool)
+	for _, dir := range dirs {
+		if !dirsWithFiles[dir] {
+			empty[dir] = true
+		}
+	}
+
+	var result []string
+	for _, dir := range dirs {
+		if !empty[dir] || empty[filepath.Dir(dir)] {
+			continue
+		}
+		rel, err := filepath.Rel(basePath, dir)
+		if err != nil {
+			rel = dir
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// privateTrackerForPublicTorrent returns the first tracker that only accepts private
+// torrents when the torrent is being created as public
+func privateTrackerForPublicTorrent(opts CreateOptions) (string, bool) {
+	if opts.IsPrivate {
+		return "", false
+	}
+	for _, tracker := range opts.TrackerURLs {
+		if trackers.RequiresPrivate(tracker) {
+			return tracker, true
+		}
+	}
+	return "", false
+}
+
 // CreateTorrent creates a new torrent file from the given options.
 // Returns a Torrent struct containing the metainfo.
 // This is the lower-level function; use Create() for a higher-level interface.
 func CreateTorrent(opts CreateOptions) (*Torrent, error) {
+	return CreateTorrentContext(context.Background(), opts)
+}
+
+// hashDisplay returns the displayer the piece hasher reports to. Single
+// torrents and batch jobs both get theirs here; a progress callback replaces
+// the terminal output.
+func hashDisplay(opts CreateOptions) Displayer {
+	if opts.ProgressCallback != nil {
+		return &callbackDisplayer{callback: opts.ProgressCallback}
+	}
+	display := NewDisplay(NewFormatter(opts.Verbose || opts.InfoOnly))
+	display.SetQuiet(opts.Quiet || opts.InfoOnly)
+	display.SetBatch(opts.Batch)
+	return display
+}
+
+// CreateTorrentContext is CreateTorrent with a context. When ctx is done, the hashing
+// workers stop after their current piece and an error matching both ErrCancelled and
+// ctx.Err() is returned.
+func CreateTorrentContext(ctx context.Context, opts CreateOptions) (*Torrent, error) {
+	if opts.PathDepth != 0 && len(opts.Sources) > 0 {
+		return nil, fmt.Errorf("cannot use a path depth with sources")
+	}
+	if opts.PathDepth > 0 {
+		// keeping parent directories is the same as adding the path below its ancestor
+		root, rel, err := depthRoot(opts.Path, opts.PathDepth)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Name == "" {
+			opts.Name = filepath.Base(root)
+		}
+		opts.Sources = []SourceSpec{{Path: opts.Path, TorrentPath: rel}}
+		opts.Path = ""
+	}
+
 	path := filepath.ToSlash(opts.Path)
+	if len(opts.Sources) > 0 {
+		if opts.Path != "" {
+			return nil, fmt.Errorf("cannot use both a path and sources; use one or the other")
+		}
+		if opts.Name == "" {
+			return nil, fmt.Errorf("a name is required when creating a torrent from sources")
+		}
+		// the first source stands in for the path in storage detection and messages
+		path = filepath.ToSlash(opts.Sources[0].Path)
+	}
 	name := opts.Name
 	if name == "" {
 		// preserve the folder name even for single-file torrents
@@ -185,7 +460,10 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 	}
 
 	// Set tracker information
-	if len(opts.TrackerURLs) > 0 {
+	if len(opts.AnnounceList) > 0 && len(opts.AnnounceList[0]) > 0 {
+		mi.Announce = opts.AnnounceList[0][0]
+		mi.AnnounceList = opts.AnnounceList
+	} else if len(opts.TrackerURLs) > 0 {
 		mi.Announce = opts.TrackerURLs[0]
 		if len(opts.TrackerURLs) > 1 {
 			announceList := make([][]string, len(opts.TrackerURLs))
@@ -196,134 +474,351 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 		}
 	}
 
-	if !opts.NoCreator {
+	if len(opts.Nodes) > 0 {
+		if opts.IsPrivate {
+			return nil, fmt.Errorf("DHT nodes cannot be used with a private torrent, private torrents don't use DHT")
+		}
+		for _, n := range opts.Nodes {
+			node, err := ParseNode(n)
+			if err != nil {
+				return nil, err
+			}
+			mi.Nodes = append(mi.Nodes, node)
+		}
+	}
+
+	meta, skipped := customFields(opts.Meta, standardRootKeys)
+	if len(skipped) > 0 {
+		display := NewDisplay(NewFormatter(opts.Verbose))
+		display.ShowWarning(fmt.Sprintf("ignoring custom metadata for standard key(s) %s, use the matching flag instead", strings.Join(skipped, ", ")))
+	}
+	infoMeta, skipped := customFields(opts.InfoMeta, standardInfoKeys)
+	if len(skipped) > 0 {
+		display := NewDisplay(NewFormatter(opts.Verbose))
+		display.ShowWarning(fmt.Sprintf("ignoring custom info fields for standard key(s) %s, use the matching flag instead", strings.Join(skipped, ", ")))
+	}
+
+	if opts.CreatedBy != "" {
+		mi.CreatedBy = opts.CreatedBy
+	} else if !opts.NoCreator {
 		mi.CreatedBy = fmt.Sprintf("mkbrr/%s (https://github.com/autobrr/mkbrr)", opts.Version)
 	}
 
-	if !opts.NoDate {
+	if !opts.CreationDate.IsZero() {
+		mi.CreationDate = opts.CreationDate.Unix()
+	} else if !opts.NoDate {
 		mi.CreationDate = time.Now().Unix()
 	}
 
-	files := make([]fileEntry, 0, 1)
-	var totalSize int64
-	var baseDir string
-	originalPaths := make(map[string]string) // map resolved path -> original path for metainfo
-
-	inputInfo, err := os.Stat(path)
-	if err != nil {
-		return nil, fmt.Errorf("error checking path: %w", err)
+	if tracker, ok := privateTrackerForPublicTorrent(opts); ok {
+		msg := fmt.Sprintf("creating a public torrent for private tracker %s", trackerHost(tracker))
+		if opts.Strict {
+			return nil, fmt.Errorf("%s; set the private flag or disable strict mode", msg)
+		}
+		showWarning(msg + ", did you forget --private?")
 	}
 
-	// Clean the base path for computing relative paths
-	cleanBasePath := filepath.Clean(path)
-	matchBasePath := cleanBasePath
-	if !inputInfo.IsDir() {
-		matchBasePath = filepath.Dir(cleanBasePath)
+	// compile regexes before waiting on or walking the content so a typo fails right away
+	filter, err := newPathFilter(opts.ExcludePatterns, opts.IncludePatterns, opts.ExcludeRegex, opts.IncludeRegex)
+	if err != nil {
+		return nil, err
 	}
 
-	err = filepath.Walk(path, func(currentPath string, walkInfo os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			// check if the error is due to a broken symlink during walk
-			// if lstat works but stat fails, it's likely a broken link we might handle later
-			if _, lerr := os.Lstat(currentPath); lerr == nil {
-				// we can lstat it, maybe it's a broken link we can ignore?
-				// for now, let's return the original error to maintain behavior.
-				// consider adding verbose logging here if needed.
+	if opts.WaitStable > 0 {
+		paths := []string{opts.Path}
+		if len(opts.Sources) > 0 {
+			paths = paths[:0]
+			for _, src := range opts.Sources {
+				paths = append(paths, src.Path)
 			}
-			return walkErr
 		}
+		display := NewDisplay(NewFormatter(opts.Verbose))
+		display.SetQuiet(opts.Quiet)
+		display.ShowNote(fmt.Sprintf("waiting until the content hasn't changed for %s", opts.WaitStable))
+		if err := waitStable(ctx, paths, opts.WaitStable); err != nil {
+			return nil, err
+		}
+	}
 
-		lstatInfo, err := os.Lstat(currentPath)
+	files := make([]fileEntry, 0, 1)
+	var totalSize int64
+	var baseDir string
+	originalPaths := make(map[string]string) // map resolved path -> original path for metainfo
+	var walkedPaths []string                 // walked path for each entry in files, used for duplicate and case collision reporting
+	var walkedDirs []string                  // directories descended into, used to report empty ones
+	dirsWithFiles := make(map[string]bool)
+
+	var matchBasePath string
+	var torrentPaths map[string]string // disk path -> path in the torrent, set when creating from sources
+	if len(opts.Sources) > 0 {
+		var err error
+		files, torrentPaths, err = sourceFiles(opts.Sources, filter)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: could not lstat %q: %v\n", currentPath, err)
-			return nil
+			return nil, err
+		}
+		for _, f := range files {
+			originalPaths[f.path] = f.path
+			walkedPaths = append(walkedPaths, torrentPaths[f.path])
+			totalSize += f.length
+		}
+	} else {
+		inputInfo, err := os.Stat(path)
+		if err != nil {
+			return nil, notFound(fmt.Errorf("error checking path: %w", err))
 		}
 
-		resolvedPath := currentPath
-		resolvedInfo := lstatInfo
+		// Clean the base path for computing relative paths
+		cleanBasePath := filepath.Clean(path)
+		matchBasePath = cleanBasePath
+		if !inputInfo.IsDir() {
+			matchBasePath = filepath.Dir(cleanBasePath)
+		}
 
-		// check if it's a symlink
-		if lstatInfo.Mode()&os.ModeSymlink != 0 {
-			linkTarget, err := os.Readlink(currentPath)
+		// NTFS junctions are skipped unless DereferenceJunctions is set, and are then
+		// walked under the junction's own path. realPrefix maps the junction being walked back to its
+		// target so a junction inside the directory it points to is caught as a loop.
+		var visit filepath.WalkFunc
+		var mappedPrefix, realPrefix string
+		followedJunctions := make(map[string]bool) // lowercased absolute targets, starting with the content root
+		if absBasePath, err := filepath.Abs(cleanBasePath); err == nil && inputInfo.IsDir() {
+			followedJunctions[strings.ToLower(absBasePath)] = true
+		}
+		walkJunction := func(junctionPath, relPath string) error {
+			// a junction given as the content path itself is always followed
+			if relPath != "" {
+				shouldSkip, err := filter.ignores(relPath, true)
+				if err != nil {
+					return fmt.Errorf("error processing directory patterns for %q: %w", junctionPath, err)
+				}
+				if shouldSkip {
+					return nil
+				}
+				if !opts.DereferenceJunctions {
+					showWarning(fmt.Sprintf("skipping directory junction %q, use --dereference-junctions to follow it", junctionPath))
+					return nil
+				}
+			}
+			target, err := junctionTarget(junctionPath)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not readlink %q: %v\n", currentPath, err)
+				showWarning(fmt.Sprintf("could not resolve junction %q: %v", junctionPath, err))
 				return nil
 			}
-			// if link is relative, resolve it based on the link's directory
-			if !filepath.IsAbs(linkTarget) {
-				linkTarget = filepath.Join(filepath.Dir(currentPath), linkTarget)
-			}
-			resolvedPath = filepath.Clean(linkTarget)
-
-			// stat target
-			statInfo, err := os.Stat(resolvedPath)
+			realPath, err := filepath.Abs(junctionPath)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not stat symlink target %q for link %q: %v\n", resolvedPath, currentPath, err)
-				return nil // skip broken link or inaccessible target
+				return err
+			}
+			if mappedPrefix != "" {
+				rel, err := filepath.Rel(mappedPrefix, junctionPath)
+				if err != nil {
+					return err
+				}
+				realPath = filepath.Join(realPrefix, rel)
+			}
+			targetKey := strings.ToLower(target)
+			if followedJunctions[targetKey] || junctionWithin(realPath, target) {
+				showWarning(fmt.Sprintf("skipping directory junction %q, following it to %q would loop", junctionPath, target))
+				return nil
+			}
+			followedJunctions[targetKey] = true
+			if relPath == "" {
+				baseDir = junctionPath
+			} else {
+				walkedDirs = append(walkedDirs, junctionPath)
 			}
-			resolvedInfo = statInfo
-		}
 
-		// Compute relative path from torrent root for glob matching
-		relPath, err := filepath.Rel(matchBasePath, currentPath)
-		if err != nil {
-			return fmt.Errorf("error calculating relative path for %q: %w", currentPath, err)
-		}
-		// Handle the root directory case
-		if relPath == "." {
-			relPath = ""
+			prevMapped, prevReal := mappedPrefix, realPrefix
+			mappedPrefix, realPrefix = junctionPath, target
+			defer func() { mappedPrefix, realPrefix = prevMapped, prevReal }()
+			return filepath.Walk(target, func(targetPath string, walkInfo os.FileInfo, walkErr error) error {
+				if targetPath == target {
+					return walkErr // the junction stands in for the target directory itself
+				}
+				rel, err := filepath.Rel(target, targetPath)
+				if err != nil {
+					return err
+				}
+				return visit(filepath.Join(junctionPath, rel), walkInfo, walkErr)
+			})
 		}
 
-		if resolvedInfo.IsDir() {
-			// Check hardcoded directory ignores (safety net)
-			if shouldIgnoreDir(currentPath) || shouldIgnoreDir(resolvedPath) {
-				return filepath.SkipDir
+		visit = func(currentPath string, walkInfo os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				// check if the error is due to a broken symlink during walk
+				// if lstat works but stat fails, it's likely a broken link we might handle later
+				if _, lerr := os.Lstat(currentPath); lerr == nil {
+					// we can lstat it, maybe it's a broken link we can ignore?
+					// for now, let's return the original error to maintain behavior.
+					// consider adding verbose logging here if needed.
+				}
+				return walkErr
 			}
 
-			// Check user-defined exclude/include patterns for directories
-			if relPath != "" {
-				shouldSkip, err := shouldIgnoreEntry(relPath, true, opts.ExcludePatterns, opts.IncludePatterns)
+			lstatInfo, err := os.Lstat(currentPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not lstat %q: %v\n", currentPath, err)
+				return nil
+			}
+
+			resolvedPath := currentPath
+			resolvedInfo := lstatInfo
+
+			// check if it's a symlink
+			if lstatInfo.Mode()&os.ModeSymlink != 0 {
+				linkTarget, err := os.Readlink(currentPath)
 				if err != nil {
-					return fmt.Errorf("error processing directory patterns for %q: %w", currentPath, err)
+					fmt.Fprintf(os.Stderr, "Warning: could not readlink %q: %v\n", currentPath, err)
+					return nil
 				}
-				if shouldSkip {
+				// if link is relative, resolve it based on the link's directory
+				if !filepath.IsAbs(linkTarget) {
+					linkTarget = filepath.Join(filepath.Dir(currentPath), linkTarget)
+				}
+				resolvedPath = filepath.Clean(linkTarget)
+
+				// stat target
+				statInfo, err := os.Stat(resolvedPath)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not stat symlink target %q for link %q: %v\n", resolvedPath, currentPath, err)
+					return nil // skip broken link or inaccessible target
+				}
+				resolvedInfo = statInfo
+			}
+
+			// Compute relative path from torrent root for glob matching
+			relPath, err := filepath.Rel(matchBasePath, currentPath)
+			if err != nil {
+				return fmt.Errorf("error calculating relative path for %q: %w", currentPath, err)
+			}
+			// Handle the root directory case
+			if relPath == "." {
+				relPath = ""
+			}
+
+			// NTFS junctions are neither symlinks nor directories to Lstat
+			if isJunction(lstatInfo) {
+				return walkJunction(currentPath, relPath)
+			}
+
+			if resolvedInfo.IsDir() {
+				// Check hardcoded directory ignores (safety net)
+				if shouldIgnoreDir(currentPath) || shouldIgnoreDir(resolvedPath) {
 					return filepath.SkipDir
 				}
+
+				// Check user-defined exclude/include patterns for directories
+				if relPath != "" {
+					shouldSkip, err := filter.ignores(relPath, true)
+					if err != nil {
+						return fmt.Errorf("error processing directory patterns for %q: %w", currentPath, err)
+					}
+					if shouldSkip {
+						return filepath.SkipDir
+					}
+				}
+
+				if baseDir == "" && currentPath == path { // only set baseDir for the initial path if it's a dir
+					baseDir = currentPath
+				}
+				if relPath != "" && lstatInfo.IsDir() {
+					walkedDirs = append(walkedDirs, currentPath)
+				}
+				return nil
 			}
 
-			if baseDir == "" && currentPath == path { // only set baseDir for the initial path if it's a dir
-				baseDir = currentPath
+			// any file, even an excluded one, means its directories were not empty on disk
+			for dir := filepath.Dir(currentPath); !dirsWithFiles[dir]; dir = filepath.Dir(dir) {
+				dirsWithFiles[dir] = true
+				if dir == cleanBasePath || dir == filepath.Dir(dir) {
+					break
+				}
 			}
+
+			// it's a file (or a link pointing to one)
+			shouldIgnore, err := filter.ignores(relPath, false)
+			if err != nil {
+				return fmt.Errorf("error processing file patterns for %q: %w", currentPath, err)
+			}
+			if shouldIgnore {
+				return nil
+			}
+
+			// add the file using the resolved path for hashing, but store the original path for metainfo
+			files = append(files, fileEntry{
+				path:    resolvedPath, // use the actual content path for hashing
+				length:  resolvedInfo.Size(),
+				offset:  totalSize,
+				modTime: resolvedInfo.ModTime(),
+			})
+			originalPaths[resolvedPath] = currentPath
+			walkedPaths = append(walkedPaths, filepath.ToSlash(relPath))
+			totalSize += resolvedInfo.Size()
 			return nil
 		}
-
-		// it's a file (or a link pointing to one)
-		shouldIgnore, err := shouldIgnoreEntry(relPath, false, opts.ExcludePatterns, opts.IncludePatterns)
+		err = filepath.Walk(path, visit)
 		if err != nil {
-			return fmt.Errorf("error processing file patterns for %q: %w", currentPath, err)
+			return nil, fmt.Errorf("error walking path: %w", err)
 		}
-		if shouldIgnore {
-			return nil
+
+		if opts.PathDepth < 0 {
+			if baseDir == "" {
+				return nil, fmt.Errorf("cannot use a negative path depth with a single file")
+			}
+			torrentPaths = make(map[string]string, len(files))
+			for _, f := range files {
+				relPath, _ := filepath.Rel(baseDir, originalPaths[f.path])
+				torrentPaths[f.path] = filepath.ToSlash(relPath)
+			}
+			if err := stripTorrentPaths(torrentPaths, -opts.PathDepth); err != nil {
+				return nil, err
+			}
+			if err := checkTorrentPaths(torrentPaths); err != nil {
+				return nil, err
+			}
+			sortByTorrentPath(files, torrentPaths)
+		}
+	}
+
+	if emptyDirs := topLevelEmptyDirs(walkedDirs, dirsWithFiles, matchBasePath); len(emptyDirs) > 0 {
+		noun := "directories"
+		if len(emptyDirs) == 1 {
+			noun = "directory"
+		}
+		if opts.FailOnEmptyDirs {
+			return nil, fmt.Errorf("found %d empty %s: %s", len(emptyDirs), noun, strings.Join(emptyDirs, ", "))
 		}
+		if opts.Verbose {
+			display := NewDisplay(NewFormatter(opts.Verbose))
+			display.SetQuiet(opts.Quiet)
+			display.ShowNote(fmt.Sprintf("skipping %d empty %s, torrents cannot contain them: %s",
+				len(emptyDirs), noun, strings.Join(emptyDirs, ", ")))
+		}
+	}
 
-		// add the file using the resolved path for hashing, but store the original path for metainfo
-		files = append(files, fileEntry{
-			path:   resolvedPath, // use the actual content path for hashing
-			length: resolvedInfo.Size(),
-			offset: totalSize,
-		})
-		originalPaths[resolvedPath] = currentPath
-		totalSize += resolvedInfo.Size()
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error walking path: %w", err)
+	if collisions := findCaseCollisions(walkedPaths); len(collisions) > 0 {
+		msg := fmt.Sprintf("found %d set(s) of paths that differ only by case, they collide on case-insensitive filesystems: %s",
+			len(collisions), formatCaseCollisions(collisions))
+		if opts.Strict {
+			return nil, fmt.Errorf("%s; rename them or disable strict mode", msg)
+		}
+		showWarning(msg)
 	}
 
-	// sort files to ensure consistent order
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].path < files[j].path
-	})
+	if opts.WarnDuplicates {
+		display := NewDisplay(NewFormatter(opts.Verbose))
+		groups, err := findDuplicateFiles(files, walkedPaths)
+		if err != nil {
+			display.ShowWarning(fmt.Sprintf("could not check for duplicate files: %v", err))
+		} else {
+			display.ShowDuplicateWarnings(groups, opts.Verbose)
+		}
+	}
+
+	// sort files to ensure consistent order, sources come sorted by torrent path
+	if torrentPaths == nil {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].path < files[j].path
+		})
+	}
 
 	// recalculate offsets based on the sorted file order
 	// context: https://github.com/autobrr/mkbrr/issues/64
@@ -337,29 +832,51 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 		return nil, fmt.Errorf("input path %q contains no files or only empty files, cannot create torrent", path)
 	}
 
+	// hashing totals across attempts, as the piece length may be raised and the content rehashed
+	var hashStats HashStats
+
+	storage := resolveStorage(opts.Storage, path)
+
 	// Function to create torrent with given piece length
 	createWithPieceLength := func(pieceLength uint) (*Torrent, error) {
 		pieceLenInt := int64(1) << pieceLength
-		numPieces := (totalSize + pieceLenInt - 1) / pieceLenInt
 
-		var display Displayer
-		if opts.ProgressCallback != nil {
-			// Use callback displayer when progress callback is provided
-			display = &callbackDisplayer{callback: opts.ProgressCallback}
-		} else {
-			// Use default display when no callback is provided
-			defaultDisplay := NewDisplay(NewFormatter(opts.Verbose || opts.InfoOnly))
-			defaultDisplay.SetQuiet(opts.Quiet || opts.InfoOnly)
-			display = defaultDisplay
+		// padding depends on the piece length, so it is laid out for each attempt
+		hashFiles := files
+		hashSize := totalSize
+		if opts.Padded {
+			hashFiles = padFiles(files, pieceLenInt)
+			last := hashFiles[len(hashFiles)-1]
+			hashSize = last.offset + last.length
 		}
+		numPieces := (hashSize + pieceLenInt - 1) / pieceLenInt
+
+		display := hashDisplay(opts)
 
 		var pieceHashes [][]byte
-		hasher := NewPieceHasher(files, pieceLenInt, int(numPieces), display, opts.FailOnSeasonPackWarning)
+		hasher := NewPieceHasher(hashFiles, pieceLenInt, int(numPieces), display, opts.FailOnSeasonPackWarning)
+		hasher.seasonPackOptions = SeasonPackOptions{ExpectedEpisodes: opts.ExpectedEpisodes}
+		hasher.ioMode = opts.IOMode
+		hasher.maxMemory = opts.MaxMemory
+		hasher.readRetries = opts.ReadRetries
+		hasher.storage = storage
+		hasher.ctx = ctx
+		if opts.DedupHardlinks {
+			hasher.pieceSources = hardlinkPieceSources(hashFiles, pieceLenInt, int(numPieces), hasher.lastPieceLength)
+		}
 		// Pass the specified or default worker count from opts
 		if err := hasher.hashPieces(opts.Workers); err != nil {
 			return nil, err
 		}
+		if opts.VerifyStable {
+			if changed := changedFiles(files, originalPaths); len(changed) > 0 {
+				return nil, withKind(ErrContentChanged, fmt.Errorf("%d file(s) changed while hashing, the torrent would not match them: %s",
+					len(changed), strings.Join(changed, ", ")))
+			}
+		}
 		pieceHashes = hasher.pieces
+		hashStats.BytesHashed += hasher.bytesProcessed
+		hashStats.Elapsed += time.Since(hasher.startTime)
 
 		info := &metainfo.Info{
 			Name:        name,
@@ -367,8 +884,12 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 			Private:     &opts.IsPrivate,
 		}
 
-		if opts.Source != "" {
-			info.Source = opts.Source
+		var trackerURL string
+		if len(opts.TrackerURLs) > 0 {
+			trackerURL = opts.TrackerURLs[0]
+		}
+		if source := preset.ExpandSource(opts.Source, opts.SourceMap, trackerURL); source != "" {
+			info.Source = source
 		}
 
 		info.Pieces = make([]byte, len(pieceHashes)*20)
@@ -376,11 +897,11 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 			copy(info.Pieces[i*20:], piece)
 		}
 
-		if len(files) == 1 {
+		if len(files) == 1 && torrentPaths == nil {
 			// check if the input path is a directory
 			pathInfo, err := os.Stat(path)
 			if err != nil {
-				return nil, fmt.Errorf("error checking path: %w", err)
+				return nil, notFound(fmt.Errorf("error checking path: %w", err))
 			}
 
 			if pathInfo.IsDir() {
@@ -392,7 +913,7 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 					originalFilepath = files[0].path // Fallback if mapping missing
 				}
 				relPath, _ := filepath.Rel(baseDir, originalFilepath)
-				pathComponents := strings.Split(filepath.ToSlash(relPath), "/") // Ensure forward slashes
+				pathComponents := torrentPathComponents(relPath, filepath.Separator)
 				info.Files[0] = metainfo.FileInfo{
 					Path:   pathComponents,
 					Length: files[0].length, // Length comes from resolved file
@@ -402,15 +923,26 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 				info.Length = files[0].length
 			}
 		} else {
-			info.Files = make([]metainfo.FileInfo, len(files))
-			for i, f := range files {
+			info.Files = make([]metainfo.FileInfo, len(hashFiles))
+			for i, f := range hashFiles {
+				if f.padding {
+					info.Files[i] = paddingFileInfo(f.length)
+					continue
+				}
+				if torrentPaths != nil {
+					info.Files[i] = metainfo.FileInfo{
+						Path:   strings.Split(torrentPaths[f.path], "/"),
+						Length: f.length,
+					}
+					continue
+				}
 				// Use the original path for calculating relative path in metainfo
 				originalFilepath := originalPaths[f.path]
 				if originalFilepath == "" {
 					originalFilepath = f.path // Fallback if mapping missing
 				}
 				relPath, _ := filepath.Rel(baseDir, originalFilepath)
-				pathComponents := strings.Split(filepath.ToSlash(relPath), "/") // Ensure forward slashes
+				pathComponents := torrentPathComponents(relPath, filepath.Separator)
 				info.Files[i] = metainfo.FileInfo{
 					Path:   pathComponents,
 					Length: f.length, // Length comes from resolved file
@@ -418,110 +950,132 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 			}
 		}
 
+		if err := normalizeInfoPaths(info, opts.NormalizeUnicode); err != nil {
+			return nil, err
+		}
+		if opts.LegacyUTF8 {
+			addLegacyUTF8(info)
+		}
+
 		infoBytes, err := bencode.Marshal(info)
 		if err != nil {
 			return nil, fmt.Errorf("error encoding info: %w", err)
 		}
 
-		// add random entropy field for cross-seeding if enabled
-		if opts.Entropy {
+		// add custom info fields, and a random entropy field for cross-seeding if enabled
+		if opts.Entropy || len(infoMeta) > 0 {
 			infoMap := make(map[string]interface{})
-			if err := bencode.Unmarshal(infoBytes, &infoMap); err == nil {
+			if err := bencode.Unmarshal(infoBytes, &infoMap); err != nil {
+				return nil, fmt.Errorf("error encoding info: %w", err)
+			}
+			for key, value := range infoMeta {
+				infoMap[key] = value
+			}
+			if opts.Entropy {
 				if entropy, err := generateRandomString(); err == nil {
 					infoMap["entropy"] = entropy
-					if infoBytes, err = bencode.Marshal(infoMap); err == nil {
-						mi.InfoBytes = infoBytes
-					}
 				}
 			}
-		} else {
-			mi.InfoBytes = infoBytes
+			if infoBytes, err = bencode.Marshal(infoMap); err != nil {
+				return nil, fmt.Errorf("error encoding info: %w", err)
+			}
 		}
+		mi.InfoBytes = infoBytes
 
 		if len(opts.WebSeeds) > 0 {
 			mi.UrlList = opts.WebSeeds
 		}
 
-		return &Torrent{mi}, nil
+		return &Torrent{MetaInfo: mi, hashStats: hashStats, meta: meta}, nil
 	}
 
 	// validate mutual exclusion at the API level (CLI validates this too, but exported callers may not)
 	if opts.PieceLengthExp != nil && opts.TargetPieceCount != nil {
 		return nil, fmt.Errorf("cannot use both piece length and target piece count; use one or the other")
 	}
+	if opts.PieceLengthAutoMax && (opts.PieceLengthExp != nil || opts.TargetPieceCount != nil) {
+		return nil, fmt.Errorf("cannot use auto-max piece length with a piece length or target piece count")
+	}
+
+	// min-piece-length only applies when the piece length is calculated
+	if opts.PieceLengthExp == nil && opts.MinPieceLength != nil {
+		if *opts.MinPieceLength < 16 || *opts.MinPieceLength > 27 {
+			return nil, withKind(ErrPieceLengthOutOfRange, fmt.Errorf("min piece length exponent must be between 16 (64 KiB) and 27 (128 MiB), got: %d", *opts.MinPieceLength))
+		}
+		if opts.MaxPieceLength != nil && *opts.MinPieceLength > *opts.MaxPieceLength {
+			return nil, withKind(ErrPieceLengthOutOfRange, fmt.Errorf("min piece length exponent %d cannot be larger than max piece length exponent %d",
+				*opts.MinPieceLength, *opts.MaxPieceLength))
+		}
+		if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
+			if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(opts.TrackerURLs[0]); ok && *opts.MinPieceLength > trackerMaxExp {
+				return nil, withKind(ErrPieceLengthOutOfRange, fmt.Errorf("min piece length exponent %d exceeds the maximum of %d (%d MiB) for %s",
+					*opts.MinPieceLength, trackerMaxExp, 1<<(trackerMaxExp-20), trackerHost(opts.TrackerURLs[0])))
+			}
+		}
+	}
 
 	var pieceLength uint
-	if opts.PieceLengthExp == nil && opts.TargetPieceCount != nil {
+	if opts.PieceLengthAutoMax {
+		trackerURL := firstTracker(opts.TrackerURLs)
+		maxSize, ok := trackers.GetTrackerMaxTorrentSize(trackerURL)
+		if !ok {
+			return nil, fmt.Errorf("auto-max piece length requires a tracker with a .torrent size limit")
+		}
+		if err := ValidatePieceLength(nil, opts.MaxPieceLength, trackerURL); err != nil {
+			return nil, err
+		}
+		minExp := uint(16)
+		if opts.MinPieceLength != nil {
+			minExp = max(minExp, *opts.MinPieceLength)
+		}
+		// the estimate leaves out the file list, so the size check below may still
+		// raise the piece length, but never needs a smaller one
+		pieceLength = smallestFittingExp(uint64(totalSize), maxSize, minExp, sizeLimitCeilingExp(trackerURL, opts.MaxPieceLength))
+		if opts.Verbose {
+			display := NewDisplay(NewFormatter(opts.Verbose))
+			display.SetQuiet(opts.Quiet)
+			display.ShowMessage(fmt.Sprintf("auto-max: starting from %s pieces for the %.1f KiB .torrent limit",
+				formatPieceSize(pieceLength), float64(maxSize)/(1<<10)))
+		}
+	} else if opts.PieceLengthExp == nil && opts.TargetPieceCount != nil {
 		if *opts.TargetPieceCount == 0 {
 			return nil, fmt.Errorf("target piece count must be greater than zero")
 		}
 		// validate max-piece-length the same way the automatic path does
-		if opts.MaxPieceLength != nil {
-			maxExp := uint(27)
-			if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
-				if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(opts.TrackerURLs[0]); ok {
-					maxExp = trackerMaxExp
-				}
-			}
-			if *opts.MaxPieceLength < 14 || *opts.MaxPieceLength > maxExp {
-				return nil, fmt.Errorf("max piece length exponent must be between 14 (16 KiB) and %d (%d MiB), got: %d",
-					maxExp, 1<<(maxExp-20), *opts.MaxPieceLength)
-			}
+		if err := ValidatePieceLength(nil, opts.MaxPieceLength, firstTracker(opts.TrackerURLs)); err != nil {
+			return nil, err
 		}
 		// target piece count mode: derive piece length from target count
-		pieceLength = calculatePieceLengthFromTarget(totalSize, *opts.TargetPieceCount, opts.MaxPieceLength, opts.TrackerURLs, opts.Verbose)
+		pieceLength = calculatePieceLengthFromTarget(totalSize, *opts.TargetPieceCount, opts.MinPieceLength, opts.MaxPieceLength, opts.TrackerURLs, opts.Verbose)
 	} else if opts.PieceLengthExp == nil {
-		if opts.MaxPieceLength != nil {
-			// Get tracker's max piece length if available
-			maxExp := uint(27) // absolute max 128 MiB
-			if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
-				if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(opts.TrackerURLs[0]); ok {
-					maxExp = trackerMaxExp
-				}
-			}
-
-			if *opts.MaxPieceLength < 14 || *opts.MaxPieceLength > maxExp {
-				return nil, fmt.Errorf("max piece length exponent must be between 14 (16 KiB) and %d (%d MiB), got: %d",
-					maxExp, 1<<(maxExp-20), *opts.MaxPieceLength)
-			}
+		if err := ValidatePieceLength(nil, opts.MaxPieceLength, firstTracker(opts.TrackerURLs)); err != nil {
+			return nil, err
 		}
-		pieceLength = calculatePieceLength(totalSize, opts.MaxPieceLength, opts.TrackerURLs, opts.Verbose)
+		pieceLength = calculatePieceLength(totalSize, len(files), opts.MinPieceLength, opts.MaxPieceLength, opts.TrackerURLs, opts.Verbose)
 	} else {
 		pieceLength = *opts.PieceLengthExp
-
-		// Get tracker's max piece length if available
-		maxExp := uint(27) // absolute max 128 MiB
-		if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
-			if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(opts.TrackerURLs[0]); ok {
-				maxExp = trackerMaxExp
-			}
-		}
-
-		if pieceLength < 16 || pieceLength > maxExp {
-			if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
-				return nil, fmt.Errorf("piece length exponent must be between 16 (64 KiB) and %d (%d MiB) for %s, got: %d",
-					maxExp, 1<<(maxExp-20), opts.TrackerURLs[0], pieceLength)
-			}
-			return nil, fmt.Errorf("piece length exponent must be between 16 (64 KiB) and %d (%d MiB), got: %d",
-				maxExp, 1<<(maxExp-20), pieceLength)
+		if err := ValidatePieceLength(opts.PieceLengthExp, nil, firstTracker(opts.TrackerURLs)); err != nil {
+			return nil, err
 		}
+		maxExp := maxPieceLengthExp(firstTracker(opts.TrackerURLs))
 
 		// If we have a tracker with specific ranges, show that we're using them and check if piece length matches
 		if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
 			if exp, ok := trackers.GetTrackerPieceSizeExp(opts.TrackerURLs[0], uint64(totalSize)); ok {
 				if exp < 16 || exp > maxExp {
-					return nil, fmt.Errorf("piece length exponent %d for %s is outside allowed range 16-%d", exp, opts.TrackerURLs[0], maxExp)
+					return nil, withKind(ErrPieceLengthOutOfRange, fmt.Errorf("piece length exponent %d for %s is outside allowed range 16-%d", exp, opts.TrackerURLs[0], maxExp))
 				}
+				display := NewDisplay(NewFormatter(opts.Verbose || opts.InfoOnly))
+				display.SetQuiet(opts.Quiet || opts.InfoOnly)
 				if opts.Verbose || opts.InfoOnly {
-					display := NewDisplay(NewFormatter(opts.Verbose || opts.InfoOnly))
-					display.SetQuiet(opts.Quiet || opts.InfoOnly)
 					display.ShowMessage(fmt.Sprintf("using tracker-specific range for content size: %d MiB (recommended: %s pieces)",
 						totalSize>>20, formatPieceSize(exp)))
 					fmt.Fprintln(display.output)
-					if pieceLength != exp {
-						display.ShowWarning(fmt.Sprintf("custom piece length %s differs from recommendation",
-							formatPieceSize(pieceLength)))
-					}
+				}
+				// warnings go to stderr, so they are shown in quiet mode too
+				if pieceLength != exp && opts.ProgressCallback == nil {
+					display.ShowWarning(fmt.Sprintf("custom piece length %s differs from recommendation (%s)",
+						formatPieceSize(pieceLength), formatPieceSize(exp)))
 				}
 			}
 		}
@@ -542,24 +1096,7 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 				return nil, fmt.Errorf("error marshaling torrent data: %w", err)
 			}
 
-			// Determine the effective max piece length ceiling
-			maxPieceLengthCeiling := uint(24) // default ceiling
-			hasTrackerCap := false
-			if len(opts.TrackerURLs) > 0 && opts.TrackerURLs[0] != "" {
-				if trackerMaxExp, ok := trackers.GetTrackerMaxPieceLength(opts.TrackerURLs[0]); ok {
-					maxPieceLengthCeiling = trackerMaxExp
-					hasTrackerCap = true
-				}
-			}
-			if opts.MaxPieceLength != nil {
-				if hasTrackerCap {
-					// tracker cap is a hard ceiling; user can lower but not exceed it
-					maxPieceLengthCeiling = min(*opts.MaxPieceLength, maxPieceLengthCeiling)
-				} else {
-					// no tracker cap; user can raise above default 24
-					maxPieceLengthCeiling = min(*opts.MaxPieceLength, 27)
-				}
-			}
+			maxPieceLengthCeiling := sizeLimitCeilingExp(opts.TrackerURLs[0], opts.MaxPieceLength)
 
 			// If it exceeds limit, try increasing piece length until it fits or we hit max
 			for uint64(len(torrentData)) > maxSize && pieceLength < maxPieceLengthCeiling {
@@ -583,8 +1120,8 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 			}
 
 			if uint64(len(torrentData)) > maxSize {
-				return nil, fmt.Errorf("unable to create torrent under size limit (%.1f KiB) even with maximum piece length",
-					float64(maxSize)/(1<<10))
+				return nil, withKind(ErrTorrentTooLarge, fmt.Errorf("unable to create torrent under size limit (%.1f KiB) even with maximum piece length",
+					float64(maxSize)/(1<<10)))
 			}
 
 			return t, nil
@@ -595,33 +1132,145 @@ func CreateTorrent(opts CreateOptions) (*Torrent, error) {
 	return createWithPieceLength(pieceLength)
 }
 
-// Create creates a new torrent file with the given options.
-// Returns TorrentInfo containing summary information about the created torrent.
-// The torrent file is automatically saved to disk based on the output options.
-// This is the main high-level function for torrent creation.
-func Create(opts CreateOptions) (*TorrentInfo, error) {
-	// validate input path
-	if _, err := os.Stat(opts.Path); err != nil {
-		return nil, fmt.Errorf("invalid path %q: %w", opts.Path, err)
+// checkWritten re-reads the torrent written to path and makes sure it parses and has
+// the info hash computed while creating it, so an encoding bug never leaves a broken
+// file behind. The file is removed if the check fails.
+func checkWritten(path string, want metainfo.Hash) error {
+	err := func() error {
+		t, err := LoadFromFile(path)
+		if err != nil {
+			return withKind(ErrCorruptTorrent, err)
+		}
+		if _, err := t.UnmarshalInfo(); err != nil {
+			return withKind(ErrCorruptTorrent, fmt.Errorf("could not parse info dictionary: %w", err))
+		}
+		if got := t.HashInfoBytes(); got != want {
+			return withKind(ErrInfoHashMismatch, fmt.Errorf("info hash is %s, expected %s", got, want))
+		}
+		return nil
+	}()
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("written torrent %s failed validation: %w", path, err)
+	}
+	return nil
+}
+
+// createOutputFile creates the file at path for writing. Unless force is set an
+// existing file is left untouched and ErrOutputExists is returned, naming the
+// file's modification time so the user knows what would be overwritten.
+func createOutputFile(path string, force bool) (*os.File, error) {
+	if force {
+		return os.Create(path)
 	}
 
-	baseName := filepath.Base(filepath.Clean(opts.Path))
-	if opts.Name == "" {
-		opts.Name = baseName
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, outputExistsError(path)
 	}
+	return f, err
+}
 
-	// set name if not provided
+// checkOutputFree returns ErrOutputExists if something is already at path, so an
+// existing output is reported before hashing instead of after it
+func checkOutputFree(path string) error {
+	if _, err := os.Lstat(path); err == nil {
+		return outputExistsError(path)
+	}
+	return nil
+}
+
+func outputExistsError(path string) error {
+	if fi, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%w: %s (modified %s)", ErrOutputExists, path, fi.ModTime().Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Errorf("%w: %s", ErrOutputExists, path)
+}
+
+// outputPath returns the path CreateContext writes the torrent to. The info hash and
+// size are only used by output patterns that reference them.
+func outputPath(opts CreateOptions, infoHash string, size int64) string {
 	fileName := opts.Name
-	if len(opts.TrackerURLs) == 1 && !opts.SkipPrefix {
+	if opts.OutputPattern != "" {
+		var trackerURL string
+		if len(opts.TrackerURLs) > 0 {
+			trackerURL = opts.TrackerURLs[0]
+		}
+		fileName = preset.ExpandOutputPattern(opts.OutputPattern, preset.OutputTemplateData{
+			Name:     opts.Name,
+			Tracker:  trackerURL,
+			InfoHash: infoHash,
+			Size:     size,
+		})
+	} else if len(opts.TrackerURLs) == 1 && !opts.SkipPrefix {
 		fileName = preset.GetDomainPrefix(opts.TrackerURLs[0]) + "_" + fileName
 	}
+	fileName = opts.OutputPrefix + fileName + opts.OutputSuffix
 
 	if opts.OutputDir != "" {
-		opts.OutputPath = filepath.Join(opts.OutputDir, fileName+".torrent")
+		return filepath.Join(opts.OutputDir, fileName+".torrent")
 	} else if opts.OutputPath == "" {
-		opts.OutputPath = fileName + ".torrent"
+		return fileName + ".torrent"
 	} else if !strings.HasSuffix(opts.OutputPath, ".torrent") {
-		opts.OutputPath = opts.OutputPath + ".torrent"
+		return opts.OutputPath + ".torrent"
+	}
+	return opts.OutputPath
+}
+
+// outputPathKnown reports whether the output path can be resolved before hashing,
+// i.e. the output pattern doesn't reference the info hash or size
+func outputPathKnown(pattern string) bool {
+	return !strings.Contains(pattern, "{infohash") && !strings.Contains(pattern, "{size}")
+}
+
+// writeOutputFile writes t to f and closes it. The file is removed again if the write
+// fails or ctx is cancelled meanwhile, so an interrupted run leaves no partial output.
+func writeOutputFile(ctx context.Context, f *os.File, t *Torrent) error {
+	err := t.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctxErr(ctx)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Create creates a new torrent file with the given options.
+// Returns TorrentInfo containing summary information about the created torrent.
+// The torrent file is automatically saved to disk based on the output options.
+// This is the main high-level function for torrent creation.
+func Create(opts CreateOptions) (*TorrentInfo, error) {
+	return CreateContext(context.Background(), opts)
+}
+
+// CreateContext is Create with a context, see CreateTorrentContext. A cancelled
+// torrent is never written, so no partial output file is left behind.
+func CreateContext(ctx context.Context, opts CreateOptions) (*TorrentInfo, error) {
+	if strings.ContainsAny(opts.OutputPrefix+opts.OutputSuffix, `/\`) {
+		return nil, fmt.Errorf("output prefix and suffix must not contain path separators, use an output directory instead")
+	}
+
+	// validate input path, sources are checked while they are walked
+	if len(opts.Sources) == 0 {
+		if _, err := os.Stat(opts.Path); err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", opts.Path, err)
+		}
+
+		baseName := filepath.Base(filepath.Clean(opts.Path))
+		if opts.PathDepth > 0 {
+			root, _, err := depthRoot(opts.Path, opts.PathDepth)
+			if err != nil {
+				return nil, err
+			}
+			baseName = filepath.Base(root)
+		}
+		if opts.Name == "" {
+			opts.Name = baseName
+		}
 	}
 
 	if opts.OutputDir != "" {
@@ -630,33 +1279,73 @@ func Create(opts CreateOptions) (*TorrentInfo, error) {
 		}
 	}
 
+	// fail before hashing when the output already exists, the exclusive create
+	// below still guards against it appearing meanwhile
+	if outputPathKnown(opts.OutputPattern) {
+		path := outputPath(opts, "", 0)
+		if !opts.Force {
+			if err := checkOutputFree(path); err != nil {
+				return nil, fmt.Errorf("error creating output file: %w", err)
+			}
+		}
+		if opts.SymlinkTo != "" {
+			if _, _, err := checkLinkTarget(path, opts.SymlinkTo, opts.Force); err != nil {
+				return nil, fmt.Errorf("error linking torrent file: %w", err)
+			}
+		}
+	}
+
 	// create torrent
-	t, err := CreateTorrent(opts)
+	t, err := CreateTorrentContext(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// expanded after hashing so {infohash} placeholders can be resolved
+	opts.OutputPath = outputPath(opts, t.MetaInfo.HashInfoBytes().String(), t.GetInfo().TotalLength())
+
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+
 	// create output file
-	f, err := os.Create(opts.OutputPath)
+	f, err := createOutputFile(opts.OutputPath, opts.Force)
 	if err != nil {
 		return nil, fmt.Errorf("error creating output file: %w", err)
 	}
-	defer f.Close()
-
-	// write torrent file
-	if err := t.Write(f); err != nil {
+	if err := writeOutputFile(ctx, f, t); err != nil {
 		return nil, fmt.Errorf("error writing torrent file: %w", err)
 	}
+	if err := checkWritten(opts.OutputPath, t.HashInfoBytes()); err != nil {
+		return nil, err
+	}
+
+	var linkPath string
+	var linkCopied bool
+	if opts.SymlinkTo != "" {
+		linkPath, linkCopied, err = linkOutput(opts.OutputPath, opts.SymlinkTo, opts.Force)
+		if err != nil {
+			return nil, fmt.Errorf("error linking torrent file: %w", err)
+		}
+	}
 
 	// get info for display
 	info := t.GetInfo()
 
 	// create torrent info for return
 	torrentInfo := &TorrentInfo{
-		Path:     opts.OutputPath,
-		Size:     info.Length,
-		InfoHash: t.MetaInfo.HashInfoBytes().String(),
-		Files:    len(info.Files),
+		MetaInfo:    t.MetaInfo,
+		Path:        opts.OutputPath,
+		Size:        info.TotalLength(),
+		InfoHash:    t.MetaInfo.HashInfoBytes().String(),
+		Files:       len(info.Files),
+		Pieces:      info.NumPieces(),
+		Hash:        t.hashStats,
+		Source:      info.Source,
+		Private:     info.Private != nil && *info.Private,
+		PieceLength: info.PieceLength,
+		LinkPath:    linkPath,
+		LinkCopied:  linkCopied,
 		Announce: func() string {
 			if len(opts.TrackerURLs) > 0 {
 				return opts.TrackerURLs[0]
@@ -682,3 +1371,23 @@ func Create(opts CreateOptions) (*TorrentInfo, error) {
 
 	return torrentInfo, nil
 }
+
+// ParseCreationDate parses a creation date given as RFC 3339, e.g.
+// "2023-01-02T15:04:05Z", or as unix seconds
+func ParseCreationDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if secs <= 0 {
+			return time.Time{}, fmt.Errorf("invalid date %q: unix seconds must be positive", s)
+		}
+		return time.Unix(secs, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use RFC 3339 (2023-01-02T15:04:05Z) or unix seconds", s)
+	}
+	if t.Unix() <= 0 {
+		return time.Time{}, fmt.Errorf("invalid date %q: must be after 1970-01-01", s)
+	}
+	return t, nil
+}
@@ -2,13 +2,16 @@ package torrent
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/anacrolix/torrent/metainfo"
 
@@ -19,6 +22,8 @@ func Test_calculatePieceLength(t *testing.T) {
 	tests := []struct {
 		name           string
 		totalSize      int64
+		fileCount      int
+		minPieceLength *uint
 		maxPieceLength *uint
 		trackerURLs    []string
 		want           uint
@@ -96,11 +101,88 @@ func Test_calculatePieceLength(t *testing.T) {
 			trackerURLs: []string{"https://unknown.tracker.com/announce"},
 			want:        23, // 8 MiB pieces
 		},
+		{
+			name:           "min piece length raises small content",
+			totalSize:      63 << 20,
+			minPieceLength: uintPtr(20),
+			want:           20, // 1 MiB pieces instead of 32 KiB
+		},
+		{
+			name:           "min piece length below calculation has no effect",
+			totalSize:      4100 << 20,
+			minPieceLength: uintPtr(18),
+			want:           22,
+		},
+		{
+			name:           "min and max piece length clamp together",
+			totalSize:      63 << 20,
+			minPieceLength: uintPtr(21),
+			maxPieceLength: uintPtr(21),
+			want:           21,
+		},
+		{
+			name:           "min piece length cannot exceed tracker cap",
+			totalSize:      1 << 30,
+			minPieceLength: uintPtr(25),
+			trackerURLs:    []string{"https://empornium.sx/announce?passkey=123"},
+			want:           23,
+		},
+		{
+			name:      "9999 small files keep the size based piece length",
+			totalSize: 500 << 20,
+			fileCount: 9_999,
+			want:      18, // 256 KiB pieces
+		},
+		{
+			name:      "10k small files double the piece length",
+			totalSize: 500 << 20,
+			fileCount: 10_000,
+			want:      19, // 512 KiB pieces
+		},
+		{
+			name:      "100k tiny files quadruple the piece length",
+			totalSize: 100 << 20,
+			fileCount: 100_000,
+			want:      18, // 256 KiB instead of 64 KiB pieces
+		},
+		{
+			name:      "1M files raise the piece length three steps",
+			totalSize: 1 << 30,
+			fileCount: 1_000_000,
+			want:      22, // 4 MiB instead of 512 KiB pieces
+		},
+		{
+			name:      "file count bias is bounded by the default max",
+			totalSize: 16500 << 20,
+			fileCount: 100_000,
+			want:      24,
+		},
+		{
+			name:           "file count bias is bounded by the user max",
+			totalSize:      500 << 20,
+			fileCount:      100_000,
+			maxPieceLength: uintPtr(20),
+			want:           20,
+		},
+		{
+			name:        "file count bias is bounded by the tracker max",
+			totalSize:   4100 << 20,
+			fileCount:   1_000_000,
+			trackerURLs: []string{"https://empornium.sx/announce?passkey=123"},
+			want:        23, // 4 MiB raised to 32 MiB, capped at 8 MiB
+		},
+		{
+			name:        "file count bias skips trackers with their own ranges",
+			totalSize:   500 << 20,
+			fileCount:   100_000,
+			trackerURLs: []string{"https://please.passthepopcorn.me:2710/passkey/announce"},
+			want:        20, // PTP range for 444-922 MiB
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculatePieceLength(tt.totalSize, tt.maxPieceLength, tt.trackerURLs, false)
+			got := calculatePieceLength(tt.totalSize, max(tt.fileCount, 1), tt.minPieceLength, tt.maxPieceLength, tt.trackerURLs, false)
 			if got != tt.want {
 				t.Errorf("calculatePieceLength() = %v, want %v", got, tt.want)
 			}
@@ -126,6 +208,7 @@ func Test_calculatePieceLengthFromTarget(t *testing.T) {
 		name           string
 		totalSize      int64
 		targetCount    uint
+		minPieceLength *uint
 		maxPieceLength *uint
 		trackerURLs    []string
 		wantExp        uint
@@ -201,11 +284,18 @@ func Test_calculatePieceLengthFromTarget(t *testing.T) {
 			targetCount: 500,
 			wantExp:     23, // ~4GB/500 = ~8MiB = 2^23
 		},
+		{
+			name:           "minPieceLength raises floor",
+			totalSize:      1 << 20,
+			targetCount:    1000,
+			minPieceLength: uintPtr(20),
+			wantExp:        20,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculatePieceLengthFromTarget(tt.totalSize, tt.targetCount, tt.maxPieceLength, tt.trackerURLs, false)
+			got := calculatePieceLengthFromTarget(tt.totalSize, tt.targetCount, tt.minPieceLength, tt.maxPieceLength, tt.trackerURLs, false)
 			if got != tt.wantExp {
 				t.Errorf("calculatePieceLengthFromTarget() = %v, want %v", got, tt.wantExp)
 			}
@@ -261,6 +351,19 @@ func TestGetRecommendedPieceLengthExpMatchesCreateMinimum(t *testing.T) {
 	}
 }
 
+func TestAutoPieceLengthExpMatchesCreate(t *testing.T) {
+	const size = 1 << 30
+	for _, fileCount := range []int{1, 20_000, 200_000} {
+		want := calculatePieceLength(size, fileCount, nil, nil, []string{"https://unknown.tracker/announce"}, false)
+		if got := AutoPieceLengthExp("https://unknown.tracker/announce", size, fileCount); got != want {
+			t.Errorf("AutoPieceLengthExp() with %d files = %d, want %d", fileCount, got, want)
+		}
+	}
+	if AutoPieceLengthExp("", size, 20_000) <= AutoPieceLengthExp("", size, 1) {
+		t.Error("expected many files to raise the automatic piece length")
+	}
+}
+
 func TestGetRecommendedPieceLengthExpUnknownTracker(t *testing.T) {
 	got := GetRecommendedPieceLengthExp("https://unknown.tracker/announce", 32<<20)
 	if got != 0 {
@@ -268,6 +371,90 @@ func TestGetRecommendedPieceLengthExpUnknownTracker(t *testing.T) {
 	}
 }
 
+func TestFitTorrentFileSize(t *testing.T) {
+	const ant = "https://anthelion.me/announce" // 250 KiB limit, no piece length cap
+	maxExp22 := uint(22)
+
+	tests := []struct {
+		name           string
+		tracker        string
+		contentSize    uint64
+		pieceLengthExp uint
+		maxPieceLength *uint
+		wantExp        uint
+		wantOK         bool
+	}{
+		{"no limit", "https://example.com/announce", 1 << 40, 16, nil, 16, true},
+		{"fits", ant, 10 << 30, 20, nil, 20, true},
+		{"raised until it fits", ant, 100 << 30, 20, nil, 23, true},
+		{"user max too low", ant, 100 << 30, 20, &maxExp22, 22, false},
+		{"too large at the ceiling", ant, 1 << 40, 20, nil, 24, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp, ok := FitTorrentFileSize(tt.tracker, tt.contentSize, tt.pieceLengthExp, tt.maxPieceLength)
+			if exp != tt.wantExp || ok != tt.wantOK {
+				t.Errorf("FitTorrentFileSize() = %d, %v, want %d, %v", exp, ok, tt.wantExp, tt.wantOK)
+			}
+		})
+	}
+
+	if got := EstimateTorrentFileSize(1<<20+1, 16); got != 17*20 {
+		t.Errorf("EstimateTorrentFileSize() = %d, want %d", got, 17*20)
+	}
+}
+
+func TestSmallestFittingExp(t *testing.T) {
+	const limit = 250 << 10 // 12800 pieces
+	tests := []struct {
+		name         string
+		contentSize  uint64
+		minExp, ceil uint
+		want         uint
+	}{
+		{"small content uses the minimum", 10 << 20, 16, 24, 16},
+		{"smallest piece length under the cap", 20 << 30, 16, 24, 21},
+		{"minimum above the fit", 20 << 30, 22, 24, 22},
+		{"nothing fits", 1 << 40, 16, 24, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := smallestFittingExp(tt.contentSize, limit, tt.minExp, tt.ceil); got != tt.want {
+				t.Errorf("smallestFittingExp() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_PieceLengthAutoMax(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(content, make([]byte, 1<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mi, err := CreateTorrent(CreateOptions{
+		Path:               content,
+		TrackerURLs:        []string{"https://anthelion.me/announce"},
+		PieceLengthAutoMax: true,
+		Quiet:              true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() error = %v", err)
+	}
+	if got := mi.GetInfo().PieceLength; got != 1<<16 {
+		t.Errorf("piece length = %d, want %d", got, 1<<16)
+	}
+
+	if _, err := CreateTorrent(CreateOptions{Path: content, PieceLengthAutoMax: true, Quiet: true}); err == nil {
+		t.Error("CreateTorrent() without a tracker size limit: expected an error")
+	}
+	exp := uint(18)
+	if _, err := CreateTorrent(CreateOptions{Path: content, PieceLengthAutoMax: true, PieceLengthExp: &exp, Quiet: true}); err == nil {
+		t.Error("CreateTorrent() with a piece length: expected an error")
+	}
+}
+
 func TestCreateTorrent_TargetPieceCountZero(t *testing.T) {
 	dir := t.TempDir()
 	data := make([]byte, 1<<20)
@@ -719,6 +906,278 @@ func TestCreate_MultipleTrackers(t *testing.T) {
 	}
 }
 
+func TestCreateTorrent_AnnounceList(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content for announce list"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tiers := [][]string{
+		{"https://tracker1.com/announce"},
+		{"udp://tracker2.com:1337/announce", "https://tracker3.com/announce"},
+	}
+	pieceLenExp := uint(16)
+
+	torrent, err := CreateTorrent(CreateOptions{
+		Path:           testFile,
+		TrackerURLs:    []string{"https://tracker1.com/announce"},
+		AnnounceList:   tiers,
+		PieceLengthExp: &pieceLenExp,
+		Quiet:          true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+
+	if torrent.Announce != "https://tracker1.com/announce" {
+		t.Errorf("Expected announce %q, got %q", "https://tracker1.com/announce", torrent.Announce)
+	}
+	if len(torrent.AnnounceList) != 2 || len(torrent.AnnounceList[1]) != 2 {
+		t.Fatalf("Expected tiers %v, got %v", tiers, torrent.AnnounceList)
+	}
+	if torrent.AnnounceList[1][1] != "https://tracker3.com/announce" {
+		t.Errorf("Expected %q in tier 1, got %v", "https://tracker3.com/announce", torrent.AnnounceList[1])
+	}
+}
+
+func TestCreateTorrent_CreatedBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content for created by"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	pieceLenExp := uint(16)
+
+	tests := []struct {
+		name      string
+		createdBy string
+		noCreator bool
+		want      string
+	}{
+		{name: "default", want: "mkbrr/test (https://github.com/autobrr/mkbrr)"},
+		{name: "no creator", noCreator: true, want: ""},
+		{name: "custom", createdBy: "GROUP", want: "GROUP"},
+		{name: "custom wins over no creator", createdBy: "GROUP", noCreator: true, want: "GROUP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			torrent, err := CreateTorrent(CreateOptions{
+				Path:           testFile,
+				PieceLengthExp: &pieceLenExp,
+				CreatedBy:      tt.createdBy,
+				NoCreator:      tt.noCreator,
+				Version:        "test",
+				Quiet:          true,
+			})
+			if err != nil {
+				t.Fatalf("CreateTorrent() failed: %v", err)
+			}
+			if torrent.CreatedBy != tt.want {
+				t.Errorf("Expected created by %q, got %q", tt.want, torrent.CreatedBy)
+			}
+		})
+	}
+}
+
+func TestParseCreationDate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "2023-01-02T15:04:05Z", want: 1672671845},
+		{input: "2023-01-02T16:04:05+01:00", want: 1672671845},
+		{input: "1672671845", want: 1672671845},
+		{input: " 1672671845 ", want: 1672671845},
+		{input: "0", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "2023-01-02", wantErr: true},
+		{input: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCreationDate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCreationDate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Unix() != tt.want {
+				t.Errorf("ParseCreationDate(%q) = %d, want %d", tt.input, got.Unix(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_CreationDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content for creation date"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	pieceLenExp := uint(16)
+
+	// a fixed date wins over NoDate
+	torrent, err := CreateTorrent(CreateOptions{
+		Path:           testFile,
+		PieceLengthExp: &pieceLenExp,
+		CreationDate:   time.Unix(1672671845, 0),
+		NoDate:         true,
+		Quiet:          true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+	if torrent.CreationDate != 1672671845 {
+		t.Errorf("Expected creation date %d, got %d", 1672671845, torrent.CreationDate)
+	}
+}
+
+func TestCreateTorrent_PublicTorrentForPrivateTracker(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content for private tracker check"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	opts := CreateOptions{
+		Path:        testFile,
+		TrackerURLs: []string{"https://passthepopcorn.me/announce?passkey=123"},
+		IsPrivate:   false,
+		Quiet:       true,
+	}
+
+	// without strict mode only a warning is printed
+	if _, err := CreateTorrent(opts); err != nil {
+		t.Fatalf("CreateTorrent() without strict failed: %v", err)
+	}
+
+	opts.Strict = true
+	_, err := CreateTorrent(opts)
+	if err == nil {
+		t.Fatal("Expected error for public torrent with private tracker in strict mode")
+	}
+	if strings.Contains(err.Error(), "passkey") {
+		t.Errorf("Expected error to omit the passkey, got: %v", err)
+	}
+
+	// a private torrent is fine in strict mode
+	opts.IsPrivate = true
+	if _, err := CreateTorrent(opts); err != nil {
+		t.Fatalf("CreateTorrent() for private torrent failed: %v", err)
+	}
+}
+
+func TestCreate_ExistingOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("existing output test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	outputPath := filepath.Join(tmpDir, "out.torrent")
+	if err := os.WriteFile(outputPath, []byte("previous"), 0644); err != nil {
+		t.Fatalf("Failed to create existing output: %v", err)
+	}
+
+	hashed := false
+	opts := CreateOptions{Path: testFile, OutputPath: outputPath, Quiet: true,
+		ProgressCallback: func(completed, total int, hashRate float64) { hashed = true }}
+	_, err := Create(opts)
+	if !errors.Is(err, ErrOutputExists) {
+		t.Fatalf("Create() error = %v, want ErrOutputExists", err)
+	}
+	if hashed {
+		t.Error("Expected the existing output to be reported before hashing")
+	}
+	if !strings.Contains(err.Error(), "modified") {
+		t.Errorf("Expected error to include the modification time, got: %v", err)
+	}
+	if data, _ := os.ReadFile(outputPath); string(data) != "previous" {
+		t.Error("Existing output file was changed")
+	}
+
+	opts.Force = true
+	if _, err := Create(opts); err != nil {
+		t.Fatalf("Create() with Force failed: %v", err)
+	}
+	if !hashed {
+		t.Error("Expected progress to be reported while hashing")
+	}
+	if _, err := LoadFromFile(outputPath); err != nil {
+		t.Errorf("Expected the output to be overwritten with a torrent: %v", err)
+	}
+}
+
+func TestCreateTorrent_CaseCollisions(t *testing.T) {
+	tmpDir := filepath.Join(t.TempDir(), "release")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+	for _, name := range []string{"Readme.txt", "README.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	if entries, err := os.ReadDir(tmpDir); err != nil || len(entries) != 2 {
+		t.Skip("filesystem is case-insensitive")
+	}
+
+	pieceLenExp := uint(16)
+	opts := CreateOptions{
+		Path:           tmpDir,
+		PieceLengthExp: &pieceLenExp,
+		Quiet:          true,
+	}
+
+	// without strict mode only a warning is printed
+	if _, err := CreateTorrent(opts); err != nil {
+		t.Fatalf("CreateTorrent() without strict failed: %v", err)
+	}
+
+	opts.Strict = true
+	_, err := CreateTorrent(opts)
+	if err == nil {
+		t.Fatal("Expected error for paths differing only by case in strict mode")
+	}
+	if !strings.Contains(err.Error(), "README.txt and Readme.txt") {
+		t.Errorf("Expected error to list the colliding paths, got: %v", err)
+	}
+}
+
+func TestCreateTorrent_FailOnSeasonPackWarning(t *testing.T) {
+	tmpDir := filepath.Join(t.TempDir(), "Show.S01.1080p")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+	for _, name := range []string{"Show.S01E01.mkv", "Show.S01E02.mkv", "Show.S01E04.mkv"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("episode "+name), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	opts := CreateOptions{
+		Path:       tmpDir,
+		OutputPath: filepath.Join(t.TempDir(), "out.torrent"),
+		IsPrivate:  true,
+		Quiet:      true,
+	}
+
+	// without the flag the warning is informational only
+	if _, err := CreateTorrent(opts); err != nil {
+		t.Fatalf("CreateTorrent() without fail on season warning failed: %v", err)
+	}
+
+	opts.FailOnSeasonPackWarning = true
+	_, err := CreateTorrent(opts)
+	if err == nil {
+		t.Fatal("Expected error for incomplete season pack")
+	}
+	if !strings.Contains(err.Error(), "season 1 is missing episodes 3") {
+		t.Errorf("Expected error to name the missing episode, got: %v", err)
+	}
+}
+
 func TestCreate_UsesCustomNameForOutputPath(t *testing.T) {
 	t.Parallel()
 
@@ -779,6 +1238,104 @@ func TestCreate_UsesCustomNameForOutputPath(t *testing.T) {
 	}
 }
 
+func TestCreate_OutputPattern(t *testing.T) {
+	workspace := t.TempDir()
+	inputPath := filepath.Join(workspace, "video.mkv")
+	if err := os.WriteFile(inputPath, []byte("tiny sample for output pattern"), 0644); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	info, err := Create(CreateOptions{
+		Path:          inputPath,
+		Name:          "Custom Show",
+		TrackerURLs:   []string{"https://tracker.example.com/announce"},
+		OutputDir:     filepath.Join(workspace, "out"),
+		OutputPattern: "{tracker}_{name}_{infohash8}",
+		Quiet:         true,
+	})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	wantFile := "example_Custom_Show_" + info.InfoHash[:8] + ".torrent"
+	if got := filepath.Base(info.Path); got != wantFile {
+		t.Fatalf("expected torrent output %q, got %q", wantFile, got)
+	}
+	if _, err := os.Stat(info.Path); err != nil {
+		t.Fatalf("expected torrent file to exist at %q, got error: %v", info.Path, err)
+	}
+}
+
+func TestCreate_OutputPrefixSuffix(t *testing.T) {
+	workspace := t.TempDir()
+	inputPath := filepath.Join(workspace, "video.mkv")
+	if err := os.WriteFile(inputPath, []byte("tiny sample for prefix and suffix"), 0644); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		skipPrefix bool
+		want       string
+	}{
+		{name: "composes with tracker prefix", want: "archive-example_video.mkv.v2.torrent"},
+		{name: "replaces tracker prefix", skipPrefix: true, want: "archive-video.mkv.v2.torrent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Create(CreateOptions{
+				Path:         inputPath,
+				TrackerURLs:  []string{"https://tracker.example.com/announce"},
+				OutputDir:    filepath.Join(workspace, "out"),
+				OutputPrefix: "archive-",
+				OutputSuffix: ".v2",
+				SkipPrefix:   tt.skipPrefix,
+				Force:        true,
+				Quiet:        true,
+			})
+			if err != nil {
+				t.Fatalf("Create returned error: %v", err)
+			}
+			if got := filepath.Base(info.Path); got != tt.want {
+				t.Errorf("expected torrent output %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Create(CreateOptions{Path: inputPath, OutputPrefix: "../", Quiet: true}); err == nil {
+		t.Error("Create with a path separator in the prefix succeeded, want error")
+	}
+}
+
+func TestCreate_HashStats(t *testing.T) {
+	workspace := t.TempDir()
+	inputPath := filepath.Join(workspace, "video.mkv")
+	data := make([]byte, 3<<20)
+	if err := os.WriteFile(inputPath, data, 0644); err != nil {
+		t.Fatalf("failed to write input file: %v", err)
+	}
+
+	info, err := Create(CreateOptions{
+		Path:      inputPath,
+		OutputDir: filepath.Join(workspace, "out"),
+		Quiet:     true,
+	})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	if info.Hash.BytesHashed != int64(len(data)) {
+		t.Errorf("BytesHashed = %d, want %d", info.Hash.BytesHashed, len(data))
+	}
+	if info.Hash.Elapsed <= 0 {
+		t.Errorf("Elapsed = %v, want > 0", info.Hash.Elapsed)
+	}
+	if info.Hash.Rate() <= 0 {
+		t.Errorf("Rate() = %v, want > 0", info.Hash.Rate())
+	}
+}
+
 func TestCreate_NameArgument(t *testing.T) {
 
 	tracker := "https://unknown.customtracker.com/announce"
@@ -1035,3 +1592,320 @@ func TestCreate_NameArgument(t *testing.T) {
 		})
 	}
 }
+
+func TestTorrentPathComponents(t *testing.T) {
+	tests := []struct {
+		name      string
+		relPath   string
+		separator rune
+		want      []string
+	}{
+		{name: "unix nested", relPath: "Season 1/Extras/file.mkv", separator: '/', want: []string{"Season 1", "Extras", "file.mkv"}},
+		{name: "windows nested", relPath: `Season 1\Extras\file.mkv`, separator: '\\', want: []string{"Season 1", "Extras", "file.mkv"}},
+		{name: "windows single", relPath: "file.mkv", separator: '\\', want: []string{"file.mkv"}},
+		{name: "trailing and duplicate slashes", relPath: "./dir//file.mkv/", separator: '/', want: []string{"dir", "file.mkv"}},
+		{name: "mixed separators on windows", relPath: `dir/sub\file.mkv`, separator: '\\', want: []string{"dir", "sub", "file.mkv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := torrentPathComponents(tt.relPath, tt.separator)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("torrentPathComponents(%q) = %q, want %q", tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_SingleNestedFileInDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "Release")
+	nested := filepath.Join(root, "Sub", "Dir", "video.mkv")
+	if err := os.MkdirAll(filepath.Dir(nested), 0755); err != nil {
+		t.Fatalf("Failed to create dirs: %v", err)
+	}
+	if err := os.WriteFile(nested, []byte("nested single file"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// trailing separator on the input path must not change the layout
+	mi, err := CreateTorrent(CreateOptions{Path: root + string(filepath.Separator), Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		t.Fatalf("UnmarshalInfo() failed: %v", err)
+	}
+	if info.Name != "Release" {
+		t.Errorf("info.Name = %q, want Release", info.Name)
+	}
+	if len(info.Files) != 1 {
+		t.Fatalf("expected 1 file entry, got %d", len(info.Files))
+	}
+	if got := strings.Join(info.Files[0].Path, "/"); got != "Sub/Dir/video.mkv" {
+		t.Errorf("file path = %q, want Sub/Dir/video.mkv", got)
+	}
+	for _, component := range info.Files[0].Path {
+		if strings.ContainsAny(component, `/\`) {
+			t.Errorf("path component %q contains a separator", component)
+		}
+	}
+}
+
+func TestCreateTorrent_EmptyDirs(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Release")
+	for _, dir := range []string{"Extras", "Empty/Nested", "Other", "OnlyExcluded"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+	}
+	for _, file := range []string{"video.mkv", "Extras/sample.mkv", "OnlyExcluded/info.nfo"} {
+		if err := os.WriteFile(filepath.Join(root, file), []byte("content "+file), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	opts := CreateOptions{Path: root, ExcludePatterns: []string{"*.nfo"}, Quiet: true}
+	if _, err := CreateTorrent(opts); err != nil {
+		t.Fatalf("CreateTorrent() should skip empty dirs by default, got: %v", err)
+	}
+
+	opts.FailOnEmptyDirs = true
+	_, err := CreateTorrent(opts)
+	if err == nil {
+		t.Fatal("Expected error with FailOnEmptyDirs")
+	}
+	// nested empty dirs are reported via their top-most empty parent, and
+	// directories whose files were only excluded are not empty on disk
+	if !strings.Contains(err.Error(), "2 empty directories: Empty, Other") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateTorrent_NameKeepsLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "Show.S01.1080p.WEB-DL-GRP")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	for _, name := range []string{"e01.mkv", "e02.mkv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content "+name), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	// directory: name replaces the folder name, file paths stay relative to it
+	mi, err := CreateTorrent(CreateOptions{Path: dir, Name: "Show.S01", Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		t.Fatalf("UnmarshalInfo() failed: %v", err)
+	}
+	if info.Name != "Show.S01" || len(info.Files) != 2 || strings.Join(info.Files[0].Path, "/") != "e01.mkv" {
+		t.Errorf("unexpected directory layout: name %q, files %+v", info.Name, info.Files)
+	}
+
+	// single file: name replaces the file name and the torrent stays single-file
+	mi, err = CreateTorrent(CreateOptions{Path: filepath.Join(dir, "e01.mkv"), Name: "Episode.mkv", Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+	info, err = mi.UnmarshalInfo()
+	if err != nil {
+		t.Fatalf("UnmarshalInfo() failed: %v", err)
+	}
+	if info.Name != "Episode.mkv" || len(info.Files) != 0 || info.Length == 0 {
+		t.Errorf("unexpected single-file layout: name %q, files %+v, length %d", info.Name, info.Files, info.Length)
+	}
+}
+
+func TestCheckWritten(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(contentPath, bytes.Repeat([]byte("x"), 1<<16), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	torrentInfo, err := Create(CreateOptions{
+		Path:        contentPath,
+		TrackerURLs: []string{"https://tracker.example.com/announce"},
+		OutputPath:  filepath.Join(tmpDir, "content.torrent"),
+		IsPrivate:   true,
+		Quiet:       true,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	want := torrentInfo.MetaInfo.HashInfoBytes()
+
+	if err := checkWritten(torrentInfo.Path, want); err != nil {
+		t.Fatalf("checkWritten() error = %v", err)
+	}
+
+	err = checkWritten(torrentInfo.Path, metainfo.Hash{})
+	if !errors.Is(err, ErrInfoHashMismatch) {
+		t.Errorf("checkWritten() with another hash error = %v, want ErrInfoHashMismatch", err)
+	}
+	if _, statErr := os.Stat(torrentInfo.Path); !os.IsNotExist(statErr) {
+		t.Errorf("torrent file left behind after failed check (stat err %v)", statErr)
+	}
+
+	broken := filepath.Join(tmpDir, "broken.torrent")
+	if err := os.WriteFile(broken, []byte("d4:infod6:lengthi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWritten(broken, want); !errors.Is(err, ErrCorruptTorrent) {
+		t.Errorf("checkWritten() of a truncated file error = %v, want ErrCorruptTorrent", err)
+	}
+}
+
+func TestCreate_ReturnsCounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "Counts")
+	if err := os.MkdirAll(contentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := os.WriteFile(filepath.Join(contentDir, name), make([]byte, 70000), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exp := uint(16)
+	tests := []struct {
+		name       string
+		path       string
+		wantFiles  int
+		wantPieces int
+		wantSize   int64
+	}{
+		{name: "multi file", path: contentDir, wantFiles: 2, wantPieces: 3, wantSize: 140000},
+		{name: "single file", path: filepath.Join(contentDir, "a.bin"), wantFiles: 0, wantPieces: 2, wantSize: 70000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			torrentInfo, err := Create(CreateOptions{
+				Path:           tt.path,
+				PieceLengthExp: &exp,
+				OutputDir:      t.TempDir(),
+				Quiet:          true,
+			})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if torrentInfo.Files != tt.wantFiles || torrentInfo.Pieces != tt.wantPieces || torrentInfo.Size != tt.wantSize {
+				t.Errorf("Create() returned %d files, %d pieces, size %d, want %d, %d, %d",
+					torrentInfo.Files, torrentInfo.Pieces, torrentInfo.Size, tt.wantFiles, tt.wantPieces, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestCreate_Cancel(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(contentPath, make([]byte, 200000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	exp := uint(16)
+	outputPath := filepath.Join(tmpDir, "content.torrent")
+	_, err := CreateContext(ctx, CreateOptions{
+		Path:           contentPath,
+		OutputPath:     outputPath,
+		PieceLengthExp: &exp,
+		Quiet:          true,
+	})
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateContext() error = %v, want ErrCancelled and context.Canceled", err)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file after cancelling, stat error = %v", err)
+	}
+}
+
+func TestWriteOutputFile_RemovesOnCancel(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(contentPath, []byte("partial output test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tor, err := CreateTorrent(CreateOptions{Path: contentPath, Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent() error = %v", err)
+	}
+
+	// cancelled while the torrent is being written
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outputPath := filepath.Join(tmpDir, "content.torrent")
+	f, err := createOutputFile(outputPath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOutputFile(ctx, f, tor); !errors.Is(err, ErrCancelled) {
+		t.Fatalf("writeOutputFile() error = %v, want ErrCancelled", err)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the output file to be removed, stat error = %v", err)
+	}
+}
+
+func TestCreateTorrent_LegacyUTF8(t *testing.T) {
+	contentDir := filepath.Join(t.TempDir(), "Crème Brûlée")
+	if err := os.MkdirAll(filepath.Join(contentDir, "Über"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", filepath.Join("Über", "日本.txt")} {
+		if err := os.WriteFile(filepath.Join(contentDir, name), make([]byte, 70000), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exp := uint(16)
+	create := func(legacy bool) *metainfo.Info {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{
+			Path:           contentDir,
+			PieceLengthExp: &exp,
+			Padded:         true,
+			LegacyUTF8:     legacy,
+			NoDate:         true,
+			Quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent() error = %v", err)
+		}
+		return mi.GetInfo()
+	}
+
+	plain := create(false)
+	if plain.NameUtf8 != "" {
+		t.Errorf("name.utf-8 = %q without LegacyUTF8, want none", plain.NameUtf8)
+	}
+
+	info := create(true)
+	if info.NameUtf8 != "Crème Brûlée" {
+		t.Errorf("name.utf-8 = %q, want %q", info.NameUtf8, "Crème Brûlée")
+	}
+	for _, f := range info.Files {
+		if isPaddingFile(f) {
+			if f.PathUtf8 != nil {
+				t.Errorf("padding file %v has path.utf-8 %v, want none", f.Path, f.PathUtf8)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(f.PathUtf8, f.Path) {
+			t.Errorf("path.utf-8 = %v, want %v", f.PathUtf8, f.Path)
+		}
+	}
+}
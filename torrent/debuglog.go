@@ -0,0 +1,27 @@
+package torrent
+
+import (
+	"io"
+	"log"
+)
+
+// debugLog receives diagnostic messages about how content is read, such as the
+// detected storage, I/O mode, worker counts and read retries. It discards them
+// unless SetDebugLog is called.
+var debugLog = log.New(io.Discard, "", 0)
+
+// SetDebugLog sends diagnostic messages to w with timestamps, or discards them
+// again for a nil w. Regular output is not affected.
+func SetDebugLog(w io.Writer) {
+	if w == nil {
+		debugLog.SetOutput(io.Discard)
+		return
+	}
+	debugLog.SetOutput(w)
+	debugLog.SetFlags(log.LstdFlags | log.Lmicroseconds)
+}
+
+// debugf writes a diagnostic message, see SetDebugLog
+func debugf(format string, args ...any) {
+	debugLog.Printf(format, args...)
+}
@@ -18,10 +18,13 @@ import (
 
 type Display struct {
 	output    io.Writer
+	stdout    io.Writer // where output goes when not quiet, os.Stdout by default
+	errOutput io.Writer // warnings and errors, kept out of output and shown even in quiet mode
 	formatter *Formatter
 	bar       *progressbar.ProgressBar
 	isBatch   bool
 	quiet     bool
+	redact    bool
 }
 
 func NewDisplay(formatter *Formatter) *Display {
@@ -29,32 +32,47 @@ func NewDisplay(formatter *Formatter) *Display {
 		formatter: formatter,
 		quiet:     false,
 		output:    os.Stdout,
+		stdout:    os.Stdout,
+		errOutput: os.Stderr,
 	}
 }
 
+// SetOutput sets where regular output is written, os.Stdout by default
+func (d *Display) SetOutput(w io.Writer) {
+	d.stdout = w
+	if !d.quiet {
+		d.output = w
+	}
+}
+
+// SetErrorOutput sets where warnings and errors are written, os.Stderr by default
+func (d *Display) SetErrorOutput(w io.Writer) {
+	d.errOutput = w
+}
+
 // SetQuiet enables/disables quiet mode (output redirected to io.Discard)
 func (d *Display) SetQuiet(quiet bool) {
 	d.quiet = quiet
 	if quiet {
 		d.output = io.Discard
 	} else {
-		d.output = os.Stdout
+		d.output = d.stdout
 	}
 }
 
 func (d *Display) ShowProgress(total int) {
 	// Progress bar needs explicit quiet check because it writes directly to the terminal,
 	// bypassing our d.output writer
-	if d.quiet {
+	if d.isBatch || d.quiet {
 		return
 	}
 	fmt.Fprintln(d.output)
 	d.bar = progressbar.NewOptions(total,
-		progressbar.OptionEnableColorCodes(true),
-		progressbar.OptionSetDescription("[cyan][bold]Hashing pieces...[reset]"),
+		progressbar.OptionEnableColorCodes(!color.NoColor),
+		progressbar.OptionSetDescription(barMarkup("[cyan][bold]Hashing pieces...[reset]")),
 		progressbar.OptionSetTheme(progressbar.Theme{
-			Saucer:        "[green]=[reset]",
-			SaucerHead:    "[green]>[reset]",
+			Saucer:        barMarkup("[green]=[reset]"),
+			SaucerHead:    barMarkup("[green]>[reset]"),
 			SaucerPadding: " ",
 			BarStart:      "[",
 			BarEnd:        "]",
@@ -62,6 +80,18 @@ func (d *Display) ShowProgress(total int) {
 	)
 }
 
+// barColorTags are the progress bar color tags used above
+var barColorTags = strings.NewReplacer("[cyan]", "", "[bold]", "", "[green]", "", "[reset]", "")
+
+// barMarkup returns s for the progress bar, with its color tags removed when
+// colors are disabled, as the bar then prints them literally
+func barMarkup(s string) string {
+	if color.NoColor {
+		return barColorTags.Replace(s)
+	}
+	return s
+}
+
 func (d *Display) UpdateProgress(completed int, hashrate float64) {
 	// Progress bar needs explicit quiet check because it writes directly to the terminal,
 	// bypassing our d.output writer
@@ -75,7 +105,7 @@ func (d *Display) UpdateProgress(completed int, hashrate float64) {
 
 		if hashrate > 0 {
 			hrStr := d.formatter.FormatBytes(int64(hashrate))
-			description := fmt.Sprintf("[cyan][bold]Hashing pieces...[reset] [%s/s]", hrStr)
+			description := barMarkup(fmt.Sprintf("[cyan][bold]Hashing pieces...[reset] [%s/s]", hrStr))
 			d.bar.Describe(description)
 		}
 	}
@@ -206,7 +236,7 @@ func (d *Display) ShowFiles(files []fileEntry, numWorkers int) {
 func (d *Display) FinishProgress() {
 	// Progress bar needs explicit quiet check because it writes directly to the terminal,
 	// bypassing our d.output writer
-	if d.quiet {
+	if d.isBatch || d.quiet {
 		return
 	}
 	if d.bar != nil {
@@ -225,6 +255,19 @@ func (d *Display) SetBatch(isBatch bool) {
 	d.isBatch = isBatch
 }
 
+// SetRedact masks passkeys in displayed tracker URLs and magnet links, see RedactTrackerURL
+func (d *Display) SetRedact(redact bool) {
+	d.redact = redact
+}
+
+// trackerURL returns a tracker URL for display, redacted if enabled
+func (d *Display) trackerURL(trackerURL string) string {
+	if d.redact {
+		return RedactTrackerURL(trackerURL)
+	}
+	return trackerURL
+}
+
 var (
 	magenta = color.New(color.FgMagenta).SprintFunc()
 	//green      = color.New(color.FgGreen).SprintFunc()
@@ -241,11 +284,26 @@ func (d *Display) ShowMessage(msg string) {
 }
 
 func (d *Display) ShowError(msg string) {
-	fmt.Fprintln(d.output, errorColor(msg))
+	fmt.Fprintln(d.errOutput, errorColor(msg))
 }
 
 func (d *Display) ShowWarning(msg string) {
-	fmt.Fprintf(d.output, "%s %s\n", yellow("Warning:"), msg)
+	fmt.Fprintf(d.errOutput, "%s %s\n", yellow("Warning:"), msg)
+}
+
+// ShowNote prints an informational note to the error output, keeping stdout clean
+// for scripts. Unlike warnings, notes are hidden in quiet mode.
+func (d *Display) ShowNote(msg string) {
+	if d.quiet {
+		return
+	}
+	fmt.Fprintf(d.errOutput, "%s %s\n", yellow("Note:"), msg)
+}
+
+// showWarning prints msg through the display's warning path, for code that runs
+// before a display is set up. Like ShowWarning it is shown even in quiet mode.
+func showWarning(msg string) {
+	NewDisplay(NewFormatter(false)).ShowWarning(msg)
 }
 
 func (d *Display) ShowTorrentInfo(t *Torrent, info *metainfo.Info) {
@@ -258,18 +316,22 @@ func (d *Display) ShowTorrentInfo(t *Torrent, info *metainfo.Info) {
 
 	magnet, err := t.MagnetV2()
 	if err == nil {
-		fmt.Fprintf(d.output, "  %-13s %s\n", label("Magnet:"), magnet.String())
+		magnetURI := magnet.String()
+		if d.redact {
+			magnetURI = redactMagnet(magnetURI)
+		}
+		fmt.Fprintf(d.output, "  %-13s %s\n", label("Magnet:"), magnetURI)
 	}
 
 	if t.AnnounceList != nil {
-		fmt.Fprintf(d.output, "  %-13s\n", label("Trackers:"))
+		fmt.Fprintf(d.output, "  %s\n", label("Trackers:"))
 		for _, tier := range t.AnnounceList {
 			for _, tracker := range tier {
-				fmt.Fprintf(d.output, "    %s\n", success(tracker))
+				fmt.Fprintf(d.output, "    %s\n", success(d.trackerURL(tracker)))
 			}
 		}
 	} else if t.Announce != "" {
-		fmt.Fprintf(d.output, "  %-13s %s\n", label("Tracker:"), success(t.Announce))
+		fmt.Fprintf(d.output, "  %-13s %s\n", label("Tracker:"), success(d.trackerURL(t.Announce)))
 	}
 
 	if len(t.UrlList) > 0 {
@@ -279,6 +341,13 @@ func (d *Display) ShowTorrentInfo(t *Torrent, info *metainfo.Info) {
 		}
 	}
 
+	if len(t.Nodes) > 0 {
+		fmt.Fprintf(d.output, "  %-13s\n", label("DHT nodes:"))
+		for _, node := range t.Nodes {
+			fmt.Fprintf(d.output, "    %s\n", highlight(node))
+		}
+	}
+
 	if info.Private != nil && *info.Private {
 		fmt.Fprintf(d.output, "  %-13s %s\n", label("Private:"), "yes")
 	}
@@ -336,6 +405,49 @@ func (d *Display) ShowOutputPathWithTime(path string, duration time.Duration) {
 		magenta(fmt.Sprintf("elapsed %s", d.formatter.FormatDuration(duration))))
 }
 
+// ShowLinkedOutput reports where the torrent was linked or, where symlinks are not
+// supported, copied to, see CreateOptions.SymlinkTo
+func (d *Display) ShowLinkedOutput(path string, copied bool) {
+	if copied {
+		fmt.Fprintf(d.output, "%s %s, symlinks are not supported there\n", label("Copied to"), white(path))
+		return
+	}
+	fmt.Fprintf(d.output, "%s %s\n", label("Linked:"), white(path))
+}
+
+// ShowHashSummary displays the total bytes hashed and the average hashrate
+func (d *Display) ShowHashSummary(stats HashStats) {
+	if d.quiet || stats.BytesHashed == 0 {
+		return
+	}
+	fmt.Fprintf(d.output, "%s %s (%s)\n",
+		label("Hashed"),
+		d.formatter.FormatBytes(stats.BytesHashed),
+		magenta(fmt.Sprintf("avg %s/s", d.formatter.FormatBytes(int64(stats.Rate())))))
+}
+
+// ShowTrackerStatuses prints the outcome of CheckTrackers. In quiet mode only
+// unreachable trackers are shown, as warnings.
+func (d *Display) ShowTrackerStatuses(statuses []TrackerStatus) {
+	if d.quiet {
+		for _, s := range statuses {
+			if !s.Reachable {
+				d.ShowWarning(fmt.Sprintf("tracker %s is unreachable: %s", s.Tracker, s.Error))
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(d.output, "\n%s\n", magenta("Trackers:"))
+	for _, s := range statuses {
+		if s.Reachable {
+			fmt.Fprintf(d.output, "  %s %s (%s, %d ms)\n", success("reachable  "), s.Tracker, s.Status, s.LatencyMs)
+		} else {
+			fmt.Fprintf(d.output, "  %s %s (%s)\n", errorColor("unreachable"), s.Tracker, s.Error)
+		}
+	}
+}
+
 func (d *Display) ShowBatchResults(results []BatchResult, duration time.Duration) {
 	fmt.Fprintf(d.output, "\n%s\n", magenta("Batch processing results:"))
 
@@ -369,7 +481,20 @@ func (d *Display) ShowBatchResults(results []BatchResult, duration time.Duration
 				fmt.Fprintf(d.output, "  %-11s %s\n", label("Output:"), result.Info.Path)
 				fmt.Fprintf(d.output, "  %-11s %s\n", label("Size:"), d.formatter.FormatBytes(result.Info.Size))
 				fmt.Fprintf(d.output, "  %-11s %s\n", label("Info hash:"), result.Info.InfoHash)
-				fmt.Fprintf(d.output, "  %-11s %s\n", label("Trackers:"), strings.Join(result.Trackers, ", "))
+				fmt.Fprintf(d.output, "  %-11s %s\n", label("Trackers:"), batchTrackers(result))
+				source := result.Info.Source
+				if source == "" {
+					source = "none"
+				}
+				fmt.Fprintf(d.output, "  %-11s %s\n", label("Source:"), source)
+				private := "no"
+				if result.Info.Private {
+					private = "yes"
+				}
+				fmt.Fprintf(d.output, "  %-11s %s\n", label("Private:"), private)
+				if result.Info.PieceLength > 0 {
+					fmt.Fprintf(d.output, "  %-11s %s\n", label("Piece size:"), d.formatter.FormatBytes(result.Info.PieceLength))
+				}
 				if result.Info.Files > 0 {
 					fmt.Fprintf(d.output, "  %-11s %d\n", label("Files:"), result.Info.Files)
 				}
@@ -382,6 +507,108 @@ func (d *Display) ShowBatchResults(results []BatchResult, duration time.Duration
 	}
 }
 
+// batchTrackers lists the trackers a batch job's torrent announces to, with tiers
+// of the announce list separated by " | "
+func batchTrackers(result BatchResult) string {
+	if result.Info.MetaInfo == nil || len(result.Info.MetaInfo.AnnounceList) == 0 {
+		return strings.Join(result.Trackers, ", ")
+	}
+	tiers := make([]string, 0, len(result.Info.MetaInfo.AnnounceList))
+	for _, tier := range result.Info.MetaInfo.AnnounceList {
+		tiers = append(tiers, strings.Join(tier, ", "))
+	}
+	return strings.Join(tiers, " | ")
+}
+
+// ShowBatchVerificationResults displays a summary table of a batch verification
+func (d *Display) ShowBatchVerificationResults(results []BatchVerifyResult, duration time.Duration) {
+	fmt.Fprintf(d.output, "\n%s\n", magenta("Batch verification results:"))
+
+	complete, incomplete, failed := 0, 0, 0
+	nameWidth := len("Name")
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			failed++
+		case result.Complete():
+			complete++
+		default:
+			incomplete++
+		}
+		nameWidth = max(nameWidth, len(result.Name))
+	}
+	nameWidth = min(nameWidth, 60)
+
+	fmt.Fprintf(d.output, "  %-15s %d\n", label("Total torrents:"), len(results))
+	fmt.Fprintf(d.output, "  %-15s %s\n", label("Complete:"), success(complete))
+	fmt.Fprintf(d.output, "  %-15s %s\n", label("Incomplete:"), errorColor(incomplete))
+	fmt.Fprintf(d.output, "  %-15s %s\n", label("Failed:"), errorColor(failed))
+	fmt.Fprintf(d.output, "  %-15s %s\n", label("Check time:"), d.formatter.FormatDuration(duration))
+
+	fmt.Fprintf(d.output, "\n  %s  %s  %s  %s\n",
+		label(fmt.Sprintf("%-*s", nameWidth, "Name")),
+		label(fmt.Sprintf("%10s", "Completion")),
+		label(fmt.Sprintf("%6s", "Bad")),
+		label(fmt.Sprintf("%7s", "Missing")))
+	for _, result := range results {
+		name := result.Name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-3] + "..."
+		}
+		name = fmt.Sprintf("%-*s", nameWidth, name)
+
+		if result.Error != nil {
+			fmt.Fprintf(d.output, "  %s  %s\n", name, errorColor(result.Error))
+			continue
+		}
+
+		completion := fmt.Sprintf("%9.2f%%", result.Result.Completion)
+		if result.Complete() {
+			completion = success(completion)
+		} else {
+			completion = errorColor(completion)
+		}
+		fmt.Fprintf(d.output, "  %s  %s  %6d  %7d\n", name, completion, result.Result.BadPieces, len(result.Result.MissingFiles))
+	}
+
+	// extra files are only collected with --report-extra and don't make a torrent incomplete
+	for _, result := range results {
+		if result.Error == nil && len(result.Result.ExtraFiles) > 0 {
+			fmt.Fprintf(d.output, "  %s %s: %d extra files\n", yellow("!"), result.Name, len(result.Result.ExtraFiles))
+		}
+	}
+
+	if d.formatter.verbose {
+		for _, result := range results {
+			if result.Error != nil || result.Complete() {
+				continue
+			}
+			fmt.Fprintf(d.output, "\n%s %s:\n", label("Torrent"), result.Name)
+			fmt.Fprintf(d.output, "  %-11s %s\n", label("Torrent:"), result.TorrentPath)
+			fmt.Fprintf(d.output, "  %-11s %s\n", label("Content:"), result.ContentPath)
+			if len(result.Result.BadPieceIndices) > 0 {
+				indices := make([]string, 0, len(result.Result.BadPieceIndices))
+				for i, idx := range result.Result.BadPieceIndices {
+					if i >= 20 {
+						indices = append(indices, "...")
+						break
+					}
+					indices = append(indices, fmt.Sprint(idx))
+				}
+				fmt.Fprintf(d.output, "  %-11s %s\n", label("Bad pieces:"), strings.Join(indices, ", "))
+			}
+			maxFilesToShow := 10
+			for i, file := range result.Result.MissingFiles {
+				if i >= maxFilesToShow {
+					fmt.Fprintf(d.output, "  %-11s ...and %d more\n", "", len(result.Result.MissingFiles)-maxFilesToShow)
+					break
+				}
+				fmt.Fprintf(d.output, "  %-11s %s\n", label("Missing:"), file)
+			}
+		}
+	}
+}
+
 type Formatter struct {
 	verbose bool
 }
@@ -418,18 +645,32 @@ func (d *Display) ShowSeasonPackWarnings(info *SeasonPackInfo) {
 	}
 
 	if len(info.MissingEpisodes) > 0 {
-		fmt.Fprintf(d.output, "\n%s %s\n", yellow("Warning:"), "Possible incomplete season pack detected")
-		fmt.Fprintf(d.output, "  %-13s %d\n", label("Season number:"), info.Season)
-		fmt.Fprintf(d.output, "  %-13s %d\n", label("Highest episode number found:"), info.MaxEpisode)
-		fmt.Fprintf(d.output, "  %-13s %d\n", label("Episodes found:"), len(info.Episodes))
+		w := d.errOutput
+		fmt.Fprintf(w, "\n%s %s\n", yellow("Warning:"), "Possible incomplete season pack detected")
+		fmt.Fprintf(w, "  %-13s %d\n", label("Season number:"), info.Season)
+		if info.ExpectedEpisodes != nil {
+			fmt.Fprintf(w, "  %-13s %d-%d\n", label("Expected episodes:"), info.ExpectedEpisodes.First, info.ExpectedEpisodes.Last)
+		}
+		fmt.Fprintf(w, "  %-13s %d\n", label("Highest episode number found:"), info.MaxEpisode)
+		fmt.Fprintf(w, "  %-13s %d\n", label("Episodes found:"), len(info.Episodes))
 
 		missingStrs := make([]string, len(info.MissingEpisodes))
 		for i, ep := range info.MissingEpisodes {
 			missingStrs[i] = fmt.Sprintf("episode %d", ep)
 		}
-		fmt.Fprintf(d.output, "  %-13s %s\n", label("Missing:"), strings.Join(missingStrs, ", "))
+		fmt.Fprintf(w, "  %-13s %s\n", label("Missing:"), strings.Join(missingStrs, ", "))
 
-		fmt.Fprintln(d.output, yellow("\nThis may be an incomplete season pack. Check files before uploading."))
+		fmt.Fprintln(w, yellow("\nThis may be an incomplete season pack. Check files before uploading."))
+		return
+	}
+
+	if d.formatter.verbose {
+		fmt.Fprintf(d.output, "\n%s\n", magenta("Season pack:"))
+		fmt.Fprintf(d.output, "  %-13s %d\n", label("Season number:"), info.Season)
+		if info.DetectedPattern != "" {
+			fmt.Fprintf(d.output, "  %-13s %s\n", label("Detected from:"), info.DetectedPattern)
+		}
+		fmt.Fprintf(d.output, "  %-13s %d\n", label("Episodes found:"), len(info.Episodes))
 	}
 }
 
@@ -454,6 +695,24 @@ func (d *Display) ShowVerificationResult(result *VerificationResult, duration ti
 			}
 			fmt.Fprintf(d.output, "    %s %s\n", label("Indices:"), strings.Join(indicesStr, ", "))
 		}
+		if loc := result.FirstBadPiece; loc != nil {
+			fmt.Fprintf(d.output, "    %s piece %d (torrent bytes %d-%d)\n", label("First bad:"), loc.Piece, loc.Offset, loc.Offset+loc.Length)
+			for i, span := range loc.Spans {
+				prefix := "    ├─"
+				if i == len(loc.Spans)-1 {
+					prefix = "    └─"
+				}
+				var note string
+				if span.Padding {
+					note = " (padding)"
+				} else if span.ReadError != "" {
+					note = " " + errorColor("(could not read: "+span.ReadError+")")
+				} else if span.Zeroed {
+					note = " " + yellow("(all zeros, likely never written)")
+				}
+				fmt.Fprintf(d.output, "    %s %s bytes %d-%d%s\n", errorColor(prefix), span.Path, span.Start, span.End, note)
+			}
+		}
 	}
 
 	if len(result.MissingFiles) > 0 {
@@ -474,5 +733,100 @@ func (d *Display) ShowVerificationResult(result *VerificationResult, duration ti
 		}
 	}
 
+	if len(result.BadFiles) > 0 {
+		fmt.Fprintf(d.output, "  %-15s %s\n", label("Bad files:"), errorColor(len(result.BadFiles)))
+		if d.formatter.verbose {
+			maxFilesToShow := 10
+			for i, file := range result.BadFiles {
+				if i >= maxFilesToShow {
+					fmt.Fprintf(d.output, "    %s ...and %d more\n", errorColor("└─"), len(result.BadFiles)-maxFilesToShow)
+					break
+				}
+				prefix := "    ├─"
+				if i == len(result.BadFiles)-1 || i == maxFilesToShow-1 {
+					prefix = "    └─"
+				}
+				fmt.Fprintf(d.output, "    %s %s\n", errorColor(prefix), file)
+			}
+		}
+	}
+
+	if len(result.ExtraFiles) > 0 {
+		fmt.Fprintf(d.output, "  %-15s %s\n", label("Extra files:"), yellow(len(result.ExtraFiles)))
+		if d.formatter.verbose {
+			maxFilesToShow := 10
+			for i, file := range result.ExtraFiles {
+				if i >= maxFilesToShow {
+					fmt.Fprintf(d.output, "    %s ...and %d more\n", yellow("└─"), len(result.ExtraFiles)-maxFilesToShow)
+					break
+				}
+				prefix := "    ├─"
+				if i == len(result.ExtraFiles)-1 || i == maxFilesToShow-1 {
+					prefix = "    └─"
+				}
+				fmt.Fprintf(d.output, "    %s %s\n", yellow(prefix), file)
+			}
+		}
+	}
+
+	if len(result.ReadErrors) > 0 {
+		fmt.Fprintf(d.output, "  %-15s %s\n", label("Read errors:"), errorColor(len(result.ReadErrors)))
+		if d.formatter.verbose {
+			maxErrorsToShow := 10
+			for i, readErr := range result.ReadErrors {
+				if i >= maxErrorsToShow {
+					fmt.Fprintf(d.output, "    %s ...and %d more\n", errorColor("└─"), len(result.ReadErrors)-maxErrorsToShow)
+					break
+				}
+				prefix := "    ├─"
+				if i == len(result.ReadErrors)-1 || i == maxErrorsToShow-1 {
+					prefix = "    └─"
+				}
+				fmt.Fprintf(d.output, "    %s %s\n", errorColor(prefix), readErr)
+			}
+		}
+	}
+
 	fmt.Fprintf(d.output, "  %-15s %s\n", label("Check time:"), d.formatter.FormatDuration(duration))
 }
+
+// ShowMagnetInfo displays the details available from a magnet link
+func (d *Display) ShowMagnetInfo(m metainfo.MagnetV2) {
+	fmt.Fprintf(d.output, "\n%s\n", magenta("Magnet info:"))
+	if m.DisplayName != "" {
+		fmt.Fprintf(d.output, "  %-13s %s\n", label("Name:"), m.DisplayName)
+	}
+	if m.InfoHash.Ok {
+		fmt.Fprintf(d.output, "  %-13s %s\n", label("Hash:"), m.InfoHash.Value.HexString())
+	}
+	if m.V2InfoHash.Ok {
+		fmt.Fprintf(d.output, "  %-13s %s\n", label("Hash (v2):"), m.V2InfoHash.Value.HexString())
+	}
+
+	if len(m.Trackers) > 0 {
+		fmt.Fprintf(d.output, "  %s\n", label("Trackers:"))
+		for _, tracker := range m.Trackers {
+			fmt.Fprintf(d.output, "    %s\n", success(d.trackerURL(tracker)))
+		}
+	}
+
+	fmt.Fprintf(d.output, "  %s\n", yellow("File and piece details are not available from a magnet link"))
+}
+
+// ShowValidationResults displays the results of validating a torrent against tracker rules
+func (d *Display) ShowValidationResults(trackerURL string, results []ValidationResult) {
+	fmt.Fprintf(d.output, "%s %s\n", magenta("Tracker validation:"), d.trackerURL(trackerURL))
+	for _, r := range results {
+		var status string
+		switch r.Severity {
+		case SeverityFail:
+			status = errorColor("FAIL")
+		case SeverityWarn:
+			status = yellow("WARN")
+		default:
+			status = success("PASS")
+		}
+		fmt.Fprintf(d.output, "  [%s] %-26s %s\n", status, label(r.Check+":"), r.Message)
+	}
+	fmt.Fprintln(d.output)
+}
@@ -2,12 +2,14 @@ package torrent
 
 import (
 	"bytes"
+	"errors"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/anacrolix/torrent/bencode"
 	"github.com/anacrolix/torrent/metainfo"
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -395,3 +397,156 @@ func stripAnsiCodes(s string) string {
 	}
 	return s
 }
+
+func TestShowBatchVerificationResults(t *testing.T) {
+	var buf bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.output = &buf
+
+	results := []BatchVerifyResult{
+		{Name: "Complete.Release", Result: &VerificationResult{Completion: 100, TotalPieces: 4, GoodPieces: 4}},
+		{Name: "Broken.Release", Result: &VerificationResult{Completion: 50, TotalPieces: 4, GoodPieces: 2, BadPieces: 1, MissingFiles: []string{"a.mkv"}}},
+		{Name: "Gone.Release", Error: errors.New("content not found")},
+	}
+	display.ShowBatchVerificationResults(results, 0)
+
+	out := buf.String()
+	for _, want := range []string{"Total torrents:", "Complete.Release", "100.00%", "50.00%", "content not found"} {
+		assert.Contains(t, out, want)
+	}
+	assert.Regexp(t, `Broken\.Release\s+\S*50\.00%\S*\s+1\s+1`, out)
+}
+
+func TestShowBatchResults_Verbose(t *testing.T) {
+	var buf bytes.Buffer
+	display := NewDisplay(NewFormatter(true))
+	display.output = &buf
+
+	results := []BatchResult{
+		{
+			Success:  true,
+			Trackers: []string{"https://a.example/announce"},
+			Info: &TorrentInfo{
+				Path: "a.torrent", Source: "AAA", Private: true, PieceLength: 1 << 20,
+				MetaInfo: &metainfo.MetaInfo{AnnounceList: [][]string{{"https://a.example/announce", "https://a2.example/announce"}, {"https://b.example/announce"}}},
+			},
+		},
+		{
+			Success:  true,
+			Trackers: []string{"https://c.example/announce"},
+			Info:     &TorrentInfo{Path: "c.torrent", PieceLength: 1 << 16},
+		},
+	}
+	display.ShowBatchResults(results, 0)
+
+	out := buf.String()
+	assert.Contains(t, out, "https://a.example/announce, https://a2.example/announce | https://b.example/announce")
+	assert.Regexp(t, `Source:\S*\s+AAA`, out)
+	assert.Regexp(t, `Private:\S*\s+yes`, out)
+	assert.Regexp(t, `Piece size:\S*\s+1\.0 MiB`, out)
+	assert.Regexp(t, `Source:\S*\s+none`, out)
+	assert.Regexp(t, `Private:\S*\s+no`, out)
+	assert.Contains(t, out, "https://c.example/announce")
+}
+
+func TestDisplay_WarningsGoToErrorOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.SetQuiet(true)
+	display.output = &out // capture what quiet mode would discard
+	display.SetErrorOutput(&errOut)
+
+	display.ShowWarning("custom piece length differs")
+	display.ShowError("something failed")
+	display.ShowSeasonPackWarnings(&SeasonPackInfo{
+		IsSeasonPack:    true,
+		Season:          1,
+		Episodes:        []int{1, 2, 4},
+		MissingEpisodes: []int{3},
+		MaxEpisode:      4,
+	})
+
+	assert.Empty(t, out.String())
+	for _, want := range []string{"custom piece length differs", "something failed", "incomplete season pack", "episode 3"} {
+		assert.Contains(t, errOut.String(), want)
+	}
+}
+
+func TestDisplay_SetOutput(t *testing.T) {
+	var out bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.SetOutput(&out)
+
+	statuses := []TrackerStatus{{Tracker: "https://tracker.example.com/announce", Reachable: true, Status: "200 OK"}}
+	display.ShowTrackerStatuses(statuses)
+	assert.Contains(t, out.String(), "tracker.example.com")
+
+	out.Reset()
+	display.SetQuiet(true)
+	display.ShowMessage("hidden")
+	display.SetQuiet(false)
+	display.ShowMessage("shown again")
+	assert.NotContains(t, out.String(), "hidden")
+	assert.Contains(t, out.String(), "shown again")
+}
+
+func TestDisplay_ShowNote(t *testing.T) {
+	var errOut bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.SetErrorOutput(&errOut)
+
+	display.ShowNote("waiting for the content")
+	assert.Contains(t, errOut.String(), "waiting for the content")
+
+	errOut.Reset()
+	display.SetQuiet(true)
+	display.ShowNote("waiting for the content")
+	assert.Empty(t, errOut.String())
+}
+
+func TestDisplay_ShowLinkedOutput(t *testing.T) {
+	var out bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.output = &out
+
+	display.ShowLinkedOutput("/content/test.torrent", false)
+	assert.Contains(t, out.String(), "Linked: /content/test.torrent")
+
+	out.Reset()
+	display.ShowLinkedOutput("/content/test.torrent", true)
+	assert.Contains(t, out.String(), "Copied to /content/test.torrent")
+
+	display.SetQuiet(true)
+	display.ShowLinkedOutput("/content/test.torrent", false)
+	assert.NotContains(t, out.String(), "Linked:")
+}
+
+func TestShowTorrentInfo_Redact(t *testing.T) {
+	info := &metainfo.Info{Name: "test", PieceLength: 16384, Length: 100, Pieces: make([]byte, 20)}
+	tr, err := createTestTorrent(&metainfo.MetaInfo{Announce: "https://tracker.example.com/announce?passkey=secret"}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.output = &buf
+	display.SetRedact(true)
+	display.ShowTorrentInfo(tr, info)
+
+	output := buf.String()
+	assert.NotContains(t, output, "secret")
+	assert.Contains(t, output, "https://tracker.example.com/announce?passkey=***")
+}
+
+func TestBarMarkup(t *testing.T) {
+	prevNoColor := color.NoColor
+	defer func() { color.NoColor = prevNoColor }()
+
+	color.NoColor = false
+	assert.Equal(t, "[cyan][bold]Hashing pieces...[reset] [1 MiB/s]", barMarkup("[cyan][bold]Hashing pieces...[reset] [1 MiB/s]"))
+
+	color.NoColor = true
+	assert.Equal(t, "Hashing pieces... [1 MiB/s]", barMarkup("[cyan][bold]Hashing pieces...[reset] [1 MiB/s]"))
+	assert.Equal(t, "=", barMarkup("[green]=[reset]"))
+}
@@ -0,0 +1,98 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"time"
+)
+
+// IOProbe is the result of hashing a test file with one I/O mode, see ProbeIOMode
+type IOProbe struct {
+	Mode     IOMode
+	Elapsed  time.Duration
+	TimedOut bool  // the probe didn't finish within the timeout, the mode may hang on this machine
+	Err      error // nil if the file was hashed correctly
+}
+
+// OK reports whether the mode hashed the test file correctly in time
+func (p IOProbe) OK() bool {
+	return !p.TimedOut && p.Err == nil
+}
+
+// probeSize is the size of the test file, a few pieces with a partial last one
+const probeSize = 1<<20 + 123
+
+// ProbeIOMode writes a small test file into dir, hashes it with mode through the same
+// code path as CreateTorrent and checks the pieces against a direct hash of the data.
+// A probe still running after timeout is reported as TimedOut and cancelled; the
+// test file is removed once the hasher returns, which a hung read may never do.
+func ProbeIOMode(dir string, mode IOMode, timeout time.Duration) IOProbe {
+	probe := IOProbe{Mode: mode}
+
+	data := make([]byte, probeSize)
+	for i := range data {
+		data[i] = byte(i*7 + i/4096)
+	}
+	f, err := os.CreateTemp(dir, ".mkbrr-probe-*")
+	if err != nil {
+		probe.Err = fmt.Errorf("error creating test file: %w", err)
+		return probe
+	}
+	path := f.Name()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		probe.Err = fmt.Errorf("error writing test file: %w", err)
+		return probe
+	}
+
+	const pieceExp = 16
+	var want []byte
+	for off := 0; off < len(data); off += 1 << pieceExp {
+		sum := sha1.Sum(data[off:min(off+1<<pieceExp, len(data))])
+		want = append(want, sum[:]...)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		defer os.Remove(path)
+		exp := uint(pieceExp)
+		t, err := CreateTorrentContext(ctx, CreateOptions{
+			Path:           path,
+			PieceLengthExp: &exp,
+			IOMode:         mode,
+			NoDate:         true,
+			NoCreator:      true,
+			Quiet:          true,
+		})
+		if err == nil && !bytes.Equal(t.GetInfo().Pieces, want) {
+			err = fmt.Errorf("piece hashes don't match the data")
+		}
+		done <- err
+	}()
+
+	select {
+	case probe.Err = <-done:
+		probe.Elapsed = time.Since(start)
+	case <-time.After(timeout):
+		probe.TimedOut = true
+		probe.Elapsed = timeout
+	}
+	debugf("doctor: io mode %s in %s: ok %t, timed out %t, error %v", mode, dir, probe.OK(), probe.TimedOut, probe.Err)
+	return probe
+}
+
+// DetectStorage returns the storage type detected for path, and false if it
+// couldn't be detected and StorageSSD would be assumed
+func DetectStorage(path string) (StorageType, bool) {
+	return detectStorage(path)
+}
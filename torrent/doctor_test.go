@@ -0,0 +1,26 @@
+package torrent
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProbeIOMode(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, mode := range []IOMode{IOModeSync, IOModeMmap} {
+		probe := ProbeIOMode(dir, mode, time.Minute)
+		if !probe.OK() {
+			t.Errorf("ProbeIOMode(%s) = %+v, want ok", mode, probe)
+		}
+	}
+
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("test files left behind: %v (err %v)", entries, err)
+	}
+
+	if probe := ProbeIOMode(dir+"/missing", IOModeSync, time.Minute); probe.OK() || probe.Err == nil {
+		t.Errorf("ProbeIOMode() in a missing directory = %+v, want an error", probe)
+	}
+}
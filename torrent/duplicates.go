@@ -0,0 +1,172 @@
+package torrent
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// quickHashChunk is the number of bytes read from the start and end of a file for its quick hash
+const quickHashChunk = 64 << 10
+
+// DuplicateGroup is a set of files in a torrent that appear to contain the same data
+type DuplicateGroup struct {
+	Paths    []string // paths as found in the content directory
+	Size     int64    // size of each file
+	SameFile bool     // all paths are links to the same file on disk
+}
+
+// findDuplicateFiles groups files with identical sizes whose quick hashes match.
+// paths holds the walked path for each entry in files (before symlink resolution)
+// and is used for reporting. Empty files are ignored.
+func findDuplicateFiles(files []fileEntry, paths []string) ([]DuplicateGroup, error) {
+	bySize := make(map[int64][]int)
+	for i, f := range files {
+		if f.length > 0 {
+			bySize[f.length] = append(bySize[f.length], i)
+		}
+	}
+
+	var groups []DuplicateGroup
+	for size, indices := range bySize {
+		if len(indices) < 2 {
+			continue
+		}
+
+		byHash := make(map[string][]int)
+		var hashOrder []string
+		for _, i := range indices {
+			sum, err := quickHash(files[i].path, size)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := byHash[sum]; !ok {
+				hashOrder = append(hashOrder, sum)
+			}
+			byHash[sum] = append(byHash[sum], i)
+		}
+
+		for _, sum := range hashOrder {
+			matches := byHash[sum]
+			if len(matches) < 2 {
+				continue
+			}
+
+			group := DuplicateGroup{Size: size, SameFile: true}
+			first, err := os.Stat(files[matches[0]].path)
+			if err != nil {
+				return nil, fmt.Errorf("could not stat %q: %w", files[matches[0]].path, err)
+			}
+			for _, i := range matches {
+				group.Paths = append(group.Paths, paths[i])
+				if info, err := os.Stat(files[i].path); err != nil || !os.SameFile(first, info) {
+					group.SameFile = false
+				}
+			}
+			sort.Strings(group.Paths)
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Paths[0] < groups[j].Paths[0]
+	})
+
+	return groups, nil
+}
+
+// quickHash hashes the size and the first and last quickHashChunk bytes of a file
+func quickHash(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%d:", size)
+
+	if _, err := io.CopyN(h, f, min(size, quickHashChunk)); err != nil {
+		return "", fmt.Errorf("could not read %q: %w", path, err)
+	}
+
+	if size > quickHashChunk {
+		tail := min(size-quickHashChunk, quickHashChunk)
+		if _, err := f.Seek(size-tail, io.SeekStart); err != nil {
+			return "", fmt.Errorf("could not seek %q: %w", path, err)
+		}
+		if _, err := io.CopyN(h, f, tail); err != nil {
+			return "", fmt.Errorf("could not read %q: %w", path, err)
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// ShowDuplicateWarnings reports duplicate files as a warning, listing each group when
+// verbose. Like other warnings it goes to the error output, even in quiet mode.
+func (d *Display) ShowDuplicateWarnings(groups []DuplicateGroup, verbose bool) {
+	if len(groups) == 0 {
+		return
+	}
+
+	formatter := NewFormatter(verbose)
+	var wasted int64
+	for _, g := range groups {
+		wasted += g.Size * int64(len(g.Paths)-1)
+	}
+
+	fmt.Fprintf(d.errOutput, "%s found %d set(s) of duplicate files (%s duplicated)\n",
+		yellow("Warning:"), len(groups), formatter.FormatBytes(wasted))
+
+	if !verbose {
+		fmt.Fprintln(d.errOutput, "  use --verbose to list them")
+		return
+	}
+
+	for _, g := range groups {
+		kind := "identical content"
+		if g.SameFile {
+			kind = "same file via link"
+		}
+		fmt.Fprintf(d.errOutput, "  %s (%s, %s)\n", label(formatter.FormatBytes(g.Size)), kind, strings.Join(g.Paths, ", "))
+	}
+}
+
+// findCaseCollisions groups torrent paths that differ only by letter case, such as
+// "Readme.txt" and "README.txt". Such files overwrite each other when downloaded to a
+// case-insensitive filesystem (the default on macOS and Windows). Groups and the paths
+// within them are sorted.
+func findCaseCollisions(paths []string) [][]string {
+	byFolded := make(map[string][]string)
+	for _, p := range paths {
+		folded := strings.ToLower(p)
+		byFolded[folded] = append(byFolded[folded], p)
+	}
+
+	var groups [][]string
+	for _, group := range byFolded {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+// formatCaseCollisions renders collision groups for a warning or error message,
+// e.g. "Readme.txt and README.txt; a/b.txt and A/b.txt"
+func formatCaseCollisions(groups [][]string) string {
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = strings.Join(g[:len(g)-1], ", ") + " and " + g[len(g)-1]
+	}
+	return strings.Join(parts, "; ")
+}
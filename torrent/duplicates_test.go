@@ -0,0 +1,138 @@
+package torrent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFindDuplicateFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	large := bytes.Repeat([]byte("abcdefgh"), 3*quickHashChunk/8)
+	largeOther := bytes.Clone(large)
+	largeOther[len(largeOther)-1] = 'z' // same size, different tail
+
+	contents := map[string][]byte{
+		"a.mkv":       large,
+		"copy/a.mkv":  large,
+		"b.mkv":       largeOther,
+		"small.nfo":   []byte("same small file"),
+		"small2.nfo":  []byte("same small file"),
+		"unique.txt":  []byte("unique"),
+		"empty1.txt":  {},
+		"empty2.txt":  {},
+		"other.nfo":   []byte("diff small file"),
+		"another.mkv": bytes.Repeat([]byte("x"), 10),
+	}
+
+	var files []fileEntry
+	var paths []string
+	for name, data := range contents {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		files = append(files, fileEntry{path: path, length: int64(len(data))})
+		paths = append(paths, name)
+	}
+
+	groups, err := findDuplicateFiles(files, paths)
+	if err != nil {
+		t.Fatalf("findDuplicateFiles() error = %v", err)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+
+	want := [][]string{{"a.mkv", "copy/a.mkv"}, {"small.nfo", "small2.nfo"}}
+	for i, g := range groups {
+		if strings.Join(g.Paths, ",") != strings.Join(want[i], ",") {
+			t.Errorf("group %d paths = %v, want %v", i, g.Paths, want[i])
+		}
+		if g.SameFile {
+			t.Errorf("group %d reported as same file, want identical content", i)
+		}
+	}
+}
+
+func TestFindDuplicateFiles_Links(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("link creation requires extra privileges on windows")
+	}
+
+	tmpDir := t.TempDir()
+	target := filepath.Join(tmpDir, "episode.mkv")
+	if err := os.WriteFile(target, []byte("episode data"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	hardlink := filepath.Join(tmpDir, "episode.hardlink.mkv")
+	if err := os.Link(target, hardlink); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+
+	files := []fileEntry{
+		{path: target, length: 12},
+		{path: hardlink, length: 12},
+	}
+	groups, err := findDuplicateFiles(files, []string{"episode.mkv", "episode.hardlink.mkv"})
+	if err != nil {
+		t.Fatalf("findDuplicateFiles() error = %v", err)
+	}
+	if len(groups) != 1 || !groups[0].SameFile {
+		t.Fatalf("expected one same-file group, got %+v", groups)
+	}
+}
+
+func TestShowDuplicateWarnings(t *testing.T) {
+	groups := []DuplicateGroup{
+		{Paths: []string{"a.mkv", "copy/a.mkv"}, Size: 1 << 20},
+	}
+
+	var buf bytes.Buffer
+	display := NewDisplay(NewFormatter(false))
+	display.SetErrorOutput(&buf)
+	display.ShowDuplicateWarnings(groups, false)
+	if !strings.Contains(buf.String(), "1 set(s) of duplicate files") || strings.Contains(buf.String(), "copy/a.mkv") {
+		t.Errorf("unexpected non-verbose output: %q", buf.String())
+	}
+
+	buf.Reset()
+	display.ShowDuplicateWarnings(groups, true)
+	if !strings.Contains(buf.String(), "a.mkv, copy/a.mkv") {
+		t.Errorf("verbose output should list paths, got: %q", buf.String())
+	}
+
+	buf.Reset()
+	display.ShowDuplicateWarnings(nil, true)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without duplicates, got: %q", buf.String())
+	}
+}
+
+func TestFindCaseCollisions(t *testing.T) {
+	paths := []string{"Readme.txt", "README.txt", "other.txt", "Sub/a.mkv", "sub/a.mkv", "sub/A.mkv", "sub/b.mkv"}
+
+	got := findCaseCollisions(paths)
+	want := [][]string{{"README.txt", "Readme.txt"}, {"Sub/a.mkv", "sub/A.mkv", "sub/a.mkv"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findCaseCollisions() = %v, want %v", got, want)
+	}
+
+	msg := formatCaseCollisions(got)
+	if wantMsg := "README.txt and Readme.txt; Sub/a.mkv, sub/A.mkv and sub/a.mkv"; msg != wantMsg {
+		t.Errorf("formatCaseCollisions() = %q, want %q", msg, wantMsg)
+	}
+
+	if got := findCaseCollisions([]string{"a.txt", "b.txt"}); len(got) != 0 {
+		t.Errorf("expected no collisions, got %v", got)
+	}
+}
@@ -0,0 +1,63 @@
+package torrent
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+// Errors returned by Create, CreateTorrent, VerifyData and ModifyTorrent for common
+// failures. The returned errors keep their detailed message and wrap one of these,
+// so callers can branch with errors.Is instead of matching error strings.
+var (
+	// ErrPathNotFound is returned when the content path or torrent file does not exist
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTorrentTooLarge is returned when a torrent cannot be kept under the tracker's size limit
+	ErrTorrentTooLarge = errors.New("torrent exceeds tracker size limit")
+	// ErrPieceLengthOutOfRange is returned when a piece length option is outside the allowed range
+	ErrPieceLengthOutOfRange = errors.New("piece length out of range")
+	// ErrContentChanged is returned when a file got shorter while it was being hashed,
+	// or with CreateOptions.VerifyStable when a file's size or mtime changed
+	ErrContentChanged = errors.New("content changed during hashing")
+	// ErrCorruptTorrent is returned when a torrent file is malformed, see ValidatePieces
+	ErrCorruptTorrent = errors.New("corrupt torrent")
+	// ErrOutputExists is returned when the output file already exists and overwriting was not forced
+	ErrOutputExists = errors.New("file already exists")
+	// ErrInfoHashMismatch is returned when a torrent's info hash differs from the expected one
+	ErrInfoHashMismatch = errors.New("info hash mismatch")
+	// ErrCancelled is returned when the context passed to CreateContext, VerifyDataContext,
+	// ModifyTorrentContext or LoadFromURLContext is done. The error also matches the
+	// context's error, e.g. context.Canceled or context.DeadlineExceeded
+	ErrCancelled = errors.New("cancelled")
+)
+
+// kindError tags a detailed error with one of the errors above without changing its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string   { return e.err.Error() }
+func (e *kindError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// withKind wraps err so that errors.Is matches both kind and the errors err wraps
+func withKind(kind, err error) error {
+	return &kindError{kind: kind, err: err}
+}
+
+// ctxErr returns the error of a done ctx tagged with ErrCancelled, or nil while it is
+// live. A nil ctx is never done, so internal types built without one keep working.
+func ctxErr(ctx context.Context) error {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+	return withKind(ErrCancelled, ctx.Err())
+}
+
+// notFound tags err with ErrPathNotFound if it was caused by a missing file
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return withKind(ErrPathNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package torrent
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestErrors_Is(t *testing.T) {
+	tmpDir := t.TempDir()
+	missing := filepath.Join(tmpDir, "missing")
+
+	testFile := filepath.Join(tmpDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("structured errors"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := CreateTorrent(CreateOptions{Path: missing, Quiet: true})
+	if !errors.Is(err, ErrPathNotFound) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("CreateTorrent() with missing path error = %v, want ErrPathNotFound", err)
+	}
+
+	pieceLenExp := uint(30)
+	_, err = CreateTorrent(CreateOptions{Path: testFile, PieceLengthExp: &pieceLenExp, Quiet: true})
+	if !errors.Is(err, ErrPieceLengthOutOfRange) {
+		t.Errorf("CreateTorrent() with piece length 2^30 error = %v, want ErrPieceLengthOutOfRange", err)
+	}
+
+	_, err = VerifyData(VerifyOptions{TorrentPath: missing + ".torrent", ContentPath: tmpDir})
+	if !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("VerifyData() with missing torrent error = %v, want ErrPathNotFound", err)
+	}
+
+	_, err = ModifyTorrent(missing+".torrent", ModifyOptions{})
+	if !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("ModifyTorrent() with missing torrent error = %v, want ErrPathNotFound", err)
+	}
+
+	// the message is left as is
+	wrapped := withKind(ErrContentChanged, errors.New("short read"))
+	if wrapped.Error() != "short read" || !errors.Is(wrapped, ErrContentChanged) {
+		t.Errorf("withKind() = %q, want the original message and ErrContentChanged", wrapped)
+	}
+	if err := notFound(errors.New("other")); errors.Is(err, ErrPathNotFound) {
+		t.Errorf("notFound() tagged %q, want only missing files tagged", err)
+	}
+}
@@ -0,0 +1,114 @@
+package torrent
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ValidateExec checks that a post-create command can be split into arguments
+func ValidateExec(command string) error {
+	args, err := splitCommand(command)
+	if err != nil {
+		return fmt.Errorf("invalid exec command %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("invalid exec command %q: no command given", command)
+	}
+	return nil
+}
+
+// RunExec runs a command after a torrent was created. {path}, {infohash} and
+// {name} are substituted in each argument; the command is split into arguments
+// like a shell would but not run through one, so substituted values are never
+// interpreted. It returns the combined stdout and stderr, which is also part of
+// the error if the command fails.
+func RunExec(command string, info *TorrentInfo) ([]byte, error) {
+	if err := ValidateExec(command); err != nil {
+		return nil, err
+	}
+	args, _ := splitCommand(command)
+
+	var name string
+	if info.MetaInfo != nil {
+		if i, err := info.MetaInfo.UnmarshalInfo(); err == nil {
+			name = i.Name
+		}
+	}
+	replacer := strings.NewReplacer(
+		"{path}", info.Path,
+		"{infohash}", info.InfoHash,
+		"{name}", name,
+	)
+	for i := range args {
+		args[i] = replacer.Replace(args[i])
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return output.Bytes(), fmt.Errorf("exec %s: %w: %s", args[0], err, out)
+		}
+		return output.Bytes(), fmt.Errorf("exec %s: %w", args[0], err)
+	}
+	return output.Bytes(), nil
+}
+
+// splitCommand splits a command line into arguments. Single quotes keep their
+// content as is, double quotes allow \" and \\, and outside quotes a backslash
+// only escapes a quote, a backslash or a space so Windows paths pass unchanged.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"'\ `, runes[i+1]):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,70 @@
+package torrent
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "inject.sh {path} {infohash}", want: []string{"inject.sh", "{path}", "{infohash}"}},
+		{in: `  spaced   out  `, want: []string{"spaced", "out"}},
+		{in: `client add "My Label" '{path}'`, want: []string{"client", "add", "My Label", "{path}"}},
+		{in: `echo "a \"quoted\" word"`, want: []string{"echo", `a "quoted" word`}},
+		{in: `echo 'no \escapes'`, want: []string{"echo", `no \escapes`}},
+		{in: `C:\tools\inject.exe {path}`, want: []string{`C:\tools\inject.exe`, "{path}"}},
+		{in: `echo with\ space`, want: []string{"echo", "with space"}},
+		{in: `echo ""`, want: []string{"echo", ""}},
+		{in: `echo "unterminated`, wantErr: true},
+		{in: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := splitCommand(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitCommand(%q) = %q, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitCommand(%q) error = %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	info := &TorrentInfo{Path: "/out/My Show.torrent", InfoHash: "abc123"}
+
+	out, err := RunExec(`sh -c 'printf "%s|%s" "$0" "$1"' {path} {infohash}`, info)
+	if err != nil {
+		t.Fatalf("RunExec() error = %v", err)
+	}
+	if got, want := string(out), "/out/My Show.torrent|abc123"; got != want {
+		t.Errorf("RunExec() output = %q, want %q", got, want)
+	}
+
+	_, err = RunExec(`sh -c 'echo broken >&2; exit 3'`, info)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("RunExec() error = %v, want the failing command's output", err)
+	}
+
+	if err := ValidateExec("   "); err == nil {
+		t.Error("ValidateExec() of an empty command succeeded, want error")
+	}
+}
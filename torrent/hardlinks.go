@@ -0,0 +1,55 @@
+package torrent
+
+// fileKey identifies a file's data on disk, shared by all hardlinks to it
+type fileKey struct {
+	dev, ino uint64
+	size     int64
+}
+
+// hardlinkPieceSources finds pieces that lie entirely inside a hardlink of an earlier
+// file and maps each to the piece of that earlier file holding the same bytes, so it
+// only has to be hashed once. That requires both links to start at the same offset
+// relative to a piece boundary, which --padded guarantees; otherwise few or no pieces
+// can be reused. Files whose identity can't be read are hashed as usual.
+func hardlinkPieceSources(files []fileEntry, pieceLen int64, numPieces int, lastPieceLength int64) map[int]int {
+	first := make(map[fileKey]fileEntry)
+	sources := make(map[int]int)
+
+	for _, f := range files {
+		if f.padding || f.length < pieceLen {
+			continue
+		}
+		dev, ino, ok := fileID(f.path)
+		if !ok {
+			continue
+		}
+		key := fileKey{dev: dev, ino: ino, size: f.length}
+		src, seen := first[key]
+		if !seen {
+			first[key] = f
+			continue
+		}
+
+		shift := f.offset - src.offset
+		if shift%pieceLen != 0 {
+			continue
+		}
+		shiftPieces := int(shift / pieceLen)
+		for p := int((f.offset + pieceLen - 1) / pieceLen); p < numPieces; p++ {
+			length := pieceLen
+			if p == numPieces-1 {
+				length = lastPieceLength
+			}
+			// the source piece is a full one, so a short last piece never matches it
+			if length != pieceLen || int64(p)*pieceLen+length > f.offset+f.length {
+				break
+			}
+			sources[p] = p - shiftPieces
+		}
+	}
+
+	if len(sources) > 0 {
+		debugf("hardlinks: %d of %d pieces are reused from earlier hardlinks", len(sources), numPieces)
+	}
+	return sources
+}
@@ -0,0 +1,96 @@
+package torrent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCreateTorrent_DedupHardlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hardlinks are only detected on Unix-like systems")
+	}
+
+	const pieceExp = 16
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "Linked")
+	for _, dir := range []string{"movies", "tv"} {
+		if err := os.MkdirAll(filepath.Join(contentDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := make([]byte, 3<<pieceExp+1000)
+	for i := range data {
+		data[i] = byte(i*31 + i/7)
+	}
+	original := filepath.Join(contentDir, "movies", "film.mkv")
+	if err := os.WriteFile(original, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(original, filepath.Join(contentDir, "tv", "film.mkv")); err != nil {
+		t.Skipf("hardlinks not supported here: %v", err)
+	}
+
+	exp := uint(pieceExp)
+	create := func(dedup bool) *Torrent {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{
+			Path:           contentDir,
+			PieceLengthExp: &exp,
+			Padded:         true,
+			DedupHardlinks: dedup,
+			NoDate:         true,
+			Quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent() error = %v", err)
+		}
+		return mi
+	}
+
+	plain := create(false)
+	deduped := create(true)
+	if !bytes.Equal(deduped.GetInfo().Pieces, plain.GetInfo().Pieces) {
+		t.Error("pieces differ with DedupHardlinks")
+	}
+
+	files := []fileEntry{
+		{path: original, length: int64(len(data)), offset: 0},
+		{path: filepath.Join(contentDir, "tv", "film.mkv"), length: int64(len(data)), offset: 4 << pieceExp},
+	}
+	sources := hardlinkPieceSources(files, 1<<pieceExp, 8, 1000)
+	// pieces 4-6 are full pieces of the link, the short last piece 7 is hashed
+	want := map[int]int{4: 0, 5: 1, 6: 2}
+	if len(sources) != len(want) {
+		t.Errorf("hardlinkPieceSources() = %v, want %v", sources, want)
+	}
+	for piece, source := range want {
+		if sources[piece] != source {
+			t.Errorf("piece %d source = %d, want %d", piece, sources[piece], source)
+		}
+	}
+	files[1].offset++
+	if sources := hardlinkPieceSources(files, 1<<pieceExp, 8, 1001); len(sources) != 0 {
+		t.Errorf("hardlinkPieceSources() for unaligned links = %v, want none", sources)
+	}
+
+	torrentPath := filepath.Join(tmpDir, "linked.torrent")
+	f, err := os.Create(torrentPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deduped.Write(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	result, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, DedupHardlinks: true, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData() error = %v", err)
+	}
+	if result.GoodPieces != result.TotalPieces || result.BadPieces != 0 {
+		t.Errorf("VerifyData() = %d/%d good, %d bad, want all good", result.GoodPieces, result.TotalPieces, result.BadPieces)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package torrent
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the device and inode of the file at path
+func fileID(path string) (uint64, uint64, bool) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(st.Dev), uint64(st.Ino), true
+}
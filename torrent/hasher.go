@@ -1,8 +1,10 @@
 package torrent
 
 import (
+	"context"
 	"crypto/sha1"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"sync"
@@ -26,6 +28,13 @@ type pieceHasher struct {
 	startTime               time.Time
 	bytesProcessed          int64
 	failOnSeasonPackWarning bool
+	seasonPackOptions       SeasonPackOptions
+	ioMode                  IOMode
+	maxMemory               int64           // budget for read buffers across all workers, 0 for no limit
+	readRetries             int             // times a failed read is retried before giving up
+	storage                 StorageType     // resolved storage type, tunes optimizeForWorkload
+	pieceSources            map[int]int     // pieces copied from an earlier hardlink instead of hashed, see hardlinkPieceSources
+	ctx                     context.Context // stops the workers with ErrCancelled when done, see CreateContext
 }
 
 // optimizeForWorkload determines optimal read buffer size and number of worker goroutines
@@ -33,6 +42,7 @@ type pieceHasher struct {
 // - single vs multiple files
 // - average file size
 // - system CPU count
+// - storage type (a single worker for HDDs, a few for network mounts)
 // returns readSize (buffer size for reading) and numWorkers (concurrent goroutines)
 func (h *pieceHasher) optimizeForWorkload() (int, int) {
 	if len(h.files) == 0 {
@@ -75,6 +85,7 @@ func (h *pieceHasher) optimizeForWorkload() (int, int) {
 		readSize = 8 << 20 // 8 MiB
 		numWorkers = defaultWorkerCount(true)
 	}
+	readSize, numWorkers = tuneForStorage(h.storage, readSize, numWorkers)
 
 	// ensure we don't create more workers than pieces to process
 	if numWorkers > h.numPieces {
@@ -85,7 +96,8 @@ func (h *pieceHasher) optimizeForWorkload() (int, int) {
 
 // hashPieces coordinates the parallel hashing of all pieces in the torrent.
 // It initializes a buffer pool, creates worker goroutines, and manages progress tracking.
-// The pieces are distributed evenly across the specified number of workers.
+// Workers claim small chunks of pieces from a shared pieceQueue, so a worker that hits
+// slow pieces (e.g. many tiny files) doesn't leave the others idle at the end.
 // Returns an error if any worker encounters issues during hashing.
 func (h *pieceHasher) hashPieces(numWorkers int) error {
 	// Determine readSize and numWorkers. Use optimizeForWorkload if numWorkers isn't specified.
@@ -109,6 +121,17 @@ func (h *pieceHasher) hashPieces(numWorkers int) error {
 		numWorkers = 1
 	}
 
+	if h.maxMemory > 0 {
+		readSize, workers := h.readSize, numWorkers
+		h.readSize, numWorkers = fitMemoryBudget(h.readSize, numWorkers, h.maxMemory)
+		if h.readSize != readSize || numWorkers != workers {
+			debugf("hash: max memory %d bytes lowers %d workers with %d byte reads to %d workers with %d byte reads",
+				h.maxMemory, workers, readSize, numWorkers, h.readSize)
+		}
+	}
+	debugf("hash: %d pieces of %d bytes from %d files, %d workers, %d byte reads, io mode %s, storage %s",
+		h.numPieces, h.pieceLen, len(h.files), numWorkers, h.readSize, h.ioMode, h.storage)
+
 	if numWorkers == 0 {
 		// no workers needed, possibly no pieces to hash
 		h.display.ShowProgress(0)
@@ -127,38 +150,35 @@ func (h *pieceHasher) hashPieces(numWorkers int) error {
 	h.startTime = time.Now()
 	h.bytesProcessed = 0
 
-	h.display.ShowFiles(h.files, numWorkers)
+	contentFiles := withoutPadding(h.files)
+	h.display.ShowFiles(contentFiles, numWorkers)
 
-	seasonInfo := AnalyzeSeasonPack(h.files)
+	seasonInfo := AnalyzeSeasonPackWithOptions(contentFiles, h.seasonPackOptions)
 
 	h.display.ShowSeasonPackWarnings(seasonInfo)
 
 	if seasonInfo.IsSuspicious && h.failOnSeasonPackWarning {
-		return fmt.Errorf("season pack is suspicious, and --fail-on-season-warning is enabled")
+		return fmt.Errorf("incomplete season pack: season %d is missing episodes %s (fail on season warning is enabled)",
+			seasonInfo.Season, formatEpisodeList(seasonInfo.MissingEpisodes))
 	}
 
 	var completedPieces uint64
-	piecesPerWorker := (h.numPieces + numWorkers - 1) / numWorkers
+	queue := newPieceQueue(h.numPieces, numWorkers)
 	errorsCh := make(chan error, numWorkers)
 
 	h.display.ShowProgress(h.numPieces)
 
-	// spawn worker goroutines to process piece ranges in parallel
+	// spawn worker goroutines that pull pieces from the queue in parallel
 	var wg sync.WaitGroup
 	for i := 0; i < numWorkers; i++ {
-		start := i * piecesPerWorker
-		end := start + piecesPerWorker
-		if end > h.numPieces {
-			end = h.numPieces
-		}
-
 		wg.Add(1)
-		go func(startPiece, endPiece int) {
+		go func() {
 			defer wg.Done()
-			if err := h.hashPieceRange(startPiece, endPiece, &completedPieces); err != nil {
+			if err := h.hashWorker(queue, &completedPieces); err != nil {
+				queue.stop()
 				errorsCh <- err
 			}
-		}(start, end)
+		}()
 	}
 
 	// monitor and update progress bar in separate goroutine
@@ -202,37 +222,123 @@ func (h *pieceHasher) hashPieces(numWorkers int) error {
 		}
 	}
 
+	for piece, source := range h.pieceSources {
+		copy(h.pieces[piece], h.pieces[source])
+	}
+
 	h.display.FinishProgress()
 	return nil
 }
 
-// hashPieceRange processes and hashes a specific range of pieces assigned to a worker.
+// minBudgetReadSize is the smallest read buffer fitMemoryBudget will shrink to
+const minBudgetReadSize = 64 << 10
+
+// fitMemoryBudget constrains readSize * numWorkers to maxMemory bytes.
+// Buffers are halved first (down to 64 KiB), since smaller reads cost less
+// throughput than fewer workers; only then is the worker count reduced.
+// At least one worker with a 64 KiB buffer is always kept.
+func fitMemoryBudget(readSize, numWorkers int, maxMemory int64) (int, int) {
+	if numWorkers <= 0 {
+		return readSize, numWorkers
+	}
+
+	for readSize > minBudgetReadSize && int64(readSize)*int64(numWorkers) > maxMemory {
+		readSize = max(readSize/2, minBudgetReadSize)
+	}
+
+	if int64(readSize)*int64(numWorkers) > maxMemory {
+		numWorkers = max(int(maxMemory/int64(readSize)), 1)
+	}
+
+	return readSize, numWorkers
+}
+
+// maxPieceChunk caps how many pieces a worker claims from a pieceQueue at once
+const maxPieceChunk = 64
+
+// pieceQueue hands out consecutive chunks of piece indices to workers.
+// Chunks are claimed in ascending order, so each worker still reads forward
+// through the files, while faster workers simply claim more chunks.
+type pieceQueue struct {
+	next      atomic.Int64
+	numPieces int64
+	chunk     int64
+}
+
+// newPieceQueue sizes chunks so each worker claims about 16 of them,
+// between 1 and maxPieceChunk pieces each
+func newPieceQueue(numPieces, numWorkers int) *pieceQueue {
+	chunk := numPieces / (max(numWorkers, 1) * 16)
+	return &pieceQueue{
+		numPieces: int64(numPieces),
+		chunk:     int64(min(max(chunk, 1), maxPieceChunk)),
+	}
+}
+
+// claim returns the next chunk of pieces [start, end), or false when all are claimed
+func (q *pieceQueue) claim() (int, int, bool) {
+	end := q.next.Add(q.chunk)
+	start := end - q.chunk
+	if start >= q.numPieces {
+		return 0, 0, false
+	}
+	return int(start), int(min(end, q.numPieces)), true
+}
+
+// stop makes further claims fail, so other workers finish early after an error
+func (q *pieceQueue) stop() {
+	q.next.Store(q.numPieces)
+}
+
+// hashWorker hashes chunks claimed from queue until none are left, keeping its
+// buffer and open files across chunks
+func (h *pieceHasher) hashWorker(queue *pieceQueue, completedPieces *uint64) error {
+	// reuse buffer from pool to minimize allocations
+	buf := h.bufferPool.Get().([]byte)
+	defer h.bufferPool.Put(buf)
+
+	hasher := sha1.New()
+	readers := newFileReaderCache(h.files)
+	readers.mmap = h.ioMode == IOModeMmap
+	defer readers.closeAll()
+
+	for {
+		if err := ctxErr(h.ctx); err != nil {
+			return err
+		}
+		start, end, ok := queue.claim()
+		if !ok {
+			return nil
+		}
+		if err := h.hashPieceRange(start, end, buf, hasher, readers, completedPieces); err != nil {
+			return err
+		}
+	}
+}
+
+// hashPieceRange processes and hashes a specific range of pieces claimed by a worker.
 // It handles:
 // - reading from multiple files that may span piece boundaries
-// - maintaining file positions and readers
+// - keeping file readers open and retrying failed reads
 // - calculating SHA1 hashes for each piece
 // - updating progress through the completedPieces counter
 // Parameters:
 //
 //	startPiece: first piece index to process
 //	endPiece: last piece index to process (exclusive)
+//	buf, hasher, readers: the worker's read buffer, SHA1 state and open files
 //	completedPieces: atomic counter for progress tracking
-func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, completedPieces *uint64) error {
-	// reuse buffer from pool to minimize allocations
-	buf := h.bufferPool.Get().([]byte)
-	defer h.bufferPool.Put(buf)
-
-	hasher := sha1.New()
-	readers := make([]*fileReader, len(h.files))
-	defer func() {
-		for _, reader := range readers {
-			if reader != nil {
-				_ = reader.file.Close()
-			}
+func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, buf []byte, hasher hash.Hash, readers *fileReaderCache, completedPieces *uint64) error {
+	for pieceIndex := startPiece; pieceIndex < endPiece; pieceIndex++ {
+		if err := ctxErr(h.ctx); err != nil {
+			return err
+		}
+		if _, ok := h.pieceSources[pieceIndex]; ok {
+			// filled in from its source once all workers are done
+			atomic.AddUint64(completedPieces, 1)
+			continue
 		}
-	}()
 
-	for pieceIndex := startPiece; pieceIndex < endPiece; pieceIndex++ {
 		pieceOffset := int64(pieceIndex) * h.pieceLen
 		pieceReadOffset := pieceOffset
 		pieceLength := h.pieceLengthFor(pieceIndex)
@@ -241,6 +347,7 @@ func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, completedPieces *
 		bytesHashed := int64(0)
 
 		startFile := h.startFileForPiece(pieceIndex)
+		readers.releaseBefore(startFile)
 		for fileIndex := startFile; fileIndex < len(h.files) && remainingPiece > 0; fileIndex++ {
 			file := h.files[fileIndex]
 			if pieceReadOffset >= file.offset+file.length {
@@ -253,25 +360,38 @@ func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, completedPieces *
 				continue
 			}
 
-			reader := readers[fileIndex]
-			if reader == nil {
-				f, err := os.Open(file.path)
-				if err != nil {
-					return fmt.Errorf("failed to open file %s: %w", file.path, err)
-				}
-				reader = &fileReader{
-					file:     f,
-					position: 0,
-					length:   file.length,
-				}
-				readers[fileIndex] = reader
+			// padding files are not on disk and consist of zeros
+			if file.padding {
+				hashZeros(hasher, buf, readLength)
+				remainingPiece -= readLength
+				pieceReadOffset += readLength
+				bytesHashed += readLength
+				continue
+			}
+
+			reader, err := readers.get(fileIndex)
+			if err != nil {
+				return err
 			}
 
-			if reader.position != readStart {
-				if _, err := reader.file.Seek(readStart, io.SeekStart); err != nil {
-					return fmt.Errorf("failed to seek in file %s: %w", file.path, err)
+			// mapped files are read directly from memory without per-read syscalls
+			if reader.mapped != nil {
+				for pos := readStart; pos < readStart+readLength; {
+					n := min(readStart+readLength-pos, int64(len(buf)))
+					read, err := reader.mapped.ReadAt(buf[:n], pos)
+					if err != nil && err != io.EOF {
+						return fmt.Errorf("failed to read file %s: %w", file.path, err)
+					}
+					if read == 0 {
+						return withKind(ErrContentChanged, fmt.Errorf("short read while hashing file %s", file.path))
+					}
+					hasher.Write(buf[:read])
+					pos += int64(read)
 				}
-				reader.position = readStart
+				remainingPiece -= readLength
+				pieceReadOffset += readLength
+				bytesHashed += readLength
+				continue
 			}
 
 			remaining := readLength
@@ -281,25 +401,24 @@ func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, completedPieces *
 					n = len(buf)
 				}
 
-				read, err := io.ReadFull(reader.file, buf[:n])
-				if err != nil && err != io.ErrUnexpectedEOF {
-					return fmt.Errorf("failed to read file %s: %w", file.path, err)
+				read, err := readAtWithRetry(reader.file, file.path, buf[:n], readStart+readLength-remaining, h.readRetries)
+				if err != nil && err != io.EOF {
+					return err
 				}
 				if read == 0 {
-					return fmt.Errorf("short read while hashing file %s", file.path)
+					return withKind(ErrContentChanged, fmt.Errorf("short read while hashing file %s", file.path))
 				}
 
 				hasher.Write(buf[:read])
 				remaining -= int64(read)
 				remainingPiece -= int64(read)
 				pieceReadOffset += int64(read)
-				reader.position += int64(read)
 				bytesHashed += int64(read)
 			}
 		}
 
 		if remainingPiece != 0 {
-			return fmt.Errorf("failed to hash piece %d completely: %d bytes remaining", pieceIndex, remainingPiece)
+			return withKind(ErrContentChanged, fmt.Errorf("failed to hash piece %d completely: %d bytes remaining", pieceIndex, remainingPiece))
 		}
 
 		if bytesHashed > 0 {
@@ -313,6 +432,69 @@ func (h *pieceHasher) hashPieceRange(startPiece, endPiece int, completedPieces *
 	return nil
 }
 
+// fileReaderCache keeps a worker's file handles open across pieces, so files spanning
+// many pieces are opened once. Workers claim pieces in ascending order, so files before
+// the current piece are never read again and are closed to keep open handles bounded.
+// With mmap set, files are mapped instead, so only the files a worker is currently on
+// are mapped rather than the whole content.
+type fileReaderCache struct {
+	files   []fileEntry
+	readers []*fileReader
+	first   int // lowest index that may still hold an open reader
+	open    int
+	mmap    bool // map files, falling back to regular reads where that fails, see IOModeMmap
+}
+
+func newFileReaderCache(files []fileEntry) *fileReaderCache {
+	return &fileReaderCache{
+		files:   files,
+		readers: make([]*fileReader, len(files)),
+	}
+}
+
+// get returns the reader for a file, opening it on first use
+func (c *fileReaderCache) get(index int) (*fileReader, error) {
+	if reader := c.readers[index]; reader != nil {
+		return reader, nil
+	}
+
+	file := c.files[index]
+	reader := &fileReader{length: file.length}
+	if c.mmap {
+		reader.mapped = mapFile(file)
+	}
+	if reader.mapped == nil {
+		f, err := os.Open(file.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file %s: %w", file.path, err)
+		}
+		reader.file = f
+	}
+	c.readers[index] = reader
+	c.open++
+	return reader, nil
+}
+
+// releaseBefore closes readers for all files before index
+func (c *fileReaderCache) releaseBefore(index int) {
+	for ; c.first < index && c.first < len(c.readers); c.first++ {
+		if reader := c.readers[c.first]; reader != nil {
+			if reader.mapped != nil {
+				_ = reader.mapped.Close()
+			} else {
+				_ = reader.file.Close()
+			}
+			c.readers[c.first] = nil
+			c.open--
+		}
+	}
+}
+
+// closeAll closes all remaining readers
+func (c *fileReaderCache) closeAll() {
+	c.releaseBefore(len(c.readers))
+}
+
 func (h *pieceHasher) pieceLengthFor(pieceIndex int) int64 {
 	if pieceIndex == h.numPieces-1 {
 		return h.lastPieceLength
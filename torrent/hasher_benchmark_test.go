@@ -8,14 +8,53 @@ import (
 )
 
 func BenchmarkPieceHasherSingleFile(b *testing.B) {
-	benchmarkPieceHasher(b, "single-file", 1, 256<<20, 1<<20)
+	benchmarkPieceHasher(b, "single-file", IOModeSync, 1, 256<<20, 1<<20)
 }
 
 func BenchmarkPieceHasherSeasonPack(b *testing.B) {
-	benchmarkPieceHasher(b, "season-pack", 8, 128<<20, 1<<20)
+	benchmarkPieceHasher(b, "season-pack", IOModeSync, 8, 128<<20, 1<<20)
 }
 
-func benchmarkPieceHasher(b *testing.B, name string, numFiles int, fileSize, pieceLen int64) {
+func BenchmarkPieceHasherSingleFileMmap(b *testing.B) {
+	benchmarkPieceHasher(b, "single-file", IOModeMmap, 1, 256<<20, 1<<20)
+}
+
+func BenchmarkPieceHasherSeasonPackMmap(b *testing.B) {
+	benchmarkPieceHasher(b, "season-pack", IOModeMmap, 8, 128<<20, 1<<20)
+}
+
+// BenchmarkPieceHasherSkewed hashes 2048 small files followed by one large file.
+// Pieces over the small files cost far more (an open per file) than pieces of the
+// large file, which left most workers idle when pieces were split evenly up front.
+func BenchmarkPieceHasherSkewed(b *testing.B) {
+	pieceLen := int64(256 << 10)
+	fileSizes := make([]int64, 2049)
+	for i := range fileSizes[:2048] {
+		fileSizes[i] = 16 << 10
+	}
+	fileSizes[2048] = 96 << 20
+	files, expectedHashes := createTestFilesWithPattern(b, b.TempDir(), fileSizes, pieceLen)
+
+	var totalSize int64
+	for _, size := range fileSizes {
+		totalSize += size
+	}
+
+	b.Run("skewed", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(totalSize)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			hasher := NewPieceHasher(files, pieceLen, len(expectedHashes), &mockDisplay{}, false)
+			if err := hasher.hashPieces(0); err != nil {
+				b.Fatalf("hashPieces failed: %v", err)
+			}
+		}
+	})
+}
+
+func benchmarkPieceHasher(b *testing.B, name string, ioMode IOMode, numFiles int, fileSize, pieceLen int64) {
 	b.Helper()
 
 	files := createBenchmarkFiles(b, numFiles, fileSize, pieceLen)
@@ -29,6 +68,7 @@ func benchmarkPieceHasher(b *testing.B, name string, numFiles int, fileSize, pie
 
 		for i := 0; i < b.N; i++ {
 			hasher := NewPieceHasher(files, pieceLen, numPieces, &mockDisplay{}, false)
+			hasher.ioMode = ioMode
 			if err := hasher.hashPieces(0); err != nil {
 				b.Fatalf("hashPieces failed: %v", err)
 			}
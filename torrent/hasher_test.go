@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/autobrr/mkbrr/internal/trackers"
@@ -297,6 +298,149 @@ func TestNewPieceHasher_PreallocatesPieceHashStorage(t *testing.T) {
 	}
 }
 
+func TestFitMemoryBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		readSize    int
+		numWorkers  int
+		maxMemory   int64
+		wantRead    int
+		wantWorkers int
+	}{
+		{name: "within budget", readSize: 8 << 20, numWorkers: 4, maxMemory: 64 << 20, wantRead: 8 << 20, wantWorkers: 4},
+		{name: "shrinks buffers first", readSize: 8 << 20, numWorkers: 128, maxMemory: 256 << 20, wantRead: 2 << 20, wantWorkers: 128},
+		{name: "then reduces workers", readSize: 8 << 20, numWorkers: 128, maxMemory: 1 << 20, wantRead: 64 << 10, wantWorkers: 16},
+		{name: "keeps one worker", readSize: 4 << 20, numWorkers: 8, maxMemory: 1 << 10, wantRead: 64 << 10, wantWorkers: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRead, gotWorkers := fitMemoryBudget(tt.readSize, tt.numWorkers, tt.maxMemory)
+			if gotRead != tt.wantRead || gotWorkers != tt.wantWorkers {
+				t.Errorf("fitMemoryBudget() = (%d, %d), want (%d, %d)", gotRead, gotWorkers, tt.wantRead, tt.wantWorkers)
+			}
+		})
+	}
+}
+
+func TestPieceQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		numPieces  int
+		numWorkers int
+		wantChunk  int64
+	}{
+		{name: "few pieces", numPieces: 10, numWorkers: 4, wantChunk: 1},
+		{name: "sized per worker", numPieces: 6400, numWorkers: 10, wantChunk: 40},
+		{name: "capped", numPieces: 1 << 20, numWorkers: 2, wantChunk: maxPieceChunk},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newPieceQueue(tt.numPieces, tt.numWorkers)
+			if queue.chunk != tt.wantChunk {
+				t.Errorf("chunk = %d, want %d", queue.chunk, tt.wantChunk)
+			}
+
+			// concurrent workers must claim every piece exactly once
+			claimed := make([]int32, tt.numPieces)
+			var wg sync.WaitGroup
+			for i := 0; i < tt.numWorkers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					lastEnd := 0
+					for {
+						start, end, ok := queue.claim()
+						if !ok {
+							return
+						}
+						if start < lastEnd || start >= end {
+							t.Errorf("claim() = [%d, %d) after %d, want ascending non-empty chunks", start, end, lastEnd)
+						}
+						lastEnd = end
+						for p := start; p < end; p++ {
+							atomic.AddInt32(&claimed[p], 1)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			for p, n := range claimed {
+				if n != 1 {
+					t.Fatalf("piece %d claimed %d times, want 1", p, n)
+				}
+			}
+		})
+	}
+
+	queue := newPieceQueue(100, 2)
+	queue.stop()
+	if _, _, ok := queue.claim(); ok {
+		t.Error("claim() after stop() should fail")
+	}
+}
+
+func TestPieceHasher_MaxMemory(t *testing.T) {
+	pieceLen := int64(1 << 18)
+	files, expectedHashes := createTestFilesWithPattern(t, t.TempDir(), []int64{3 << 20, 1 << 20}, pieceLen)
+
+	hasher := NewPieceHasher(files, pieceLen, len(expectedHashes), &mockDisplay{}, false)
+	hasher.maxMemory = 128 << 10
+	if err := hasher.hashPieces(8); err != nil {
+		t.Fatalf("hashPieces failed: %v", err)
+	}
+	if hasher.readSize != minBudgetReadSize {
+		t.Errorf("readSize = %d, want %d under a 128 KiB budget", hasher.readSize, minBudgetReadSize)
+	}
+	verifyHashes(t, hasher.pieces, expectedHashes)
+}
+
+func TestPieceHasher_ManySmallFilesBoundsOpenHandles(t *testing.T) {
+	const numFiles = 300
+	pieceLen := int64(4096)
+	fileSizes := make([]int64, numFiles)
+	for i := range fileSizes {
+		fileSizes[i] = 1000 + int64(i%7)*10 // several files per piece, pieces span files
+	}
+	files, expectedHashes := createTestFilesWithPattern(t, t.TempDir(), fileSizes, pieceLen)
+	numPieces := len(expectedHashes)
+
+	hasher := NewPieceHasher(files, pieceLen, numPieces, &mockDisplay{}, false)
+
+	// walk the pieces the way a single worker does and check that files
+	// from earlier pieces are closed once the worker has moved past them
+	readers := newFileReaderCache(files)
+	maxOpen := 0
+	for pieceIndex := 0; pieceIndex < numPieces; pieceIndex++ {
+		startFile := hasher.startFileForPiece(pieceIndex)
+		readers.releaseBefore(startFile)
+
+		pieceEnd := int64(pieceIndex)*pieceLen + hasher.pieceLengthFor(pieceIndex)
+		for i := startFile; i < len(files) && files[i].offset < pieceEnd; i++ {
+			if _, err := readers.get(i); err != nil {
+				t.Fatalf("get(%d) error = %v", i, err)
+			}
+		}
+		maxOpen = max(maxOpen, readers.open)
+	}
+	readers.closeAll()
+
+	// a 4 KiB piece touches at most 6 files of ~1 KB
+	if maxOpen > 6 {
+		t.Errorf("worker held up to %d open files, want at most 6", maxOpen)
+	}
+	if readers.open != 0 {
+		t.Errorf("%d files left open after closeAll", readers.open)
+	}
+
+	if err := hasher.hashPieces(1); err != nil {
+		t.Fatalf("hashPieces failed: %v", err)
+	}
+	verifyHashes(t, hasher.pieces, expectedHashes)
+}
+
 // TestPieceHasher_EdgeCases tests various edge cases and error conditions
 func TestPieceHasher_EdgeCases(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "hasher_test_edge")
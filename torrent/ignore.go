@@ -1,7 +1,9 @@
 package torrent
 
 import (
+	"fmt"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -132,6 +134,29 @@ func matchPattern(pattern, relPath string, isDir bool) (bool, error) {
 	return false, nil
 }
 
+// ExtensionPatterns turns file extensions such as "nfo", ".SFV" or "mkv,mp4"
+// into glob patterns for ExcludePatterns or IncludePatterns. Leading dots are
+// dropped and extensions are lowercased; matching is case-insensitive anyway.
+func ExtensionPatterns(extensions []string) ([]string, error) {
+	var patterns []string
+	for _, group := range extensions {
+		for _, ext := range strings.Split(group, ",") {
+			ext = strings.ToLower(strings.TrimLeft(strings.TrimSpace(ext), "."))
+			if ext == "" {
+				continue
+			}
+			if strings.ContainsAny(ext, "/\\*?[]{}") {
+				return nil, fmt.Errorf("invalid extension %q: must not contain path separators or glob characters", ext)
+			}
+			pattern := "*." + ext
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns, nil
+}
+
 // shouldIgnoreEntry checks if a file or directory should be ignored based on
 // predefined patterns, user-defined include patterns, and user-defined exclude patterns.
 // It uses doublestar for full glob support including ** recursive matching.
@@ -233,6 +258,80 @@ func shouldIgnoreEntry(relPath string, isDir bool, excludePatterns []string, inc
 	return false, nil
 }
 
+// pathFilter combines the glob patterns with the regular expressions of
+// CreateOptions.ExcludeRegex and IncludeRegex, compiled once before the walk.
+type pathFilter struct {
+	excludePatterns []string
+	includePatterns []string
+	excludeRegex    []*regexp.Regexp
+	includeRegex    []*regexp.Regexp
+}
+
+// newPathFilter compiles the regular expressions, failing on the first invalid one
+func newPathFilter(excludePatterns, includePatterns, excludeRegex, includeRegex []string) (*pathFilter, error) {
+	f := &pathFilter{excludePatterns: excludePatterns, includePatterns: includePatterns}
+	for _, expr := range excludeRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude regex %q: %w", expr, err)
+		}
+		f.excludeRegex = append(f.excludeRegex, re)
+	}
+	for _, expr := range includeRegex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include regex %q: %w", expr, err)
+		}
+		f.includeRegex = append(f.includeRegex, re)
+	}
+	return f, nil
+}
+
+// ignores reports whether the entry at relPath should be left out of the torrent.
+// It follows shouldIgnoreEntry, with a regex taking the place of a glob: a file
+// kept by any include glob or regex is always kept, and excludes only apply when
+// there are no includes. Regexes are matched unanchored against the forward-slash
+// path, with a trailing slash for directories so "(^|/)sample/" prunes a whole folder.
+func (f *pathFilter) ignores(relPath string, isDir bool) (bool, error) {
+	if len(f.includeRegex) == 0 {
+		ignore, err := shouldIgnoreEntry(relPath, isDir, f.excludePatterns, f.includePatterns)
+		if err != nil || ignore || len(f.includePatterns) > 0 {
+			return ignore, err
+		}
+		return matchesAnyRegex(f.excludeRegex, relPath, isDir), nil
+	}
+
+	// built-in ignores still apply, and directories are traversed to find included files
+	ignore, err := shouldIgnoreEntry(relPath, isDir, nil, nil)
+	if err != nil || ignore || isDir {
+		return ignore, err
+	}
+	if matchesAnyRegex(f.includeRegex, relPath, false) {
+		return false, nil
+	}
+	if len(f.includePatterns) > 0 {
+		return shouldIgnoreEntry(relPath, false, nil, f.includePatterns)
+	}
+	return true, nil
+}
+
+// matchesAnyRegex reports whether relPath matches one of the expressions, see pathFilter.ignores
+func matchesAnyRegex(exprs []*regexp.Regexp, relPath string, isDir bool) bool {
+	if relPath == "" || relPath == "." {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	if isDir {
+		relPath += "/"
+	}
+	for _, re := range exprs {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
 // shouldIgnoreFile checks if a file should be ignored based on predefined patterns,
 // user-defined include patterns, and user-defined exclude patterns (glob matching).
 // This is a wrapper around shouldIgnoreEntry for backward compatibility.
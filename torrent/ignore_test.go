@@ -1,6 +1,7 @@
 package torrent
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -91,6 +92,42 @@ func TestSplitPatterns(t *testing.T) {
 	}
 }
 
+func TestExtensionPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr bool
+	}{
+		{"plain", []string{"nfo", "sfv"}, []string{"*.nfo", "*.sfv"}, false},
+		{"leading dots and case", []string{".NFO", "..Txt"}, []string{"*.nfo", "*.txt"}, false},
+		{"comma separated", []string{"mkv, mp4"}, []string{"*.mkv", "*.mp4"}, false},
+		{"duplicates and empties", []string{"nfo,,NFO", " "}, []string{"*.nfo"}, false},
+		{"multi-part extension", []string{"tar.gz"}, []string{"*.tar.gz"}, false},
+		{"glob rejected", []string{"n*o"}, nil, true},
+		{"path rejected", []string{"sub/nfo"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtensionPatterns(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtensionPatterns(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExtensionPatterns(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	// the generated patterns match regardless of case or depth
+	patterns, _ := ExtensionPatterns([]string{"nfo"})
+	ignored, err := shouldIgnoreEntry("Sub/Release.NFO", false, patterns, nil)
+	if err != nil || !ignored {
+		t.Errorf("shouldIgnoreEntry with %v = %v, %v; want ignored", patterns, ignored, err)
+	}
+}
+
 // TestMatchPattern tests the matchPattern function which matches glob patterns
 // against paths using doublestar with case-insensitivity and directory handling.
 func TestMatchPattern(t *testing.T) {
@@ -551,6 +588,58 @@ func TestShouldIgnoreEntry(t *testing.T) {
 	}
 }
 
+// TestPathFilter tests regex excludes and includes and how they combine with globs.
+func TestPathFilter(t *testing.T) {
+	tests := []struct {
+		name            string
+		excludePatterns []string
+		includePatterns []string
+		excludeRegex    []string
+		includeRegex    []string
+		relPath         string
+		isDir           bool
+		want            bool
+	}{
+		{name: "no filters", relPath: "Movie/movie.mkv", want: false},
+		{name: "exclude regex file", excludeRegex: []string{"(^|/)sample/"}, relPath: "Movie/sample/sample.mkv", want: true},
+		{name: "exclude regex top level dir", excludeRegex: []string{"(^|/)sample/"}, relPath: "sample", isDir: true, want: true},
+		{name: "exclude regex nested dir", excludeRegex: []string{"(^|/)sample/"}, relPath: "Movie/Sample", isDir: true, want: false},
+		{name: "exclude regex case insensitive flag", excludeRegex: []string{"(?i)(^|/)sample/"}, relPath: "Movie/Sample", isDir: true, want: true},
+		{name: "exclude regex no match", excludeRegex: []string{`\.nfo$`}, relPath: "Movie/movie.mkv", want: false},
+		{name: "exclude glob still applies", excludePatterns: []string{"*.nfo"}, excludeRegex: []string{"sample"}, relPath: "movie.nfo", want: true},
+		{name: "include regex match", includeRegex: []string{`^S01/.*E0[1-3]\.mkv$`}, relPath: "S01/Show.E02.mkv", want: false},
+		{name: "include regex no match", includeRegex: []string{`^S01/.*E0[1-3]\.mkv$`}, relPath: "S01/Show.E04.mkv", want: true},
+		{name: "include regex traverses dirs", includeRegex: []string{`\.mkv$`}, relPath: "S02", isDir: true, want: false},
+		{name: "include glob or regex", includePatterns: []string{"*.srt"}, includeRegex: []string{`\.mkv$`}, relPath: "subs/show.srt", want: false},
+		{name: "include wins over exclude regex", includeRegex: []string{`\.mkv$`}, excludeRegex: []string{"sample"}, relPath: "sample.mkv", want: false},
+		{name: "include glob wins over exclude regex", includePatterns: []string{"*.mkv"}, excludeRegex: []string{"sample"}, relPath: "sample.mkv", want: false},
+		{name: "built-in ignores with include regex", includeRegex: []string{".*"}, relPath: "Movie/Thumbs.db", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newPathFilter(tt.excludePatterns, tt.includePatterns, tt.excludeRegex, tt.includeRegex)
+			if err != nil {
+				t.Fatalf("newPathFilter() error = %v", err)
+			}
+			got, err := f.ignores(tt.relPath, tt.isDir)
+			if err != nil {
+				t.Fatalf("ignores() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ignores(%q, %v) = %v, want %v", tt.relPath, tt.isDir, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newPathFilter(nil, nil, []string{"("}, nil); err == nil {
+		t.Error("expected an error for an invalid exclude regex")
+	}
+	if _, err := newPathFilter(nil, nil, nil, []string{"[a-"}); err == nil {
+		t.Error("expected an error for an invalid include regex")
+	}
+}
+
 // TestShouldIgnoreFile tests the deprecated shouldIgnoreFile wrapper function
 // for backward compatibility with filename-only matching.
 func TestShouldIgnoreFile(t *testing.T) {
@@ -0,0 +1,73 @@
+package torrent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// InfoHashes holds the info hashes of a torrent as lowercase hex. V1 or V2 is empty
+// when the torrent has no metadata of that version (BEP 52).
+type InfoHashes struct {
+	V1 string
+	V2 string
+}
+
+// TorrentInfoHashes returns the v1 (SHA-1) and, for v2 and hybrid torrents, the v2 (SHA-256) info hash
+func TorrentInfoHashes(mi *metainfo.MetaInfo, info *metainfo.Info) InfoHashes {
+	var h InfoHashes
+	if info.HasV1() {
+		h.V1 = mi.HashInfoBytes().HexString()
+	}
+	if info.HasV2() {
+		sum := sha256.Sum256(mi.InfoBytes)
+		h.V2 = hex.EncodeToString(sum[:])
+	}
+	return h
+}
+
+// MagnetInfoHashes returns the info hashes given in a magnet link's xt parameters
+func MagnetInfoHashes(m metainfo.MagnetV2) InfoHashes {
+	var h InfoHashes
+	if m.InfoHash.Ok {
+		h.V1 = m.InfoHash.Value.HexString()
+	}
+	if m.V2InfoHash.Ok {
+		h.V2 = m.V2InfoHash.Value.HexString()
+	}
+	return h
+}
+
+// ParseInfoHash normalizes a 40 character v1 or 64 character v2 hex info hash to lowercase
+func ParseInfoHash(s string) (string, error) {
+	hash := strings.ToLower(strings.TrimSpace(s))
+	if _, err := hex.DecodeString(hash); err != nil || (len(hash) != 40 && len(hash) != 64) {
+		return "", fmt.Errorf("invalid info hash %q: expected 40 (v1) or 64 (v2) hex characters", s)
+	}
+	return hash, nil
+}
+
+// Match compares expected, a 40 character v1 or 64 character v2 hex info hash, against
+// the hash of the same version. It returns an error wrapping ErrInfoHashMismatch if they
+// differ or the torrent has no hash of that version.
+func (h InfoHashes) Match(expected string) error {
+	want, err := ParseInfoHash(expected)
+	if err != nil {
+		return err
+	}
+
+	version, got := "v1", h.V1
+	if len(want) == 64 {
+		version, got = "v2", h.V2
+	}
+	if got == "" {
+		return withKind(ErrInfoHashMismatch, fmt.Errorf("torrent has no %s info hash to compare %s against", version, want))
+	}
+	if got != want {
+		return withKind(ErrInfoHashMismatch, fmt.Errorf("%s info hash mismatch: expected %s, got %s", version, want, got))
+	}
+	return nil
+}
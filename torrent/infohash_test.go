@@ -0,0 +1,84 @@
+package torrent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestInfoHashes_Match(t *testing.T) {
+	v1 := "b61574568bc4945bfd91664908481b644448a19a"
+	v2 := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name         string
+		hashes       InfoHashes
+		expected     string
+		wantErr      bool
+		wantMismatch bool
+	}{
+		{name: "v1 match", hashes: InfoHashes{V1: v1}, expected: v1},
+		{name: "v1 match is case-insensitive", hashes: InfoHashes{V1: v1}, expected: " " + strings.ToUpper(v1) + "\n"},
+		{name: "v2 match on hybrid", hashes: InfoHashes{V1: v1, V2: v2}, expected: v2},
+		{name: "v1 mismatch", hashes: InfoHashes{V1: v1}, expected: strings.Repeat("0", 40), wantErr: true, wantMismatch: true},
+		{name: "v2 against v1 only torrent", hashes: InfoHashes{V1: v1}, expected: v2, wantErr: true, wantMismatch: true},
+		{name: "wrong length", hashes: InfoHashes{V1: v1}, expected: v1[:39], wantErr: true},
+		{name: "not hex", hashes: InfoHashes{V1: v1}, expected: strings.Repeat("z", 40), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.hashes.Match(tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Match(%q) error = %v, wantErr %v", tt.expected, err, tt.wantErr)
+			}
+			if errors.Is(err, ErrInfoHashMismatch) != tt.wantMismatch {
+				t.Errorf("Match(%q) error = %v, want ErrInfoHashMismatch %v", tt.expected, err, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestTorrentInfoHashes(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(testFile, []byte("info hash lookup"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mi, err := CreateTorrent(CreateOptions{Path: testFile, TrackerURLs: []string{"https://tracker.example.com/announce"}, NoDate: true, Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+
+	hashes := TorrentInfoHashes(mi.MetaInfo, mi.GetInfo())
+	if hashes.V1 != mi.HashInfoBytes().HexString() || hashes.V2 != "" {
+		t.Errorf("TorrentInfoHashes() = %+v, want only v1 %s", hashes, mi.HashInfoBytes().HexString())
+	}
+	if err := hashes.Match(mi.HashInfoBytes().HexString()); err != nil {
+		t.Errorf("Match() with own info hash failed: %v", err)
+	}
+
+	// hybrid torrents also have a v2 hash over the same info dict
+	info := *mi.GetInfo()
+	info.MetaVersion = 2
+	sum := sha256.Sum256(mi.InfoBytes)
+	if got := TorrentInfoHashes(mi.MetaInfo, &info).V2; got != hex.EncodeToString(sum[:]) {
+		t.Errorf("TorrentInfoHashes().V2 = %q, want %q", got, hex.EncodeToString(sum[:]))
+	}
+}
+
+func TestMagnetInfoHashes(t *testing.T) {
+	m, err := metainfo.ParseMagnetV2Uri("magnet:?xt=urn:btih:b61574568bc4945bfd91664908481b644448a19a&dn=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MagnetInfoHashes(m); got.V1 != "b61574568bc4945bfd91664908481b644448a19a" || got.V2 != "" {
+		t.Errorf("MagnetInfoHashes() = %+v, want only the btih hash", got)
+	}
+}
@@ -0,0 +1,195 @@
+package torrent
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// TorrentInspectJSON is the machine-readable form of the inspect output.
+// Every key is always present so scripts can rely on a stable shape.
+type TorrentInspectJSON struct {
+	Name         string             `json:"name"`
+	InfoHash     string             `json:"infoHash"`
+	Magnet       string             `json:"magnet"`
+	Size         int64              `json:"size"`
+	PieceLength  int64              `json:"pieceLength"`
+	PieceCount   int                `json:"pieceCount"`
+	Private      bool               `json:"private"`
+	Source       string             `json:"source"`
+	Comment      string             `json:"comment"`
+	CreatedBy    string             `json:"createdBy"`
+	CreationDate int64              `json:"creationDate"` // unix seconds, 0 when not set
+	Trackers     [][]string         `json:"trackers"`     // announce tiers
+	WebSeeds     []string           `json:"webSeeds"`
+	Nodes        []string           `json:"nodes"`
+	Files        []FileDetail       `json:"files"`
+	Validation   []ValidationResult `json:"validation"` // only filled when validating against a tracker
+	// Pieces and PieceLayers are only filled by AddPieces, as they are large
+	Pieces      []string            `json:"pieces,omitempty"`      // hex SHA-1 piece hashes (v1)
+	PieceLayers map[string][]string `json:"pieceLayers,omitempty"` // hex SHA-256 piece hashes keyed by the hex pieces root of each file (v2)
+}
+
+// FileDetail describes a single file in the torrent. Offset is the byte position
+// of the file's first byte within the concatenated torrent data, so the pieces
+// a file touches are Offset/pieceLength through (Offset+Length-1)/pieceLength.
+type FileDetail struct {
+	Path   string `json:"path"`
+	Length int64  `json:"length"`
+	Offset int64  `json:"offset"`
+}
+
+// GenerateInspectJSON collects the inspect information of a torrent
+func GenerateInspectJSON(mi *metainfo.MetaInfo, info *metainfo.Info) TorrentInspectJSON {
+	t := &Torrent{MetaInfo: mi}
+	out := TorrentInspectJSON{
+		Name:         info.Name,
+		InfoHash:     mi.HashInfoBytes().HexString(),
+		Size:         info.TotalLength(),
+		PieceLength:  info.PieceLength,
+		PieceCount:   info.NumPieces(),
+		Private:      info.Private != nil && *info.Private,
+		Source:       info.Source,
+		Comment:      mi.Comment,
+		CreatedBy:    mi.CreatedBy,
+		CreationDate: mi.CreationDate,
+		Trackers:     [][]string{},
+		WebSeeds:     []string{},
+		Nodes:        []string{},
+		Validation:   []ValidationResult{},
+	}
+
+	if magnet, err := t.MagnetV2(); err == nil {
+		out.Magnet = magnet.String()
+	}
+
+	if len(mi.AnnounceList) > 0 {
+		for _, tier := range mi.AnnounceList {
+			out.Trackers = append(out.Trackers, append([]string{}, tier...))
+		}
+	} else if mi.Announce != "" {
+		out.Trackers = append(out.Trackers, []string{mi.Announce})
+	}
+
+	out.WebSeeds = append(out.WebSeeds, mi.UrlList...)
+	for _, node := range mi.Nodes {
+		out.Nodes = append(out.Nodes, string(node))
+	}
+
+	// padding files (BEP 47) are listed too, so offsets add up to the total size
+	files := info.UpvertedFiles()
+	out.Files = make([]FileDetail, 0, len(files))
+	var offset int64
+	for _, f := range files {
+		out.Files = append(out.Files, FileDetail{
+			Path:   f.DisplayPath(info),
+			Length: f.Length,
+			Offset: offset,
+		})
+		offset += f.Length
+	}
+
+	return out
+}
+
+// AddPieces fills in the hex encoded piece hashes of the torrent, and for v2
+// torrents the piece layers, e.g. to diff two torrents piece by piece
+func (j *TorrentInspectJSON) AddPieces(mi *metainfo.MetaInfo, info *metainfo.Info) {
+	j.Pieces = make([]string, 0, info.NumPieces())
+	for i := 0; i+20 <= len(info.Pieces); i += 20 {
+		j.Pieces = append(j.Pieces, hex.EncodeToString(info.Pieces[i:i+20]))
+	}
+
+	if len(mi.PieceLayers) > 0 {
+		j.PieceLayers = make(map[string][]string, len(mi.PieceLayers))
+		for root, layer := range mi.PieceLayers {
+			hashes := make([]string, 0, len(layer)/32)
+			for i := 0; i+32 <= len(layer); i += 32 {
+				hashes = append(hashes, hex.EncodeToString([]byte(layer[i:i+32])))
+			}
+			j.PieceLayers[hex.EncodeToString([]byte(root))] = hashes
+		}
+	}
+}
+
+// GenerateMagnetInspectJSON collects what a magnet link reveals about a torrent:
+// name, info hash and trackers. File and piece details are left empty.
+func GenerateMagnetInspectJSON(uri string, m metainfo.MagnetV2) TorrentInspectJSON {
+	out := TorrentInspectJSON{
+		Name:       m.DisplayName,
+		Magnet:     uri,
+		Trackers:   [][]string{},
+		WebSeeds:   []string{},
+		Nodes:      []string{},
+		Files:      []FileDetail{},
+		Validation: []ValidationResult{},
+	}
+	if m.InfoHash.Ok {
+		out.InfoHash = m.InfoHash.Value.HexString()
+	}
+	for _, tracker := range m.Trackers {
+		out.Trackers = append(out.Trackers, []string{tracker})
+	}
+	return out
+}
+
+// InspectJSONFields returns the keys of TorrentInspectJSON in sorted order,
+// including the ones only present with AddPieces
+func InspectJSONFields() []string {
+	typ := reflect.TypeFor[TorrentInspectJSON]()
+	fields := make([]string, 0, typ.NumField())
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// SelectFields returns an object holding only the requested top-level keys.
+// Field names are the JSON keys, e.g. "name" or "infoHash", and are matched
+// case-insensitively. An unknown field is an error.
+func (j TorrentInspectJSON) SelectFields(fields []string) (map[string]json.RawMessage, error) {
+	all, err := inspectJSONMap(j)
+	if err != nil {
+		return nil, err
+	}
+
+	byLower := make(map[string]string, len(all))
+	for _, k := range InspectJSONFields() {
+		byLower[strings.ToLower(k)] = k
+	}
+
+	out := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, ok := byLower[strings.ToLower(field)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q: must be one of %s", field, strings.Join(InspectJSONFields(), ", "))
+		}
+		if value, ok := all[key]; ok {
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+func inspectJSONMap(j TorrentInspectJSON) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
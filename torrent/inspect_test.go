@@ -0,0 +1,220 @@
+package torrent
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestGenerateInspectJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "release")
+	if err := os.MkdirAll(filepath.Join(contentDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, "a.bin"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, "sub", "b.bin"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLenExp := uint(16)
+	mi, err := CreateTorrent(CreateOptions{
+		Path:           contentDir,
+		TrackerURLs:    []string{"https://tracker.example.com/announce"},
+		WebSeeds:       []string{"https://seed.example.com/"},
+		IsPrivate:      true,
+		Source:         "SRC",
+		Comment:        "a comment",
+		PieceLengthExp: &pieceLenExp,
+		NoDate:         true,
+		Quiet:          true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent failed: %v", err)
+	}
+	info := mi.GetInfo()
+
+	j := GenerateInspectJSON(mi.MetaInfo, info)
+	if j.Name != "release" || j.Size != 150 || j.PieceLength != 1<<16 || j.PieceCount != 1 {
+		t.Errorf("unexpected basics: %+v", j)
+	}
+	if j.InfoHash != mi.HashInfoBytes().HexString() {
+		t.Errorf("infoHash = %q, want %q", j.InfoHash, mi.HashInfoBytes().HexString())
+	}
+	if !j.Private || j.Source != "SRC" || j.Comment != "a comment" {
+		t.Errorf("unexpected metadata: %+v", j)
+	}
+	if j.Magnet == "" {
+		t.Error("expected a magnet link")
+	}
+	if want := [][]string{{"https://tracker.example.com/announce"}}; !reflect.DeepEqual(j.Trackers, want) {
+		t.Errorf("trackers = %v, want %v", j.Trackers, want)
+	}
+	if want := []string{"https://seed.example.com/"}; !reflect.DeepEqual(j.WebSeeds, want) {
+		t.Errorf("webSeeds = %v, want %v", j.WebSeeds, want)
+	}
+	wantFiles := []FileDetail{{Path: "a.bin", Length: 100, Offset: 0}, {Path: "sub/b.bin", Length: 50, Offset: 100}}
+	if !reflect.DeepEqual(j.Files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", j.Files, wantFiles)
+	}
+
+	// with padding every content file starts on a piece boundary
+	padded, err := CreateTorrent(CreateOptions{
+		Path:           contentDir,
+		PieceLengthExp: &pieceLenExp,
+		Padded:         true,
+		NoDate:         true,
+		Quiet:          true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent with padding failed: %v", err)
+	}
+	paddedInfo := padded.GetInfo()
+	var end int64
+	for _, f := range GenerateInspectJSON(padded.MetaInfo, paddedInfo).Files {
+		if f.Offset != end {
+			t.Errorf("%s offset = %d, want %d", f.Path, f.Offset, end)
+		}
+		if !strings.Contains(f.Path, ".pad") && f.Offset%paddedInfo.PieceLength != 0 {
+			t.Errorf("%s starts at %d, not on a piece boundary", f.Path, f.Offset)
+		}
+		end = f.Offset + f.Length
+	}
+	if end != paddedInfo.TotalLength() {
+		t.Errorf("files end at %d, want %d", end, paddedInfo.TotalLength())
+	}
+
+	// empty lists encode as [] rather than null
+	data, err := json.Marshal(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"nodes", "validation"} {
+		if v, ok := raw[key].([]any); !ok || len(v) != 0 {
+			t.Errorf("%s = %#v, want []", key, raw[key])
+		}
+	}
+}
+
+func TestTorrentInspectJSON_AddPieces(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.bin")
+	data := make([]byte, 3<<16)
+	for i := range data {
+		data[i] = byte(i / (1 << 16)) // each piece differs
+	}
+	if err := os.WriteFile(contentPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLenExp := uint(16)
+	mi, err := CreateTorrent(CreateOptions{Path: contentPath, PieceLengthExp: &pieceLenExp, NoDate: true, Quiet: true})
+	if err != nil {
+		t.Fatalf("CreateTorrent failed: %v", err)
+	}
+	info := mi.GetInfo()
+
+	j := GenerateInspectJSON(mi.MetaInfo, info)
+	plain, err := json.Marshal(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), `"pieces"`) || strings.Contains(string(plain), `"pieceLayers"`) {
+		t.Errorf("pieces present without AddPieces: %s", plain)
+	}
+
+	j.AddPieces(mi.MetaInfo, info)
+	if len(j.Pieces) != 3 {
+		t.Fatalf("pieces = %d, want 3", len(j.Pieces))
+	}
+	for i, piece := range j.Pieces {
+		if want := hex.EncodeToString(info.Pieces[i*20 : (i+1)*20]); piece != want {
+			t.Errorf("piece %d = %s, want %s", i, piece, want)
+		}
+	}
+	if j.PieceLayers != nil {
+		t.Errorf("pieceLayers = %v, want none for a v1 torrent", j.PieceLayers)
+	}
+
+	// v2 layers are keyed by the file's pieces root
+	root, layer := strings.Repeat("\x01", 32), strings.Repeat("\x02", 32)+strings.Repeat("\x03", 32)
+	j.AddPieces(&metainfo.MetaInfo{PieceLayers: map[string]string{root: layer}}, info)
+	want := map[string][]string{strings.Repeat("01", 32): {strings.Repeat("02", 32), strings.Repeat("03", 32)}}
+	if !reflect.DeepEqual(j.PieceLayers, want) {
+		t.Errorf("pieceLayers = %v, want %v", j.PieceLayers, want)
+	}
+
+	got, err := j.SelectFields([]string{"pieces"})
+	if err != nil {
+		t.Fatalf("SelectFields(pieces) failed: %v", err)
+	}
+	if _, ok := got["pieces"]; !ok {
+		t.Error("SelectFields(pieces) did not return the pieces")
+	}
+}
+
+func TestGenerateMagnetInspectJSON(t *testing.T) {
+	uri := "magnet:?xt=urn:btih:b61574568bc4945bfd91664908481b644448a19a&dn=name&tr=https%3A%2F%2Ftracker.example.com%2Fannounce"
+	m, err := ParseMagnet(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := GenerateMagnetInspectJSON(uri, m)
+	if j.Name != "name" || j.InfoHash != "b61574568bc4945bfd91664908481b644448a19a" || j.Magnet != uri {
+		t.Errorf("unexpected result: %+v", j)
+	}
+	if want := [][]string{{"https://tracker.example.com/announce"}}; !reflect.DeepEqual(j.Trackers, want) {
+		t.Errorf("trackers = %v, want %v", j.Trackers, want)
+	}
+	if j.Files == nil || len(j.Files) != 0 {
+		t.Errorf("files = %#v, want empty", j.Files)
+	}
+}
+
+func TestTorrentInspectJSON_SelectFields(t *testing.T) {
+	j := TorrentInspectJSON{
+		Name:     "name",
+		InfoHash: "abc",
+		Size:     42,
+		Files:    []FileDetail{{Path: "a", Length: 42}},
+	}
+
+	got, err := j.SelectFields([]string{"name", "INFOHASH", " size ", ""})
+	if err != nil {
+		t.Fatalf("SelectFields failed: %v", err)
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"infoHash":"abc","name":"name","size":42}`; string(data) != want {
+		t.Errorf("SelectFields = %s, want %s", data, want)
+	}
+
+	if _, err := j.SelectFields([]string{"name", "bogus"}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestValidationResultJSON(t *testing.T) {
+	data, err := json.Marshal(ValidationResult{Check: "private", Message: "ok", Severity: SeverityWarn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"check":"private","message":"ok","severity":"warn"}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,60 @@
+package torrent
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/mmap"
+)
+
+// IOMode selects how file data is read while hashing
+type IOMode string
+
+const (
+	// IOModeSync reads files with buffered read calls (default)
+	IOModeSync IOMode = "sync"
+	// IOModeMmap memory-maps the files each worker is reading and reads from the mapping
+	IOModeMmap IOMode = "mmap"
+)
+
+// maxMmapSize is the largest file that will be memory-mapped. On 32-bit platforms
+// this is 1 GiB, leaving room in the address space; larger files fall back to sync reads.
+const maxMmapSize = math.MaxInt >> 1
+
+// ParseIOMode parses an I/O mode as used by --io-mode
+func ParseIOMode(s string) (IOMode, error) {
+	switch IOMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", IOModeSync:
+		return IOModeSync, nil
+	case IOModeMmap:
+		return IOModeMmap, nil
+	default:
+		return IOModeSync, fmt.Errorf("invalid io mode %q: must be one of sync, mmap", s)
+	}
+}
+
+// mapFile memory-maps a file for reading. It returns nil for empty files, files too
+// large to map and files that can't be mapped, which are read with the sync path instead.
+func mapFile(file fileEntry) *mmap.ReaderAt {
+	if file.length == 0 {
+		return nil
+	}
+	if file.length > maxMmapSize {
+		debugf("mmap: %s is too large to map (%d bytes), falling back to sync reads", file.path, file.length)
+		return nil
+	}
+
+	r, err := mmap.Open(file.path)
+	if err != nil {
+		debugf("mmap: could not map %s, falling back to sync reads: %v", file.path, err)
+		return nil
+	}
+	// a file that shrank is reported as changed by the sync path's short read
+	if int64(r.Len()) < file.length {
+		_ = r.Close()
+		debugf("mmap: %s is smaller than expected (%d < %d bytes), falling back to sync reads", file.path, r.Len(), file.length)
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIOMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    IOMode
+		wantErr bool
+	}{
+		{input: "", want: IOModeSync},
+		{input: "sync", want: IOModeSync},
+		{input: "MMAP", want: IOModeMmap},
+		{input: "direct", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseIOMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIOMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseIOMode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPieceHasher_MmapMatchesSync(t *testing.T) {
+	pieceLen := int64(1 << 16)
+	// odd sizes so pieces span file boundaries, plus an empty file which is never mapped
+	files, expectedHashes := createTestFilesWithPattern(t, t.TempDir(), []int64{100_000, 0, 300_001, 65_536}, pieceLen)
+	numPieces := len(expectedHashes)
+
+	for _, workers := range []int{1, 4} {
+		hasher := NewPieceHasher(files, pieceLen, numPieces, &mockDisplay{}, false)
+		hasher.ioMode = IOModeMmap
+		if err := hasher.hashPieces(workers); err != nil {
+			t.Fatalf("hashPieces(%d) with mmap failed: %v", workers, err)
+		}
+		verifyHashes(t, hasher.pieces, expectedHashes)
+	}
+}
+
+func TestFileReaderCache_Mmap(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "mapped.bin")
+	if err := os.WriteFile(path, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := []fileEntry{
+		{path: path, length: 4096},
+		// a directory can't be mapped, so it is opened for regular reads instead
+		{path: tmpDir, length: 1 << 20, offset: 4096},
+	}
+	readers := newFileReaderCache(files)
+	readers.mmap = true
+	defer readers.closeAll()
+
+	mapped, err := readers.get(0)
+	if err != nil {
+		t.Fatalf("get(0) failed: %v", err)
+	}
+	if mapped.mapped == nil || mapped.file != nil {
+		t.Error("expected the file to be mapped")
+	}
+
+	fallback, err := readers.get(1)
+	if err != nil {
+		t.Fatalf("get(1) failed: %v", err)
+	}
+	if fallback.mapped != nil || fallback.file == nil {
+		t.Error("expected a file that can't be mapped to fall back to regular reads")
+	}
+
+	// only the files a worker is still on stay mapped
+	readers.releaseBefore(1)
+	if readers.readers[0] != nil || readers.open != 1 {
+		t.Errorf("expected the mapping to be released, %d readers open", readers.open)
+	}
+}
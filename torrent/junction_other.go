@@ -0,0 +1,10 @@
+//go:build !windows
+
+package torrent
+
+import "os"
+
+// isJunction is only implemented on Windows; elsewhere there are no junctions
+func isJunction(os.FileInfo) bool {
+	return false
+}
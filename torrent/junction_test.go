@@ -0,0 +1,91 @@
+package torrent
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestJunctionWithin(t *testing.T) {
+	dir := filepath.Join("C", "Media", "Show")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: dir, want: true},
+		{path: filepath.Join("C", "media", "show"), want: true},
+		{path: filepath.Join(dir, "Season 1", "link"), want: true},
+		{path: filepath.Join("C", "Media", "Show2", "link"), want: false},
+		{path: filepath.Join("C", "Media"), want: false},
+		{path: filepath.Join("D", "Media", "Show"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := junctionWithin(tt.path, dir); got != tt.want {
+				t.Errorf("junctionWithin(%q, %q) = %v, want %v", tt.path, dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_DereferenceJunctions(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("directory junctions only exist on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "Content")
+	extrasDir := filepath.Join(tmpDir, "Extras")
+	for _, dir := range []string{contentDir, extrasDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, "movie.mkv"), []byte("movie data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(extrasDir, "extra.mkv"), []byte("extra data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mklink := func(link, target string) {
+		t.Helper()
+		if out, err := exec.Command("cmd", "/c", "mklink", "/J", link, target).CombinedOutput(); err != nil {
+			t.Skipf("could not create junction: %v: %s", err, out)
+		}
+	}
+	mklink(filepath.Join(contentDir, "Extras"), extrasDir)
+	mklink(filepath.Join(extrasDir, "Loop"), contentDir) // leads back into the content
+
+	files := func(deref bool) []string {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{
+			Path:                 contentDir,
+			DereferenceJunctions: deref,
+			Quiet:                true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent failed: %v", err)
+		}
+		info, err := mi.UnmarshalInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, f := range info.UpvertedFiles() {
+			paths = append(paths, filepath.ToSlash(filepath.Join(f.Path...)))
+		}
+		slices.Sort(paths)
+		return paths
+	}
+
+	if got, want := files(false), []string{"movie.mkv"}; !slices.Equal(got, want) {
+		t.Errorf("without dereferencing got files %v, want %v", got, want)
+	}
+	if got, want := files(true), []string{"Extras/extra.mkv", "movie.mkv"}; !slices.Equal(got, want) {
+		t.Errorf("with dereferencing got files %v, want %v", got, want)
+	}
+}
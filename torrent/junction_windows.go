@@ -0,0 +1,21 @@
+package torrent
+
+import (
+	"os"
+	"syscall"
+)
+
+// isJunction reports whether info, as returned by Lstat, is an NTFS directory
+// junction or mount point. Go reports these as irregular files rather than
+// symlinks or directories, so a plain walk would add them as empty files.
+func isJunction(info os.FileInfo) bool {
+	if info.Mode()&(os.ModeSymlink|os.ModeDir) != 0 {
+		return false
+	}
+	attrs, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return false
+	}
+	const junction = syscall.FILE_ATTRIBUTE_REPARSE_POINT | syscall.FILE_ATTRIBUTE_DIRECTORY
+	return attrs.FileAttributes&junction == junction
+}
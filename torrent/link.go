@@ -0,0 +1,95 @@
+package torrent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// linkOutput places the torrent file at torrentPath into dir, as a symlink where
+// possible and as a copy on Windows or when the filesystem refuses symlinks. It
+// returns the path of the link and whether it had to copy. An existing file at
+// that path is only replaced with force.
+func linkOutput(torrentPath, dir string, force bool) (string, bool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("error creating link directory %q: %w", dir, err)
+	}
+
+	target, err := filepath.Abs(torrentPath)
+	if err != nil {
+		return "", false, fmt.Errorf("error resolving %q: %w", torrentPath, err)
+	}
+	linkPath, exists, err := checkLinkTarget(torrentPath, dir, force)
+	if err != nil {
+		return "", false, err
+	}
+
+	if exists {
+		if err := os.Remove(linkPath); err != nil {
+			return "", false, fmt.Errorf("error replacing %q: %w", linkPath, err)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		err := os.Symlink(target, linkPath)
+		if err == nil {
+			return linkPath, false, nil
+		}
+		if errors.Is(err, fs.ErrExist) {
+			return "", false, fmt.Errorf("%w: %s", ErrOutputExists, linkPath)
+		}
+		// e.g. FAT or SMB mounts without symlink support, fall back to a copy
+	}
+
+	if err := copyFile(target, linkPath); err != nil {
+		return "", false, err
+	}
+	return linkPath, true, nil
+}
+
+// checkLinkTarget returns where linkOutput would place torrentPath in dir and whether
+// something is already there. It fails if the link would replace the torrent itself,
+// or an existing file without force, so CreateContext can check before hashing.
+func checkLinkTarget(torrentPath, dir string, force bool) (linkPath string, exists bool, err error) {
+	target, err := filepath.Abs(torrentPath)
+	if err != nil {
+		return "", false, fmt.Errorf("error resolving %q: %w", torrentPath, err)
+	}
+	linkPath = filepath.Join(dir, filepath.Base(torrentPath))
+
+	if same, _ := filepath.Abs(linkPath); same == target {
+		return "", false, fmt.Errorf("cannot link %q onto itself, pick a directory other than the output directory", torrentPath)
+	}
+
+	fi, err := os.Lstat(linkPath)
+	if err != nil {
+		return linkPath, false, nil
+	}
+	if !force {
+		return "", false, fmt.Errorf("%w: %s (modified %s)", ErrOutputExists, linkPath, fi.ModTime().Format("2006-01-02 15:04:05"))
+	}
+	return linkPath, true, nil
+}
+
+// copyFile copies src to a new file at dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("error copying %q to %q: %w", src, dst, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,83 @@
+package torrent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLinkOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	torrentPath := filepath.Join(tmpDir, "torrents", "test.torrent")
+	if err := os.MkdirAll(filepath.Dir(torrentPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(torrentPath, []byte("d4:infod4:name4:testee"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := filepath.Join(tmpDir, "content")
+
+	linkPath, copied, err := linkOutput(torrentPath, linkDir, false)
+	if err != nil {
+		t.Fatalf("linkOutput() error = %v", err)
+	}
+	if want := filepath.Join(linkDir, "test.torrent"); linkPath != want {
+		t.Errorf("linkOutput() path = %q, want %q", linkPath, want)
+	}
+	if runtime.GOOS != "windows" {
+		if copied {
+			t.Error("linkOutput() copied, want a symlink")
+		}
+		if fi, err := os.Lstat(linkPath); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("%s is not a symlink (err %v)", linkPath, err)
+		}
+	}
+	if data, err := os.ReadFile(linkPath); err != nil || string(data) != "d4:infod4:name4:testee" {
+		t.Errorf("reading through the link = %q, %v", data, err)
+	}
+
+	if _, _, err := linkOutput(torrentPath, linkDir, false); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("linkOutput() over an existing link error = %v, want ErrOutputExists", err)
+	}
+	if _, _, err := linkOutput(torrentPath, linkDir, true); err != nil {
+		t.Errorf("linkOutput() with force error = %v", err)
+	}
+
+	if _, _, err := linkOutput(torrentPath, filepath.Dir(torrentPath), true); err == nil {
+		t.Error("linkOutput() into the torrent's own directory succeeded, want error")
+	}
+	if _, err := os.Stat(torrentPath); err != nil {
+		t.Errorf("torrent file was removed: %v", err)
+	}
+}
+
+func TestCreate_SymlinkToExistingChecksBeforeHashing(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := filepath.Join(tmpDir, "content.bin")
+	if err := os.WriteFile(content, []byte("link target test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := filepath.Join(tmpDir, "links")
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(linkDir, "out.torrent"), []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hashed := false
+	outputPath := filepath.Join(tmpDir, "out.torrent")
+	_, err := Create(CreateOptions{Path: content, OutputPath: outputPath, SymlinkTo: linkDir, Quiet: true,
+		ProgressCallback: func(completed, total int, hashRate float64) { hashed = true }})
+	if !errors.Is(err, ErrOutputExists) {
+		t.Fatalf("Create() error = %v, want ErrOutputExists", err)
+	}
+	if hashed {
+		t.Error("expected the existing link target to be reported before hashing")
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Errorf("expected no torrent to be written, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package torrent
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// BadPieceLocation describes where a bad piece lies on disk, see VerifyOptions.Locate.
+// Without the original data the exact differing byte can't be known, so the
+// location narrows it down to the file ranges the piece covers.
+type BadPieceLocation struct {
+	Piece  int         `json:"piece"`
+	Offset int64       `json:"offset"` // offset of the piece in the torrent's data
+	Length int64       `json:"length"`
+	Spans  []PieceSpan `json:"spans"`
+}
+
+// PieceSpan is the byte range [Start, End) of one file that a piece covers
+type PieceSpan struct {
+	Path    string `json:"path"`
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+	Padding bool   `json:"padding,omitempty"` // BEP 47 padding, not stored on disk
+	Zeroed  bool   `json:"zeroed,omitempty"`  // every byte read back is zero, e.g. space allocated but never written
+	// ReadError is set when the range could not be read back, the other spans are still checked
+	ReadError string `json:"readError,omitempty"`
+}
+
+// locatePiece maps pieceIndex onto files and re-reads each range to flag ranges
+// holding only zeros, the usual sign of a download that never wrote them. A range
+// that can't be read is reported on its span rather than failing the whole check.
+func locatePiece(files []fileEntry, pieceIndex int, pieceLen, totalLength int64) *BadPieceLocation {
+	pieceStart := int64(pieceIndex) * pieceLen
+	pieceEnd := min(pieceStart+pieceLen, totalLength)
+	loc := &BadPieceLocation{
+		Piece:  pieceIndex,
+		Offset: pieceStart,
+		Length: pieceEnd - pieceStart,
+		Spans:  []PieceSpan{},
+	}
+
+	for _, f := range files {
+		if f.offset >= pieceEnd || f.offset+f.length <= pieceStart {
+			continue
+		}
+		span := PieceSpan{
+			Path:    f.path,
+			Start:   max(pieceStart-f.offset, 0),
+			End:     min(pieceEnd-f.offset, f.length),
+			Padding: f.padding,
+		}
+		if !f.padding {
+			zeroed, err := rangeIsZero(f.path, span.Start, span.End)
+			if err != nil {
+				span.ReadError = err.Error()
+			}
+			span.Zeroed = zeroed
+		}
+		loc.Spans = append(loc.Spans, span)
+	}
+	return loc
+}
+
+// filesWithBadPieces returns the paths, as listed in MissingFiles, of the files that
+// overlap a bad piece, in torrent order. Padding files are skipped as they aren't on disk.
+func filesWithBadPieces(info *metainfo.Info, badPieces []int) []string {
+	if len(badPieces) == 0 {
+		return nil
+	}
+	pieces := slices.Sorted(slices.Values(badPieces))
+
+	var files []string
+	var offset int64
+	p := 0
+	for _, f := range info.UpvertedFiles() {
+		start, end := offset, offset+f.Length
+		offset = end
+		if f.Length == 0 || isPaddingFile(f) {
+			continue
+		}
+		for p < len(pieces) && int64(pieces[p]+1)*info.PieceLength <= start {
+			p++
+		}
+		if p == len(pieces) {
+			break
+		}
+		if int64(pieces[p])*info.PieceLength < end {
+			if info.IsDir() {
+				files = append(files, filepath.ToSlash(filepath.Join(f.Path...)))
+			} else {
+				files = append(files, info.Name)
+			}
+		}
+	}
+	return files
+}
+
+// rangeIsZero reports whether the bytes [start, end) of the file at path are all zero
+func rangeIsZero(path string, start, end int64) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("error opening %q: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, 64<<10)
+	for off := start; off < end; {
+		n, err := f.ReadAt(buf[:min(int64(len(buf)), end-off)], off)
+		for _, b := range buf[:n] {
+			if b != 0 {
+				return false, nil
+			}
+		}
+		off += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("error reading %q: %w", path, err)
+		}
+	}
+	return true, nil
+}
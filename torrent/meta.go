@@ -0,0 +1,54 @@
+package torrent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// standardRootKeys are the metainfo root keys written by mkbrr or defined by a BEP,
+// which custom metadata must not replace
+var standardRootKeys = map[string]bool{
+	"announce": true, "announce-list": true, "comment": true, "created by": true,
+	"creation date": true, "encoding": true, "httpseeds": true, "info": true,
+	"nodes": true, "piece layers": true, "url-list": true,
+}
+
+// standardInfoKeys are the info dictionary keys written by mkbrr or defined by a
+// BEP, which custom info fields must not replace
+var standardInfoKeys = map[string]bool{
+	"entropy": true, "file tree": true, "files": true, "length": true, "md5sum": true,
+	"meta version": true, "name": true, "name.utf-8": true, "piece length": true,
+	"pieces": true, "private": true, "source": true,
+}
+
+// ParseMetaField parses custom metadata given as "key=value"
+func ParseMetaField(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid metadata %q: must be key=value", s)
+	}
+	if key = strings.TrimSpace(key); key == "" {
+		return "", "", fmt.Errorf("invalid metadata %q: key is empty", s)
+	}
+	return key, value, nil
+}
+
+// customFields returns the entries of meta that don't collide with a standard key,
+// and the colliding keys that were left out, sorted
+func customFields(meta map[string]string, standardKeys map[string]bool) (map[string]string, []string) {
+	if len(meta) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]string, len(meta))
+	var skipped []string
+	for key, value := range meta {
+		if standardKeys[key] {
+			skipped = append(skipped, key)
+			continue
+		}
+		fields[key] = value
+	}
+	sort.Strings(skipped)
+	return fields, skipped
+}
@@ -0,0 +1,125 @@
+package torrent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+)
+
+func TestParseMetaField(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{input: "x_created_with=archiver", wantKey: "x_created_with", wantValue: "archiver"},
+		{input: "x_note=a=b", wantKey: "x_note", wantValue: "a=b"},
+		{input: "x_empty=", wantKey: "x_empty", wantValue: ""},
+		{input: "no-value", wantErr: true},
+		{input: " =value", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			key, value, err := ParseMetaField(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMetaField(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if key != tt.wantKey || value != tt.wantValue {
+				t.Errorf("ParseMetaField(%q) = %q, %q, want %q, %q", tt.input, key, value, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_Meta(t *testing.T) {
+	contentPath := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(contentPath, []byte("archived content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLenExp := uint(16)
+	create := func(meta map[string]string) ([]byte, string) {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{
+			Path:           contentPath,
+			PieceLengthExp: &pieceLenExp,
+			Comment:        "from the flag",
+			Meta:           meta,
+			NoDate:         true,
+			Quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent() error = %v", err)
+		}
+		var buf bytes.Buffer
+		if err := mi.Write(&buf); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		return buf.Bytes(), mi.HashInfoBytes().HexString()
+	}
+
+	_, plainHash := create(nil)
+	data, hash := create(map[string]string{"x_created_with": "archiver", "comment": "from meta"})
+	if hash != plainHash {
+		t.Errorf("info hash = %s with custom metadata, want %s", hash, plainHash)
+	}
+
+	var root map[string]any
+	if err := bencode.Unmarshal(data, &root); err != nil {
+		t.Fatalf("failed to decode torrent: %v", err)
+	}
+	if root["x_created_with"] != "archiver" {
+		t.Errorf("x_created_with = %v, want archiver", root["x_created_with"])
+	}
+	if root["comment"] != "from the flag" {
+		t.Errorf("comment = %v, want the standard key left untouched", root["comment"])
+	}
+}
+
+func TestCreateTorrent_InfoMeta(t *testing.T) {
+	contentPath := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(contentPath, []byte("tagged content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLenExp := uint(16)
+	create := func(infoMeta map[string]string) *Torrent {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{
+			Path:           contentPath,
+			PieceLengthExp: &pieceLenExp,
+			InfoMeta:       infoMeta,
+			NoDate:         true,
+			Quiet:          true,
+		})
+		if err != nil {
+			t.Fatalf("CreateTorrent() error = %v", err)
+		}
+		return mi
+	}
+
+	plain := create(nil)
+	tagged := create(map[string]string{"x_tool": "required-value", "name": "renamed"})
+	if tagged.HashInfoBytes() == plain.HashInfoBytes() {
+		t.Error("info hash is unchanged with a custom info field")
+	}
+
+	var info map[string]any
+	if err := bencode.Unmarshal(tagged.InfoBytes, &info); err != nil {
+		t.Fatalf("failed to decode info: %v", err)
+	}
+	if info["x_tool"] != "required-value" {
+		t.Errorf("info x_tool = %v, want required-value", info["x_tool"])
+	}
+	if info["name"] != "file.bin" {
+		t.Errorf("info name = %v, want the standard key left untouched", info["name"])
+	}
+	if tagged.GetInfo().NumPieces() != plain.GetInfo().NumPieces() {
+		t.Error("pieces changed with a custom info field")
+	}
+}
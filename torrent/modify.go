@@ -1,8 +1,10 @@
 package torrent
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/anacrolix/torrent/bencode"
@@ -25,18 +27,24 @@ type ModifyOptions struct {
 	TrackerURLs    []string
 	Comment        string
 	Source         string
+	CreatedBy      string // custom "created by" value, takes precedence over NoCreator
 	Version        string
 	WebSeeds       []string
 	NoDate         bool
 	NoCreator      bool
+	CreationDate   time.Time // fixed creation date, takes precedence over NoDate
 	DryRun         bool
+	Force          bool // overwrite an existing output file instead of failing with ErrOutputExists
 	Verbose        bool
 	Quiet          bool
 	Entropy        *bool
 	SkipPrefix     bool
-	SourceSet      bool // true when --source flag was explicitly provided (allows empty string to clear)
-	CommentSet     bool // true when --comment flag was explicitly provided (allows empty string to clear)
-	RemovePrivate  bool // true when --no-private flag is provided (removes private field entirely)
+	SourceSet      bool       // true when --source flag was explicitly provided (allows empty string to clear)
+	CommentSet     bool       // true when --comment flag was explicitly provided (allows empty string to clear)
+	RemovePrivate  bool       // true when --no-private flag is provided (removes private field entirely)
+	RemoveWebSeeds bool       // remove existing web seeds unless WebSeeds replaces them
+	AppendTrackers bool       // add TrackerURLs as new tiers after the existing announce list instead of replacing it
+	AnnounceList   [][]string // tiers added after TrackerURLs, e.g. copied from another torrent with LoadTrackerTiers
 }
 
 // Result represents the result of modifying a torrent
@@ -52,23 +60,78 @@ type Result struct {
 func LoadFromFile(path string) (*Torrent, error) {
 	mi, err := metainfo.LoadFromFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("could not load torrent: %w", err)
+		return nil, notFound(fmt.Errorf("could not load torrent: %w", err))
+	}
+	if info, err := mi.UnmarshalInfo(); err == nil {
+		if err := ValidatePieces(&info); err != nil {
+			return nil, fmt.Errorf("could not load torrent: %w", err)
+		}
 	}
 	return &Torrent{MetaInfo: mi}, nil
 }
 
+// appendTrackerTiers adds the given tiers after the existing ones, leaving out trackers
+// already in the announce list. The primary announce URL is kept unless there was none.
+// Returns whether any tracker was added.
+func appendTrackerTiers(mi *metainfo.MetaInfo, newTiers [][]string) bool {
+	var tiers [][]string
+	seen := make(map[string]bool)
+	for _, tier := range mi.UpvertedAnnounceList() {
+		tiers = append(tiers, append([]string{}, tier...))
+		for _, tracker := range tier {
+			seen[tracker] = true
+		}
+	}
+
+	added := false
+	for _, tier := range newTiers {
+		var kept []string
+		for _, tracker := range tier {
+			if tracker == "" || seen[tracker] {
+				continue
+			}
+			seen[tracker] = true
+			kept = append(kept, tracker)
+		}
+		if len(kept) > 0 {
+			tiers = append(tiers, kept)
+			added = true
+		}
+	}
+	if !added {
+		return false
+	}
+
+	mi.AnnounceList = tiers
+	if mi.Announce == "" {
+		mi.Announce = tiers[0][0]
+	}
+	return true
+}
+
 // ModifyTorrent modifies a single torrent file according to the given options.
 // It can change trackers, comment, source, piece length, and other metadata.
 // Returns a Result containing the operation outcome and output path.
 func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
+	return ModifyTorrentContext(context.Background(), path, opts)
+}
+
+// ModifyTorrentContext is ModifyTorrent with a context. If ctx is done before the
+// output is written, nothing is written and an error matching both ErrCancelled and
+// ctx.Err() is returned.
+func ModifyTorrentContext(ctx context.Context, path string, opts ModifyOptions) (*Result, error) {
 	result := &Result{
 		Path: path,
 	}
+	if err := ctxErr(ctx); err != nil {
+		result.Error = err
+		return result, result.Error
+	}
 
 	// load torrent file
 	mi, err := metainfo.LoadFromFile(path)
 	if err != nil {
-		result.Error = fmt.Errorf("could not load torrent: %w", err)
+		result.Error = notFound(fmt.Errorf("could not load torrent: %w", err))
 		return result, result.Error
 	}
 
@@ -112,6 +175,10 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 		result.Error = fmt.Errorf("could not unmarshal info: %w", err)
 		return result, result.Error
 	}
+	if err := ValidatePieces(&info); err != nil {
+		result.Error = err
+		return result, result.Error
+	}
 	originalMetaInfoName := info.Name
 
 	// track info-level changes to apply via raw map at the end,
@@ -124,14 +191,23 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 	var infoChanges []infoChange
 
 	// apply flag-based overrides:
-	// update tracker if flag provided
-	if len(opts.TrackerURLs) > 0 {
-		mi.Announce = opts.TrackerURLs[0] // Primary announce is the first one
-		announceList := make([][]string, len(opts.TrackerURLs))
-		for i, tracker := range opts.TrackerURLs {
-			announceList[i] = []string{tracker}
+	// update tracker if flag provided: each tracker URL is its own tier, followed by AnnounceList
+	trackerTiers := make([][]string, 0, len(opts.TrackerURLs)+len(opts.AnnounceList))
+	for _, tracker := range opts.TrackerURLs {
+		trackerTiers = append(trackerTiers, []string{tracker})
+	}
+	for _, tier := range opts.AnnounceList {
+		if len(tier) > 0 {
+			trackerTiers = append(trackerTiers, slices.Clone(tier))
 		}
-		mi.AnnounceList = announceList
+	}
+	if len(trackerTiers) > 0 && opts.AppendTrackers {
+		if appendTrackerTiers(mi, trackerTiers) {
+			wasModified = true
+		}
+	} else if len(trackerTiers) > 0 {
+		mi.Announce = trackerTiers[0][0] // Primary announce is the first one
+		mi.AnnounceList = trackerTiers
 		wasModified = true
 		// Note: This overrides any trackers set by a preset
 	}
@@ -146,6 +222,9 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 	if len(opts.WebSeeds) > 0 {
 		mi.UrlList = opts.WebSeeds
 		wasModified = true
+	} else if opts.RemoveWebSeeds && len(mi.UrlList) > 0 {
+		mi.UrlList = nil
+		wasModified = true
 	}
 
 	// update comment if provided via flag (CommentSet allows clearing with empty string)
@@ -228,13 +307,19 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 	}
 
 	// handle creator
-	if presetOpts != nil && presetOpts.NoCreator != nil && *presetOpts.NoCreator || opts.NoCreator {
+	if opts.CreatedBy != "" {
+		mi.CreatedBy = opts.CreatedBy
+		wasModified = true
+	} else if presetOpts != nil && presetOpts.NoCreator != nil && *presetOpts.NoCreator || opts.NoCreator {
 		mi.CreatedBy = ""
 		wasModified = true
 	}
 
 	// update creation date based on preset and command line options
-	if presetOpts != nil && presetOpts.NoDate != nil && *presetOpts.NoDate || opts.NoDate {
+	if !opts.CreationDate.IsZero() {
+		mi.CreationDate = opts.CreationDate.Unix()
+		wasModified = true
+	} else if presetOpts != nil && presetOpts.NoDate != nil && *presetOpts.NoDate || opts.NoDate {
 		mi.CreationDate = 0
 		wasModified = true
 	} else {
@@ -253,13 +338,20 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 
 	// re-read info to get potentially updated name (e.g. if name was changed via infoChanges)
 	var metaInfoName string
+	var totalLength int64
 	if updatedInfo, infoErr := mi.UnmarshalInfo(); infoErr == nil {
 		metaInfoName = updatedInfo.Name
+		totalLength = updatedInfo.TotalLength()
 	}
 
+	// the output filename follows the torrent name, so renaming with --name renames the file too
 	basePath := path
-	if opts.OutputPattern == "" && originalMetaInfoName != "" {
-		basePath = originalMetaInfoName + ".torrent"
+	if opts.OutputPattern == "" {
+		if metaInfoName != "" {
+			basePath = metaInfoName + ".torrent"
+		} else if originalMetaInfoName != "" {
+			basePath = originalMetaInfoName + ".torrent"
+		}
 	}
 
 	// determine output directory: command-line flag takes precedence over preset
@@ -270,12 +362,24 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 
 	// generate output path using the preset generating helper
 	var trackerForOutput string
-	if len(opts.TrackerURLs) > 0 {
-		trackerForOutput = opts.TrackerURLs[0]
-	} else {
-		trackerForOutput = ""
+	if len(trackerTiers) > 0 {
+		trackerForOutput = trackerTiers[0][0]
+	}
+	outputPattern := opts.OutputPattern
+	if outputPattern != "" {
+		// {tracker} falls back to the torrent's existing announce URL
+		patternTracker := trackerForOutput
+		if patternTracker == "" {
+			patternTracker = mi.Announce
+		}
+		outputPattern = preset.ExpandOutputPattern(outputPattern, preset.OutputTemplateData{
+			Name:     metaInfoName,
+			Tracker:  patternTracker,
+			InfoHash: mi.HashInfoBytes().String(),
+			Size:     totalLength,
+		})
 	}
-	outPath := preset.GenerateOutputPath(basePath, outputDir, opts.PresetName, opts.OutputPattern, trackerForOutput, metaInfoName, opts.SkipPrefix)
+	outPath := preset.GenerateOutputPath(basePath, outputDir, opts.PresetName, outputPattern, trackerForOutput, metaInfoName, opts.SkipPrefix)
 	result.OutputPath = outPath
 
 	// ensure output directory exists if specified
@@ -286,15 +390,20 @@ func ModifyTorrent(path string, opts ModifyOptions) (*Result, error) {
 		}
 	}
 
+	if err := ctxErr(ctx); err != nil {
+		result.Error = err
+		return result, result.Error
+	}
+
 	// save modified torrent file
-	f, err := os.Create(outPath)
+	f, err := createOutputFile(outPath, opts.Force)
 	if err != nil {
 		result.Error = fmt.Errorf("could not create output file: %w", err)
 		return result, result.Error
 	}
 	defer f.Close()
 
-	if err := mi.Write(f); err != nil {
+	if err := writeMetaInfo(f, mi, nil); err != nil {
 		result.Error = fmt.Errorf("could not write output file: %w", err)
 		return result, result.Error
 	}
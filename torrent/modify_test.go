@@ -1,12 +1,17 @@
 package torrent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
 )
 
 func TestModifyTorrent_OutputDirPriority(t *testing.T) {
@@ -271,6 +276,8 @@ presets:
 }
 
 func TestModify_NameArgument(t *testing.T) {
+	// cases without an OutputDir write relative to the working directory
+	t.Chdir(t.TempDir())
 
 	tracker := "https://unknown.customtracker.com/announce"
 	tracker2 := "https://unknown.customtracker2.com/announce"
@@ -368,12 +375,13 @@ func TestModify_NameArgument(t *testing.T) {
 			name: "With --name argument no --skip-prefix no -o",
 			path: torrentFilepath,
 			opts: ModifyOptions{
+				OutputDir:  tmpDir,
 				Name:       "customname",
 				SkipPrefix: false,
 				Quiet:      true,
 			},
 			expectedName:     "customname",
-			expectedFilename: "modified_oldname.torrent",
+			expectedFilename: "modified_customname.torrent",
 		},
 		{
 			name: "With --name argument --skip-prefix present -o supplied",
@@ -426,17 +434,68 @@ func TestModify_NameArgument(t *testing.T) {
 			name: "Prefixed input with --name argument no --skip-prefix no -o",
 			path: prefixedTorrentFilepath,
 			opts: ModifyOptions{
+				OutputDir:  tmpDir,
 				Name:       "customname",
 				SkipPrefix: false,
 				Quiet:      true,
 			},
 			expectedName:     "customname",
-			expectedFilename: "modified_oldname.torrent",
+			expectedFilename: "modified_customname.torrent",
+		},
+		{
+			name: "With --name argument --skip-prefix present no -o",
+			path: torrentFilepath,
+			opts: ModifyOptions{
+				OutputDir:  tmpDir,
+				Name:       "customname",
+				SkipPrefix: true,
+				Quiet:      true,
+			},
+			expectedName:     "customname",
+			expectedFilename: "customname.torrent",
+		},
+		{
+			name: "With --name argument no --skip-prefix no -o -t supplied",
+			path: torrentFilepath,
+			opts: ModifyOptions{
+				OutputDir:   tmpDir,
+				Name:        "customname",
+				TrackerURLs: []string{tracker2},
+				Quiet:       true,
+			},
+			expectedName:     "customname",
+			expectedFilename: "customtracker2_customname.torrent",
+		},
+		{
+			name: "With unchanged --name argument no -o",
+			path: torrentFilepath,
+			opts: ModifyOptions{
+				OutputDir:   tmpDir,
+				Name:        "oldname",
+				TrackerURLs: []string{tracker2},
+				Quiet:       true,
+			},
+			expectedName:     "oldname",
+			expectedFilename: "customtracker2_oldname.torrent",
+		},
+		{
+			name: "With --name argument --output-pattern placeholders -t supplied",
+			path: torrentFilepath,
+			opts: ModifyOptions{
+				Name:          "customname",
+				OutputPattern: "{tracker}_{name}",
+				TrackerURLs:   []string{tracker2},
+				Quiet:         true,
+			},
+			expectedName:     "customname",
+			expectedFilename: "customtracker2_customname.torrent",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// cases share output filenames
+			tt.opts.Force = true
 
 			// Modify the torrent
 			result, err := ModifyTorrent(tt.path, tt.opts)
@@ -635,6 +694,56 @@ func TestModifyTorrent_RemoveFields(t *testing.T) {
 		}
 	})
 
+	t.Run("RemoveWebSeeds", func(t *testing.T) {
+		webSeedTorrentPath := filepath.Join(tmpDir, "webseed_test.torrent")
+		_, err := Create(CreateOptions{
+			Path:       tmpDir,
+			OutputPath: webSeedTorrentPath,
+			IsPrivate:  true,
+			Source:     "SRC",
+			WebSeeds:   []string{"https://example.com/files/"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create web seed torrent: %v", err)
+		}
+
+		// the options --anonymous maps to: source and private must survive
+		outPath := filepath.Join(tmpDir, "anonymous.torrent")
+		result, err := ModifyTorrent(webSeedTorrentPath, ModifyOptions{
+			RemoveWebSeeds: true,
+			NoCreator:      true,
+			NoDate:         true,
+			CommentSet:     true,
+			OutputDir:      tmpDir,
+			OutputPattern:  "anonymous",
+			Version:        "test",
+		})
+		if err != nil {
+			t.Fatalf("ModifyTorrent failed: %v", err)
+		}
+		if !result.WasModified {
+			t.Fatal("Expected torrent to be modified")
+		}
+
+		mi, err := LoadFromFile(outPath)
+		if err != nil {
+			t.Fatalf("Failed to load modified torrent: %v", err)
+		}
+		if len(mi.UrlList) != 0 {
+			t.Errorf("Expected web seeds to be removed, got %v", mi.UrlList)
+		}
+		if mi.CreatedBy != "" || mi.CreationDate != 0 {
+			t.Errorf("Expected creator and date to be removed, got %q and %d", mi.CreatedBy, mi.CreationDate)
+		}
+		info, err := mi.UnmarshalInfo()
+		if err != nil {
+			t.Fatalf("Failed to unmarshal info: %v", err)
+		}
+		if info.Source != "SRC" || info.Private == nil || !*info.Private {
+			t.Errorf("Expected source and private to be kept, got %q and %v", info.Source, info.Private)
+		}
+	})
+
 	t.Run("PreserveEntropyWhenRemovingPrivate", func(t *testing.T) {
 		// create a torrent with entropy enabled
 		entropyTorrentPath := filepath.Join(tmpDir, "entropy_test.torrent")
@@ -788,3 +897,245 @@ func TestModifyTorrent_RemoveFields(t *testing.T) {
 		}
 	})
 }
+
+func TestModifyTorrent_CreatedBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.txt")
+	if err := os.WriteFile(contentPath, []byte("created by test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torrentPath := filepath.Join(tmpDir, "test.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, Version: "test"}); err != nil {
+		t.Fatalf("Failed to create test torrent: %v", err)
+	}
+
+	// a custom creator wins over NoCreator
+	result, err := ModifyTorrent(torrentPath, ModifyOptions{
+		CreatedBy:     "GROUP",
+		NoCreator:     true,
+		OutputDir:     tmpDir,
+		OutputPattern: "created_by",
+		Version:       "test",
+	})
+	if err != nil {
+		t.Fatalf("ModifyTorrent failed: %v", err)
+	}
+
+	mi, err := LoadFromFile(result.OutputPath)
+	if err != nil {
+		t.Fatalf("Failed to load modified torrent: %v", err)
+	}
+	if mi.CreatedBy != "GROUP" {
+		t.Errorf("Expected created by %q, got %q", "GROUP", mi.CreatedBy)
+	}
+}
+
+func TestModifyTorrent_CreationDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.txt")
+	if err := os.WriteFile(contentPath, []byte("creation date test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torrentPath := filepath.Join(tmpDir, "test.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath}); err != nil {
+		t.Fatalf("Failed to create test torrent: %v", err)
+	}
+
+	result, err := ModifyTorrent(torrentPath, ModifyOptions{
+		CreationDate:  time.Unix(1672671845, 0),
+		OutputDir:     tmpDir,
+		OutputPattern: "set_date",
+	})
+	if err != nil {
+		t.Fatalf("ModifyTorrent failed: %v", err)
+	}
+
+	mi, err := LoadFromFile(result.OutputPath)
+	if err != nil {
+		t.Fatalf("Failed to load modified torrent: %v", err)
+	}
+	if mi.CreationDate != 1672671845 {
+		t.Errorf("Expected creation date %d, got %d", 1672671845, mi.CreationDate)
+	}
+}
+
+func TestModifyTorrent_AppendTrackers(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.txt")
+	if err := os.WriteFile(contentPath, []byte("append trackers test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torrentPath := filepath.Join(tmpDir, "test.torrent")
+	if _, err := Create(CreateOptions{
+		Path:        contentPath,
+		OutputPath:  torrentPath,
+		TrackerURLs: []string{"https://old.example.com/announce"},
+		WebSeeds:    []string{"https://seed.example.com/"},
+		Quiet:       true,
+	}); err != nil {
+		t.Fatalf("Failed to create test torrent: %v", err)
+	}
+
+	result, err := ModifyTorrent(torrentPath, ModifyOptions{
+		TrackerURLs:    []string{"https://old.example.com/announce", "https://new.example.com/announce"},
+		AppendTrackers: true,
+		OutputDir:      tmpDir,
+		OutputPattern:  "appended",
+	})
+	if err != nil {
+		t.Fatalf("ModifyTorrent failed: %v", err)
+	}
+
+	mi, err := LoadFromFile(result.OutputPath)
+	if err != nil {
+		t.Fatalf("Failed to load modified torrent: %v", err)
+	}
+	// the existing tracker stays primary and is not added twice
+	wantTiers := [][]string{{"https://old.example.com/announce"}, {"https://new.example.com/announce"}}
+	if mi.Announce != "https://old.example.com/announce" {
+		t.Errorf("Expected announce to stay %q, got %q", "https://old.example.com/announce", mi.Announce)
+	}
+	if !reflect.DeepEqual([][]string(mi.AnnounceList), wantTiers) {
+		t.Errorf("Expected announce list %v, got %v", wantTiers, mi.AnnounceList)
+	}
+	if !reflect.DeepEqual(mi.UrlList, metainfo.UrlList{"https://seed.example.com/"}) {
+		t.Errorf("Expected web seeds to be kept, got %v", mi.UrlList)
+	}
+
+	// appending only known trackers leaves the announce list alone
+	if appendTrackerTiers(mi.MetaInfo, [][]string{{"https://new.example.com/announce"}}) {
+		t.Error("Expected no change when all trackers are already present")
+	}
+	if !reflect.DeepEqual([][]string(mi.AnnounceList), wantTiers) {
+		t.Errorf("Expected announce list %v, got %v", wantTiers, mi.AnnounceList)
+	}
+}
+
+func TestModifyTorrent_TrackersFrom(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.txt")
+	if err := os.WriteFile(contentPath, []byte("trackers from test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sourceTiers := [][]string{
+		{"https://a.example.com/announce", "https://b.example.com/announce"},
+		{"https://c.example.com/announce"},
+	}
+	sourcePath := filepath.Join(tmpDir, "source.torrent")
+	if _, err := Create(CreateOptions{
+		Path:         contentPath,
+		OutputPath:   sourcePath,
+		TrackerURLs:  []string{"https://a.example.com/announce", "https://b.example.com/announce", "https://c.example.com/announce"},
+		AnnounceList: sourceTiers,
+		Quiet:        true,
+	}); err != nil {
+		t.Fatalf("Failed to create source torrent: %v", err)
+	}
+	targetPath := filepath.Join(tmpDir, "target.torrent")
+	if _, err := Create(CreateOptions{
+		Path:        contentPath,
+		OutputPath:  targetPath,
+		TrackerURLs: []string{"https://old.example.com/announce"},
+		Quiet:       true,
+	}); err != nil {
+		t.Fatalf("Failed to create target torrent: %v", err)
+	}
+	barePath := filepath.Join(tmpDir, "bare.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: barePath, Quiet: true}); err != nil {
+		t.Fatalf("Failed to create torrent without trackers: %v", err)
+	}
+
+	tiers, err := LoadTrackerTiers(sourcePath)
+	if err != nil {
+		t.Fatalf("LoadTrackerTiers failed: %v", err)
+	}
+	if !reflect.DeepEqual(tiers, sourceTiers) {
+		t.Fatalf("Expected tiers %v, got %v", sourceTiers, tiers)
+	}
+	if _, err := LoadTrackerTiers(barePath); err == nil {
+		t.Error("Expected an error for a torrent without trackers")
+	}
+
+	tests := []struct {
+		name      string
+		opts      ModifyOptions
+		wantTiers [][]string
+	}{
+		{
+			name: "replace",
+			opts: ModifyOptions{AnnounceList: tiers},
+			wantTiers: [][]string{
+				{"https://a.example.com/announce", "https://b.example.com/announce"},
+				{"https://c.example.com/announce"},
+			},
+		},
+		{
+			name: "replace after tracker flag",
+			opts: ModifyOptions{TrackerURLs: []string{"https://first.example.com/announce"}, AnnounceList: tiers},
+			wantTiers: [][]string{
+				{"https://first.example.com/announce"},
+				{"https://a.example.com/announce", "https://b.example.com/announce"},
+				{"https://c.example.com/announce"},
+			},
+		},
+		{
+			name: "append",
+			opts: ModifyOptions{AnnounceList: tiers, AppendTrackers: true},
+			wantTiers: [][]string{
+				{"https://old.example.com/announce"},
+				{"https://a.example.com/announce", "https://b.example.com/announce"},
+				{"https://c.example.com/announce"},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.OutputDir = tmpDir
+			tt.opts.OutputPattern = fmt.Sprintf("trackers_from_%d", i)
+			result, err := ModifyTorrent(targetPath, tt.opts)
+			if err != nil {
+				t.Fatalf("ModifyTorrent failed: %v", err)
+			}
+
+			mi, err := LoadFromFile(result.OutputPath)
+			if err != nil {
+				t.Fatalf("Failed to load modified torrent: %v", err)
+			}
+			if mi.Announce != tt.wantTiers[0][0] {
+				t.Errorf("Expected announce %q, got %q", tt.wantTiers[0][0], mi.Announce)
+			}
+			if !reflect.DeepEqual([][]string(mi.AnnounceList), tt.wantTiers) {
+				t.Errorf("Expected announce list %v, got %v", tt.wantTiers, mi.AnnounceList)
+			}
+		})
+	}
+}
+
+func TestModifyTorrentContext_Cancel(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.txt")
+	if err := os.WriteFile(contentPath, []byte("cancel test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torrentPath := filepath.Join(tmpDir, "test.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, Quiet: true}); err != nil {
+		t.Fatalf("Failed to create test torrent: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ModifyTorrentContext(ctx, torrentPath, ModifyOptions{
+		TrackerURLs:   []string{"https://new.example.com/announce"},
+		OutputDir:     tmpDir,
+		OutputPattern: "cancelled",
+	})
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("ModifyTorrentContext() error = %v, want ErrCancelled and context.Canceled", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "cancelled.torrent")); !os.IsNotExist(err) {
+		t.Errorf("Expected no output file after cancelling, stat error = %v", err)
+	}
+}
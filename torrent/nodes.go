@@ -0,0 +1,78 @@
+package torrent
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// ParseNode validates a DHT bootstrap node given as host:port
+func ParseNode(s string) (metainfo.Node, error) {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid node %q: must be host:port", s)
+	}
+	if host == "" {
+		return "", fmt.Errorf("invalid node %q: missing host", s)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid node %q: port must be between 1 and 65535", s)
+	}
+	return metainfo.Node(net.JoinHostPort(host, port)), nil
+}
+
+// Write bencodes the torrent to w, see writeMetaInfo
+func (t *Torrent) Write(w io.Writer) error {
+	return writeMetaInfo(w, t.MetaInfo, t.meta)
+}
+
+// writeMetaInfo bencodes mi to w, adding the string keys in meta to the root
+// dictionary. metainfo.Node encodes as a "host:port" string, so nodes are rewritten
+// as the [host, port] pairs BEP 5 specifies. Keys outside the info dictionary are
+// written canonically, see canonicalMetaInfo, while the info dictionary is copied
+// as encoded, leaving its hash untouched.
+func writeMetaInfo(w io.Writer, mi *metainfo.MetaInfo, meta map[string]string) error {
+	data, err := bencode.Marshal(mi)
+	if err != nil {
+		return err
+	}
+	var root map[string]bencode.Bytes
+	if err := bencode.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	if len(mi.Nodes) > 0 {
+		nodes := make([][]any, 0, len(mi.Nodes))
+		for _, node := range mi.Nodes {
+			host, port, err := net.SplitHostPort(string(node))
+			if err != nil {
+				return fmt.Errorf("invalid node %q: %w", node, err)
+			}
+			portNum, err := strconv.Atoi(port)
+			if err != nil {
+				return fmt.Errorf("invalid node %q: %w", node, err)
+			}
+			nodes = append(nodes, []any{host, portNum})
+		}
+		if root["nodes"], err = bencode.Marshal(nodes); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range meta {
+		if root[key], err = bencode.Marshal(value); err != nil {
+			return err
+		}
+	}
+
+	data, err = canonicalMetaInfo(root)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
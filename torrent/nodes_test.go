@@ -0,0 +1,103 @@
+package torrent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestParseNode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    metainfo.Node
+		wantErr bool
+	}{
+		{input: "router.bittorrent.com:6881", want: "router.bittorrent.com:6881"},
+		{input: "127.0.0.1:1", want: "127.0.0.1:1"},
+		{input: "[::1]:6881", want: "[::1]:6881"},
+		{input: "router.bittorrent.com", wantErr: true},
+		{input: ":6881", wantErr: true},
+		{input: "host:0", wantErr: true},
+		{input: "host:65536", wantErr: true},
+		{input: "host:port", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_Nodes(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "file.bin")
+	if err := os.WriteFile(contentPath, []byte("trackerless content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLenExp := uint(16)
+	opts := CreateOptions{
+		Path:           contentPath,
+		PieceLengthExp: &pieceLenExp,
+		Nodes:          []string{"router.bittorrent.com:6881", "[::1]:6881"},
+		NoDate:         true,
+		Quiet:          true,
+	}
+
+	mi, err := CreateTorrent(opts)
+	if err != nil {
+		t.Fatalf("CreateTorrent failed: %v", err)
+	}
+	if mi.Announce != "" || mi.AnnounceList != nil {
+		t.Errorf("expected no trackers, got %q %v", mi.Announce, mi.AnnounceList)
+	}
+
+	var buf bytes.Buffer
+	if err := mi.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	// nodes are written as [host, port] pairs (BEP 5)
+	var raw map[string]any
+	if err := bencode.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to decode torrent: %v", err)
+	}
+	want := []any{[]any{"router.bittorrent.com", int64(6881)}, []any{"::1", int64(6881)}}
+	if !reflect.DeepEqual(raw["nodes"], want) {
+		t.Errorf("nodes = %v, want %v", raw["nodes"], want)
+	}
+
+	loaded, err := metainfo.Load(&buf)
+	if err != nil {
+		t.Fatalf("failed to load written torrent: %v", err)
+	}
+	if loaded.HashInfoBytes() != mi.HashInfoBytes() {
+		t.Errorf("info hash changed on write: %s != %s", loaded.HashInfoBytes(), mi.HashInfoBytes())
+	}
+	if len(loaded.Nodes) != 2 || loaded.Nodes[0] != "router.bittorrent.com:6881" || loaded.Nodes[1] != "[::1]:6881" {
+		t.Errorf("unexpected nodes after reload: %v", loaded.Nodes)
+	}
+
+	opts.IsPrivate = true
+	if _, err := CreateTorrent(opts); err == nil {
+		t.Error("expected an error for nodes on a private torrent")
+	}
+
+	opts.IsPrivate = false
+	opts.Nodes = []string{"no-port"}
+	if _, err := CreateTorrent(opts); err == nil {
+		t.Error("expected an error for an invalid node")
+	}
+}
@@ -0,0 +1,75 @@
+package torrent
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// paddingDir is the directory BEP 47 padding files are conventionally placed in
+const paddingDir = ".pad"
+
+// padFiles returns files with BEP 47 padding entries inserted after every file
+// except the last, so each file starts on a piece boundary. Offsets are
+// recalculated to include the padding.
+func padFiles(files []fileEntry, pieceLen int64) []fileEntry {
+	padded := make([]fileEntry, 0, len(files)*2)
+	var offset int64
+	for i, f := range files {
+		f.offset = offset
+		padded = append(padded, f)
+		offset += f.length
+
+		if i == len(files)-1 {
+			break
+		}
+		if rem := offset % pieceLen; rem != 0 {
+			padLen := pieceLen - rem
+			padded = append(padded, fileEntry{
+				path:    paddingDir + "/" + strconv.FormatInt(padLen, 10),
+				length:  padLen,
+				offset:  offset,
+				padding: true,
+			})
+			offset += padLen
+		}
+	}
+	return padded
+}
+
+// withoutPadding returns files with padding entries removed
+func withoutPadding(files []fileEntry) []fileEntry {
+	content := make([]fileEntry, 0, len(files))
+	for _, f := range files {
+		if !f.padding {
+			content = append(content, f)
+		}
+	}
+	return content
+}
+
+// isPaddingFile reports whether a file is marked as padding with the BEP 47 "p" attribute
+func isPaddingFile(f metainfo.FileInfo) bool {
+	return strings.Contains(f.Attr, "p")
+}
+
+// paddingFileInfo returns the info dictionary entry for a padding file of length bytes
+func paddingFileInfo(length int64) metainfo.FileInfo {
+	return metainfo.FileInfo{
+		Path:              []string{paddingDir, strconv.FormatInt(length, 10)},
+		Length:            length,
+		ExtendedFileAttrs: metainfo.ExtendedFileAttrs{Attr: "p"},
+	}
+}
+
+// hashZeros writes n zero bytes to w using buf as scratch space
+func hashZeros(w io.Writer, buf []byte, n int64) {
+	clear(buf)
+	for n > 0 {
+		chunk := min(n, int64(len(buf)))
+		_, _ = w.Write(buf[:chunk])
+		n -= chunk
+	}
+}
@@ -0,0 +1,131 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPadFiles(t *testing.T) {
+	files := []fileEntry{
+		{path: "a", length: 100},
+		{path: "b", length: 64}, // ends on a piece boundary, no padding needed
+		{path: "c", length: 0},
+		{path: "d", length: 10},
+		{path: "e", length: 5}, // last file is never padded
+	}
+
+	got := padFiles(files, 64)
+
+	want := []fileEntry{
+		{path: "a", length: 100, offset: 0},
+		{path: ".pad/28", length: 28, offset: 100, padding: true},
+		{path: "b", length: 64, offset: 128},
+		{path: "c", length: 0, offset: 192},
+		{path: "d", length: 10, offset: 192},
+		{path: ".pad/54", length: 54, offset: 202, padding: true},
+		{path: "e", length: 5, offset: 256},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("padFiles() returned %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, f := range got {
+		if !f.padding && f.offset%64 != 0 {
+			t.Errorf("file %s starts at %d, not on a piece boundary", f.path, f.offset)
+		}
+	}
+
+	if content := withoutPadding(got); len(content) != len(files) {
+		t.Errorf("withoutPadding() returned %d entries, want %d", len(content), len(files))
+	}
+}
+
+func TestCreateTorrent_Padded(t *testing.T) {
+	const pieceExp = 16
+	const pieceLen = int64(1) << pieceExp
+
+	contentDir := filepath.Join(t.TempDir(), "Padded.Pack")
+	if err := os.MkdirAll(contentDir, 0755); err != nil {
+		t.Fatalf("failed to create content dir: %v", err)
+	}
+	// the first two files need the same padding, so their padding entries share a name
+	sizes := map[string]int64{
+		"01.mkv": pieceLen + 1000,
+		"02.mkv": pieceLen + 1000,
+		"03.mkv": 2 * pieceLen,
+		"04.nfo": 300,
+	}
+	for name, size := range sizes {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(len(name) + i%251)
+		}
+		if err := os.WriteFile(filepath.Join(contentDir, name), data, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	exp := uint(pieceExp)
+	mi, err := CreateTorrent(CreateOptions{
+		Path:           contentDir,
+		PieceLengthExp: &exp,
+		Padded:         true,
+		NoDate:         true,
+		Quiet:          true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() error = %v", err)
+	}
+
+	info := mi.GetInfo()
+	var offset int64
+	var padCount int
+	for _, f := range info.Files {
+		if isPaddingFile(f) {
+			padCount++
+			if f.Length <= 0 || f.Length >= pieceLen {
+				t.Errorf("padding file %v has invalid length %d", f.Path, f.Length)
+			}
+		} else if offset%pieceLen != 0 {
+			t.Errorf("file %v starts at %d, not on a piece boundary", f.Path, offset)
+		}
+		offset += f.Length
+	}
+	// 01 and 02 need padding, 03 ends on a boundary and 04 is last
+	if padCount != 2 {
+		t.Errorf("got %d padding files, want 2", padCount)
+	}
+	if wantPieces := int((offset + pieceLen - 1) / pieceLen); info.NumPieces() != wantPieces {
+		t.Errorf("got %d pieces, want %d", info.NumPieces(), wantPieces)
+	}
+
+	torrentPath := filepath.Join(t.TempDir(), "padded.torrent")
+	f, err := os.Create(torrentPath)
+	if err != nil {
+		t.Fatalf("failed to create torrent file: %v", err)
+	}
+	if err := mi.Write(f); err != nil {
+		f.Close()
+		t.Fatalf("failed to write torrent: %v", err)
+	}
+	f.Close()
+
+	result, err := VerifyData(VerifyOptions{
+		TorrentPath: torrentPath,
+		ContentPath: contentDir,
+		Quiet:       true,
+	})
+	if err != nil {
+		t.Fatalf("VerifyData() error = %v", err)
+	}
+	if result.BadPieces != 0 || len(result.MissingFiles) != 0 || result.GoodPieces != result.TotalPieces {
+		t.Errorf("verification of padded torrent failed: good %d/%d, bad %d, missing %v",
+			result.GoodPieces, result.TotalPieces, result.BadPieces, result.MissingFiles)
+	}
+}
@@ -1,5 +1,11 @@
 package torrent
 
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
 // Displayer defines the interface for displaying progress during torrent creation
 type Displayer interface {
 	ShowProgress(total int)
@@ -9,3 +15,34 @@ type Displayer interface {
 	FinishProgress()
 	IsBatch() bool
 }
+
+// ProgressEvent is a single progress update as written by NewJSONProgressCallback
+type ProgressEvent struct {
+	Completed int     `json:"completed"`
+	Total     int     `json:"total"`
+	HashRate  float64 `json:"hashRate"` // MiB per second
+	Percent   float64 `json:"percent"`
+}
+
+// NewJSONProgressCallback returns a ProgressCallback that writes each update to w
+// as one JSON object per line. Updates arrive at the hashing progress interval (200ms).
+func NewJSONProgressCallback(w io.Writer) ProgressCallback {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+
+	return func(completed, total int, hashRate float64) {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(completed) / float64(total) * 100
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(ProgressEvent{
+			Completed: completed,
+			Total:     total,
+			HashRate:  hashRate,
+			Percent:   percent,
+		})
+	}
+}
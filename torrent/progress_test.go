@@ -0,0 +1,109 @@
+package torrent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestNewJSONProgressCallback(t *testing.T) {
+	var buf bytes.Buffer
+	callback := NewJSONProgressCallback(&buf)
+
+	callback(0, 4, 0)
+	callback(1, 4, 12.5)
+	callback(4, 4, 0)
+
+	var events []ProgressEvent
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if want := (ProgressEvent{Completed: 1, Total: 4, HashRate: 12.5, Percent: 25}); events[1] != want {
+		t.Errorf("events[1] = %+v, want %+v", events[1], want)
+	}
+	if events[2].Percent != 100 {
+		t.Errorf("final event percent = %v, want 100", events[2].Percent)
+	}
+}
+
+func TestCreateTorrent_JSONProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.bin")
+	if err := os.WriteFile(testFile, bytes.Repeat([]byte("x"), 1<<20), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	pieceLength := uint(16)
+	_, err := CreateTorrent(CreateOptions{
+		Path:             testFile,
+		PieceLengthExp:   &pieceLength,
+		ProgressCallback: NewJSONProgressCallback(&buf),
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() failed: %v", err)
+	}
+
+	var last ProgressEvent
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		if err := json.Unmarshal(scanner.Bytes(), &last); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+	}
+	if last.Total != 16 || last.Completed != 16 {
+		t.Errorf("last event = %+v, want 16/16 pieces", last)
+	}
+}
+
+func TestVerifyData_ProgressCallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.bin")
+	if err := os.WriteFile(testFile, bytes.Repeat([]byte("x"), 1<<20), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	torrentPath := filepath.Join(tmpDir, "test.torrent")
+	pieceLength := uint(16)
+	if _, err := Create(CreateOptions{Path: testFile, OutputPath: torrentPath, PieceLengthExp: &pieceLength, Quiet: true}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var events [][2]int
+	_, err := VerifyData(VerifyOptions{
+		TorrentPath: torrentPath,
+		ContentPath: testFile,
+		ProgressCallback: func(completed, total int, _ float64) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, [2]int{completed, total})
+		},
+	})
+	if err != nil {
+		t.Fatalf("VerifyData() failed: %v", err)
+	}
+
+	// same contract as CreateTorrent: a start and a final event around the updates
+	if len(events) < 2 {
+		t.Fatalf("got %d progress events, want at least 2", len(events))
+	}
+	if first := events[0]; first != [2]int{0, 16} {
+		t.Errorf("first event = %v, want [0 16]", first)
+	}
+	if last := events[len(events)-1]; last != [2]int{16, 16} {
+		t.Errorf("last event = %v, want [16 16]", last)
+	}
+}
@@ -0,0 +1,115 @@
+package torrent
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces secrets in displayed URLs
+const redactedValue = "***"
+
+// secretQueryParams are announce URL query parameters that hold a user's credentials
+var secretQueryParams = map[string]bool{
+	"passkey":      true,
+	"authkey":      true,
+	"torrent_pass": true,
+}
+
+// minPathKeyLength is the length from which an alphanumeric path segment is treated
+// as a passkey, e.g. "/announce/<32 hex chars>" as used by many private trackers
+const minPathKeyLength = 24
+
+// RedactTrackerURL masks the credentials in a tracker URL for display so logs can be
+// shared safely. The values of passkey, authkey and torrent_pass query parameters,
+// passkey-like path segments and userinfo passwords are replaced with "***"; scheme,
+// host and the rest of the path are kept. URLs that can't be parsed are returned as is.
+func RedactTrackerURL(trackerURL string) string {
+	u, err := url.Parse(trackerURL)
+	if err != nil || u.Host == "" {
+		return trackerURL
+	}
+
+	// the masks are set on the raw forms, as url.URL would escape the asterisks
+	var userinfo string
+	if u.User != nil {
+		userinfo = u.User.String()
+		if _, ok := u.User.Password(); ok {
+			userinfo = url.User(u.User.Username()).String() + ":" + redactedValue
+		}
+		u.User = nil
+	}
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, segment := range segments {
+		if isPathKey(segment) {
+			segments[i] = redactedValue
+		}
+	}
+	u.RawPath = strings.Join(segments, "/")
+	if path, err := url.PathUnescape(u.RawPath); err == nil {
+		u.Path = path
+	}
+
+	// rewrite the raw query to keep the parameter order
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			key, _, found := strings.Cut(param, "=")
+			if found && secretQueryParams[strings.ToLower(key)] {
+				params[i] = key + "=" + redactedValue
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+
+	if userinfo != "" {
+		scheme, rest, _ := strings.Cut(u.String(), "//")
+		return scheme + "//" + userinfo + "@" + rest
+	}
+	return u.String()
+}
+
+// redactMagnet masks the credentials of the tracker URLs (tr parameters) in a magnet link
+func redactMagnet(uri string) string {
+	prefix, query, found := strings.Cut(uri, "?")
+	if !found {
+		return uri
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, value, found := strings.Cut(param, "=")
+		if !found || key != "tr" {
+			continue
+		}
+		tracker, err := url.QueryUnescape(value)
+		if err != nil {
+			continue
+		}
+		params[i] = key + "=" + url.QueryEscape(RedactTrackerURL(tracker))
+	}
+	return prefix + "?" + strings.Join(params, "&")
+}
+
+// isPathKey reports whether a URL path segment looks like a passkey
+func isPathKey(segment string) bool {
+	if len(segment) < minPathKeyLength {
+		return false
+	}
+	for _, r := range segment {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Redact masks the credentials in the tracker URLs and magnet link, see RedactTrackerURL
+func (j *TorrentInspectJSON) Redact() {
+	for _, tier := range j.Trackers {
+		for i, tracker := range tier {
+			tier[i] = RedactTrackerURL(tracker)
+		}
+	}
+	j.Magnet = redactMagnet(j.Magnet)
+}
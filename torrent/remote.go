@@ -0,0 +1,166 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// DefaultRemoteTimeout is the default timeout for fetching a torrent over HTTP
+const DefaultRemoteTimeout = 30 * time.Second
+
+// MaxRemoteTorrentSize is the largest .torrent file that will be fetched over HTTP
+// or read from stdin
+const MaxRemoteTorrentSize = 10 << 20
+
+// StdinPath is the torrent path that reads the torrent from stdin instead of a file
+const StdinPath = "-"
+
+// allowedTorrentContentTypes are the content types accepted when fetching a torrent.
+// Servers commonly serve torrents as a generic binary download.
+var allowedTorrentContentTypes = map[string]bool{
+	"application/x-bittorrent":   true,
+	"application/octet-stream":   true,
+	"binary/octet-stream":        true,
+	"application/force-download": true,
+	"application/download":       true,
+}
+
+// IsRemoteTorrent reports whether path is an http(s) URL rather than a local file
+func IsRemoteTorrent(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// IsStdinTorrent reports whether path means the torrent is piped to stdin
+func IsStdinTorrent(path string) bool {
+	return path == StdinPath
+}
+
+// IsMagnetLink reports whether path is a magnet URI
+func IsMagnetLink(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), "magnet:")
+}
+
+// LoadFromURL fetches a torrent file over http(s) into memory and parses it.
+// The raw bytes are returned as well, e.g. for checking the torrent file size.
+func LoadFromURL(rawURL string, timeout time.Duration) (*Torrent, []byte, error) {
+	return LoadFromURLContext(context.Background(), rawURL, timeout)
+}
+
+// LoadFromURLContext is LoadFromURL with a context. When ctx is done, the request is
+// aborted and an error matching both ErrCancelled and ctx.Err() is returned.
+func LoadFromURLContext(ctx context.Context, rawURL string, timeout time.Duration) (*Torrent, []byte, error) {
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not fetch torrent: %w", err)
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		if err := ctxErr(ctx); err != nil {
+			return nil, nil, err
+		}
+		// url.Error repeats the full URL, passkey included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, nil, fmt.Errorf("could not fetch torrent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("could not fetch torrent: unexpected status %s", resp.Status)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !allowedTorrentContentTypes[mediaType] {
+			return nil, nil, fmt.Errorf("unexpected content type %q (expected application/x-bittorrent), the URL may require authentication", contentType)
+		}
+	}
+
+	if resp.ContentLength > MaxRemoteTorrentSize {
+		return nil, nil, fmt.Errorf("torrent file is too large (%d bytes, limit %d)", resp.ContentLength, MaxRemoteTorrentSize)
+	}
+
+	return LoadFromReader(resp.Body)
+}
+
+// LoadFromReader reads a torrent file from r into memory and parses it, e.g. one
+// piped to stdin. The raw bytes are returned as well.
+func LoadFromReader(r io.Reader) (*Torrent, []byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxRemoteTorrentSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read torrent: %w", err)
+	}
+	if len(data) > MaxRemoteTorrentSize {
+		return nil, nil, fmt.Errorf("torrent file is too large (limit %d bytes)", MaxRemoteTorrentSize)
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("could not load torrent: no data")
+	}
+
+	mi, err := metainfo.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load torrent: %w", err)
+	}
+	if info, err := mi.UnmarshalInfo(); err == nil {
+		if err := ValidatePieces(&info); err != nil {
+			return nil, nil, fmt.Errorf("could not load torrent: %w", err)
+		}
+	}
+
+	return &Torrent{MetaInfo: mi}, data, nil
+}
+
+// stdinTorrent holds the torrent read from stdin. Stdin can only be read once, so
+// every load in the same run, e.g. to find the content path and then to verify,
+// gets the same torrent.
+var stdinTorrent struct {
+	once sync.Once
+	t    *Torrent
+	data []byte
+	err  error
+}
+
+// LoadFromStdin reads a torrent file piped to stdin, see LoadFromReader
+func LoadFromStdin() (*Torrent, []byte, error) {
+	stdinTorrent.once.Do(func() {
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			stdinTorrent.err = fmt.Errorf("no torrent piped to stdin")
+			return
+		}
+		stdinTorrent.t, stdinTorrent.data, stdinTorrent.err = LoadFromReader(os.Stdin)
+	})
+	return stdinTorrent.t, stdinTorrent.data, stdinTorrent.err
+}
+
+// ParseMagnet parses the info hash, trackers and display name from a magnet URI.
+// Fetching the full metadata from peers is not supported.
+func ParseMagnet(uri string) (metainfo.MagnetV2, error) {
+	m, err := metainfo.ParseMagnetV2Uri(uri)
+	if err != nil {
+		return m, fmt.Errorf("could not parse magnet link: %w", err)
+	}
+	if !m.InfoHash.Ok && !m.V2InfoHash.Ok {
+		return m, fmt.Errorf("could not parse magnet link: missing info hash")
+	}
+	return m, nil
+}
@@ -0,0 +1,191 @@
+package torrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func createRemoteTestTorrent(t *testing.T) (contentPath string, data []byte) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	contentPath = filepath.Join(tmpDir, "remote.bin")
+	if err := os.WriteFile(contentPath, []byte(strings.Repeat("remote torrent data", 1000)), 0644); err != nil {
+		t.Fatalf("failed to write content: %v", err)
+	}
+
+	torrentPath := filepath.Join(tmpDir, "remote.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, NoDate: true, NoCreator: true}); err != nil {
+		t.Fatalf("failed to create torrent: %v", err)
+	}
+
+	data, err := os.ReadFile(torrentPath)
+	if err != nil {
+		t.Fatalf("failed to read torrent: %v", err)
+	}
+	return contentPath, data
+}
+
+func TestLoadFromURL(t *testing.T) {
+	_, data := createRemoteTestTorrent(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		length      string
+		wantErr     string
+	}{
+		{name: "bittorrent content type", contentType: "application/x-bittorrent", status: http.StatusOK},
+		{name: "octet stream", contentType: "application/octet-stream", status: http.StatusOK},
+		{name: "html login page", contentType: "text/html; charset=utf-8", status: http.StatusOK, wantErr: "unexpected content type"},
+		{name: "not found", contentType: "application/x-bittorrent", status: http.StatusNotFound, wantErr: "unexpected status"},
+		{name: "too large", contentType: "application/x-bittorrent", status: http.StatusOK, length: strconv.Itoa(MaxRemoteTorrentSize + 1), wantErr: "too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.length != "" {
+					w.Header().Set("Content-Length", tt.length)
+				}
+				w.WriteHeader(tt.status)
+				if tt.length == "" {
+					w.Write(data)
+				}
+			}))
+			defer server.Close()
+
+			torrent, raw, err := LoadFromURL(server.URL+"/file.torrent", 0)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadFromURL() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFromURL() error = %v", err)
+			}
+			if len(raw) != len(data) {
+				t.Errorf("got %d raw bytes, want %d", len(raw), len(data))
+			}
+			info, err := torrent.UnmarshalInfo()
+			if err != nil {
+				t.Fatalf("UnmarshalInfo() error = %v", err)
+			}
+			if info.Name != "remote.bin" {
+				t.Errorf("name = %q, want remote.bin", info.Name)
+			}
+		})
+	}
+}
+
+func TestLoadFromURLContext_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, _, err := LoadFromURLContext(ctx, server.URL+"/file.torrent", 0)
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("LoadFromURLContext() error = %v, want ErrCancelled and context.Canceled", err)
+	}
+}
+
+func TestLoadFromURL_ErrorOmitsPasskey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL + "/download?torrent_pass=0123456789abcdef0123456789abcdef"
+	server.Close()
+
+	_, _, err := LoadFromURL(url, 0)
+	if err == nil {
+		t.Fatal("expected an error fetching from a closed server")
+	}
+	if strings.Contains(err.Error(), "0123456789abcdef") {
+		t.Errorf("error %q contains the passkey", err)
+	}
+}
+
+func TestLoadFromReader(t *testing.T) {
+	_, data := createRemoteTestTorrent(t)
+
+	tor, raw, err := LoadFromReader(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("LoadFromReader() error = %v", err)
+	}
+	if string(raw) != string(data) {
+		t.Error("LoadFromReader() did not return the raw torrent bytes")
+	}
+	if name := tor.GetInfo().Name; name != "remote.bin" {
+		t.Errorf("LoadFromReader() name = %q, want %q", name, "remote.bin")
+	}
+
+	for name, input := range map[string]string{
+		"empty":       "",
+		"not bencode": "<html>",
+		"too large":   strings.Repeat("x", MaxRemoteTorrentSize+1),
+	} {
+		if _, _, err := LoadFromReader(strings.NewReader(input)); err == nil {
+			t.Errorf("LoadFromReader() with %s input: expected an error", name)
+		}
+	}
+}
+
+func TestVerifyData_RemoteTorrent(t *testing.T) {
+	contentPath, data := createRemoteTestTorrent(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-bittorrent")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	result, err := VerifyData(VerifyOptions{TorrentPath: server.URL, ContentPath: contentPath, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData() error = %v", err)
+	}
+	if result.Completion != 100.0 {
+		t.Errorf("completion = %.2f, want 100", result.Completion)
+	}
+}
+
+func TestParseMagnet(t *testing.T) {
+	uri := "magnet:?xt=urn:btih:c9e15763f722f23e98a29decdfae341b98d53056&dn=Example.Show.S01&tr=https%3A%2F%2Ftracker.example.com%2Fannounce"
+
+	if !IsMagnetLink(uri) || IsRemoteTorrent(uri) {
+		t.Fatalf("expected %q to be detected as a magnet link only", uri)
+	}
+
+	m, err := ParseMagnet(uri)
+	if err != nil {
+		t.Fatalf("ParseMagnet() error = %v", err)
+	}
+	if m.DisplayName != "Example.Show.S01" {
+		t.Errorf("DisplayName = %q, want Example.Show.S01", m.DisplayName)
+	}
+	if got := m.InfoHash.Value.HexString(); got != "c9e15763f722f23e98a29decdfae341b98d53056" {
+		t.Errorf("InfoHash = %s", got)
+	}
+	if len(m.Trackers) != 1 || m.Trackers[0] != "https://tracker.example.com/announce" {
+		t.Errorf("Trackers = %v", m.Trackers)
+	}
+
+	if _, err := ParseMagnet("magnet:?dn=missing-hash"); err == nil {
+		t.Error("ParseMagnet() expected error for magnet without info hash")
+	}
+}
@@ -0,0 +1,34 @@
+package torrent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultReadRetries is the number of times a failed read is retried by the CLI
+const DefaultReadRetries = 3
+
+// readRetryDelay is the wait before the first retry, doubled after each attempt
+var readRetryDelay = 100 * time.Millisecond
+
+// readAtWithRetry reads len(buf) bytes at off, retrying failed reads up to retries
+// times with exponential backoff, as errors on network filesystems are often transient.
+// Like io.ReaderAt it returns io.EOF if the file ends before buf is filled, which is
+// not retried. Other errors include the path, offset and number of attempts.
+func readAtWithRetry(r io.ReaderAt, path string, buf []byte, off int64, retries int) (int, error) {
+	delay := readRetryDelay
+	for attempt := 1; ; attempt++ {
+		n, err := r.ReadAt(buf, off)
+		if err == nil || errors.Is(err, io.EOF) {
+			return n, err
+		}
+		if attempt > retries {
+			return n, fmt.Errorf("failed to read file %s at offset %d after %d attempt(s): %w", path, off, attempt, err)
+		}
+		debugf("read: %s at offset %d failed (attempt %d of %d), retrying in %s: %v", path, off, attempt, retries+1, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
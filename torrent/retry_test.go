@@ -0,0 +1,88 @@
+package torrent
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyReaderAt fails the first failures calls to ReadAt before reading from data
+type flakyReaderAt struct {
+	data     []byte
+	failures int
+	calls    int
+}
+
+func (r *flakyReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return 0, syscall.EIO
+	}
+	return bytes.NewReader(r.data).ReadAt(p, off)
+}
+
+func TestReadAtWithRetry(t *testing.T) {
+	oldDelay := readRetryDelay
+	readRetryDelay = time.Millisecond
+	t.Cleanup(func() { readRetryDelay = oldDelay })
+
+	tests := []struct {
+		name      string
+		failures  int
+		retries   int
+		offset    int64
+		size      int
+		want      string
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "no errors", retries: 3, offset: 2, size: 4, want: "cdef", wantCalls: 1},
+		{name: "recovers after transient errors", failures: 2, retries: 3, offset: 0, size: 3, want: "abc", wantCalls: 3},
+		{name: "gives up after retries", failures: 5, retries: 2, offset: 4, size: 2, wantErr: syscall.EIO, wantCalls: 3},
+		{name: "retries disabled", failures: 1, retries: 0, offset: 0, size: 2, wantErr: syscall.EIO, wantCalls: 1},
+		{name: "eof is not retried", retries: 3, offset: 6, size: 4, want: "gh", wantErr: io.EOF, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &flakyReaderAt{data: []byte("abcdefgh"), failures: tt.failures}
+			buf := make([]byte, tt.size)
+
+			n, err := readAtWithRetry(r, "/mnt/nfs/file.mkv", buf, tt.offset, tt.retries)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readAtWithRetry() error = %v, want %v", err, tt.wantErr)
+			}
+			if r.calls != tt.wantCalls {
+				t.Errorf("ReadAt called %d times, want %d", r.calls, tt.wantCalls)
+			}
+			if tt.want != "" && string(buf[:n]) != tt.want {
+				t.Errorf("read %q, want %q", buf[:n], tt.want)
+			}
+			if errors.Is(tt.wantErr, syscall.EIO) && !strings.Contains(err.Error(), "/mnt/nfs/file.mkv at offset") {
+				t.Errorf("error should include path and offset, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestReadAtWithRetry_DebugLog(t *testing.T) {
+	oldDelay := readRetryDelay
+	readRetryDelay = time.Millisecond
+	t.Cleanup(func() { readRetryDelay = oldDelay })
+
+	var logged bytes.Buffer
+	SetDebugLog(&logged)
+	t.Cleanup(func() { SetDebugLog(nil) })
+
+	r := &flakyReaderAt{data: []byte("abcdefgh"), failures: 1}
+	if _, err := readAtWithRetry(r, "/mnt/nfs/file.mkv", make([]byte, 2), 4, 3); err != nil {
+		t.Fatalf("readAtWithRetry() error = %v", err)
+	}
+	if !strings.Contains(logged.String(), "/mnt/nfs/file.mkv at offset 4 failed (attempt 1 of 4)") {
+		t.Errorf("debug log = %q, want the failed attempt", logged.String())
+	}
+}
@@ -1,6 +1,7 @@
 package torrent
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -10,13 +11,58 @@ import (
 )
 
 type SeasonPackInfo struct {
-	Episodes        []int
-	MissingEpisodes []int
-	Season          int
-	MaxEpisode      int
-	VideoFileCount  int
-	IsSeasonPack    bool
-	IsSuspicious    bool
+	ExpectedEpisodes *EpisodeRange
+	DetectedPattern  string // text that identified the season, e.g. "S01" or "Season 2"
+	Episodes         []int
+	MissingEpisodes  []int
+	Season           int
+	MaxEpisode       int
+	VideoFileCount   int
+	IsSeasonPack     bool
+	IsSuspicious     bool
+}
+
+// EpisodeRange is an inclusive range of expected episode numbers
+type EpisodeRange struct {
+	First int
+	Last  int
+}
+
+// SeasonPackOptions tunes season pack analysis
+type SeasonPackOptions struct {
+	// ExpectedEpisodes, when set, is compared against instead of inferring
+	// the expected episodes from the highest episode number found
+	ExpectedEpisodes *EpisodeRange
+}
+
+// ParseEpisodeRange parses an expected episode count ("10") or an inclusive range ("3-12")
+func ParseEpisodeRange(s string) (*EpisodeRange, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("episode range cannot be empty")
+	}
+
+	first, last := 1, 0
+	if before, after, found := strings.Cut(s, "-"); found {
+		var err error
+		if first, err = strconv.Atoi(strings.TrimSpace(before)); err != nil {
+			return nil, fmt.Errorf("invalid episode range %q: %w", s, err)
+		}
+		if last, err = strconv.Atoi(strings.TrimSpace(after)); err != nil {
+			return nil, fmt.Errorf("invalid episode range %q: %w", s, err)
+		}
+	} else {
+		var err error
+		if last, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("invalid episode count %q: %w", s, err)
+		}
+	}
+
+	if first < 1 || last < first {
+		return nil, fmt.Errorf("invalid episode range %q: episodes must be positive and in ascending order", s)
+	}
+
+	return &EpisodeRange{First: first, Last: last}, nil
 }
 
 var seasonPackPatterns = []*regexp.Regexp{
@@ -32,25 +78,37 @@ var seasonPackPatterns = []*regexp.Regexp{
 }
 
 var episodePattern = regexp.MustCompile(`(?i)S\d{1,2}E(\d{1,3})`)
-var multiEpisodePattern = regexp.MustCompile(`(?i)S\d{1,2}E(\d{1,3})(?:-E?|E)(\d{1,3})`)
+
+// multiEpisodePattern matches chained (S01E01E02E03) and ranged (S01E01-E03, S01E01-03) episodes
+var multiEpisodePattern = regexp.MustCompile(`(?i)S\d{1,2}(E\d{1,3}(?:(?:-E?|E)\d{1,3})+)`)
+var episodeNumberPattern = regexp.MustCompile(`\d{1,3}`)
 
 var videoExtensions = map[string]bool{
 	".mkv": true,
 	".mp4": true,
 }
 
+// AnalyzeSeasonPack checks files for an incomplete season pack using default options
 func AnalyzeSeasonPack(files []fileEntry) *SeasonPackInfo {
+	return AnalyzeSeasonPackWithOptions(files, SeasonPackOptions{})
+}
+
+// AnalyzeSeasonPackWithOptions checks files for an incomplete season pack.
+// If opts.ExpectedEpisodes is set, missing episodes are computed against that
+// range rather than against the highest episode number found.
+func AnalyzeSeasonPackWithOptions(files []fileEntry, opts SeasonPackOptions) *SeasonPackInfo {
 	if len(files) == 0 {
 		return &SeasonPackInfo{IsSeasonPack: false}
 	}
 
 	dirPath := filepath.Dir(files[0].path)
-	season := detectSeasonNumber(dirPath)
+	season, pattern := detectSeason(dirPath)
 
 	if season == 0 && len(files) > 1 {
 		for i := 0; i < min(5, len(files)); i++ {
 			if s, _ := extractSeasonEpisode(filepath.Base(files[i].path)); s > 0 {
 				season = s
+				pattern = episodePattern.FindString(filepath.Base(files[i].path))
 				break
 			}
 		}
@@ -61,9 +119,11 @@ func AnalyzeSeasonPack(files []fileEntry) *SeasonPackInfo {
 	}
 
 	info := &SeasonPackInfo{
-		IsSeasonPack: false, // Will be set to true only if multiple episodes found
-		Season:       season,
-		Episodes:     make([]int, 0),
+		IsSeasonPack:     false, // Will be set to true only if multiple episodes found
+		Season:           season,
+		DetectedPattern:  pattern,
+		ExpectedEpisodes: opts.ExpectedEpisodes,
+		Episodes:         make([]int, 0),
 	}
 
 	episodeMap := make(map[int]bool)
@@ -102,12 +162,21 @@ func AnalyzeSeasonPack(files []fileEntry) *SeasonPackInfo {
 	}
 	sort.Ints(info.Episodes)
 
-	// Only consider it a season pack if we have multiple episodes
-	if len(info.Episodes) > 1 {
+	// Only consider it a season pack if we have multiple episodes,
+	// or any episode at all when the expected episodes are declared
+	if len(info.Episodes) > 1 || (opts.ExpectedEpisodes != nil && len(info.Episodes) > 0) {
 		info.IsSeasonPack = true
 	}
 
-	if info.MaxEpisode > 0 && info.IsSeasonPack {
+	if opts.ExpectedEpisodes != nil && info.IsSeasonPack {
+		info.MissingEpisodes = []int{}
+		for i := opts.ExpectedEpisodes.First; i <= opts.ExpectedEpisodes.Last; i++ {
+			if !episodeMap[i] {
+				info.MissingEpisodes = append(info.MissingEpisodes, i)
+			}
+		}
+		info.IsSuspicious = len(info.MissingEpisodes) > 0
+	} else if info.MaxEpisode > 0 && info.IsSeasonPack {
 		episodeCount := len(info.Episodes)
 
 		expectedEpisodes := info.MaxEpisode
@@ -128,15 +197,21 @@ func AnalyzeSeasonPack(files []fileEntry) *SeasonPackInfo {
 }
 
 func detectSeasonNumber(path string) int {
+	season, _ := detectSeason(path)
+	return season
+}
+
+// detectSeason returns the season number found in a path and the text that matched
+func detectSeason(path string) (int, string) {
 	for _, pattern := range seasonPackPatterns {
-		matches := pattern.FindStringSubmatch(path)
-		if len(matches) > 1 {
-			if season, err := strconv.Atoi(matches[1]); err == nil {
-				return season
+		loc := pattern.FindStringSubmatchIndex(path)
+		if len(loc) > 3 {
+			if season, err := strconv.Atoi(path[loc[2]:loc[3]]); err == nil {
+				return season, strings.TrimLeft(path[loc[0]:loc[3]], `./\\-_ `)
 			}
 		}
 	}
-	return 0
+	return 0, ""
 }
 
 func extractSeasonEpisode(filename string) (season, episode int) {
@@ -154,22 +229,47 @@ func extractSeasonEpisode(filename string) (season, episode int) {
 	return season, episode
 }
 
+// formatEpisodeList formats episode numbers as a comma separated list, e.g. "3, 5, 6"
+func formatEpisodeList(episodes []int) string {
+	strs := make([]string, len(episodes))
+	for i, ep := range episodes {
+		strs[i] = strconv.Itoa(ep)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// extractMultiEpisodes returns all episodes in a multi-episode filename.
+// Ranges (E01-E03, E01-03) expand to every episode in between,
+// chains (E01E02E03) return only the listed episodes.
 func extractMultiEpisodes(filename string) []int {
 	episodes := []int{}
 
 	matches := multiEpisodePattern.FindStringSubmatch(filename)
+	if len(matches) < 2 {
+		return episodes
+	}
+
+	var numbers []int
+	for _, n := range episodeNumberPattern.FindAllString(matches[1], -1) {
+		if ep, err := strconv.Atoi(n); err == nil {
+			numbers = append(numbers, ep)
+		}
+	}
+	if len(numbers) < 2 {
+		return episodes
+	}
 
-	if len(matches) > 2 {
-		start, err1 := strconv.Atoi(matches[1])
-		end, err2 := strconv.Atoi(matches[2])
-		if err1 == nil && err2 == nil && end >= start {
+	if strings.Contains(matches[1], "-") {
+		start, end := numbers[0], numbers[len(numbers)-1]
+		if end >= start {
 			for i := start; i <= end; i++ {
 				episodes = append(episodes, i)
 			}
 		}
+		return episodes
 	}
 
-	return episodes
+	return append(episodes, numbers...)
 }
 
 // AnalyzeSeasonPackFromPath analyzes a path for season pack completeness.
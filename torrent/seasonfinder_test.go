@@ -87,6 +87,10 @@ func TestMultipleEpisodes(t *testing.T) {
 	}{
 		{"Show.S01E01E02.mkv", []int{1, 2}},
 		{"Show.S01E05-E07.mkv", []int{5, 6, 7}},
+		{"Show.S01E05-07.mkv", []int{5, 6, 7}},
+		{"Show.S01E01E02E03.mkv", []int{1, 2, 3}},
+		{"Show.S01E01E03.mkv", []int{1, 3}},
+		{"Show.S01E03.mkv", []int{}},
 	}
 
 	for _, tc := range tests {
@@ -132,3 +136,55 @@ func TestAnalyzeSeasonPack_SingleEpisode(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEpisodeRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *EpisodeRange
+		wantErr  bool
+	}{
+		{input: "10", expected: &EpisodeRange{First: 1, Last: 10}},
+		{input: "3-12", expected: &EpisodeRange{First: 3, Last: 12}},
+		{input: " 5 - 8 ", expected: &EpisodeRange{First: 5, Last: 8}},
+		{input: "", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "12-3", wantErr: true},
+		{input: "abc", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseEpisodeRange(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAnalyzeSeasonPackWithOptions_ExpectedEpisodes(t *testing.T) {
+	files := []fileEntry{
+		{path: filepath.Join("/test", "Show.S01.1080p", "Show.S01E01.mkv")},
+		{path: filepath.Join("/test", "Show.S01.1080p", "Show.S01E02.mkv")},
+		{path: filepath.Join("/test", "Show.S01.1080p", "Show.S01E03.mkv")},
+	}
+
+	// without an override the pack looks complete
+	info := AnalyzeSeasonPack(files)
+	assert.False(t, info.IsSuspicious)
+	assert.Equal(t, "S01", info.DetectedPattern)
+
+	info = AnalyzeSeasonPackWithOptions(files, SeasonPackOptions{ExpectedEpisodes: &EpisodeRange{First: 1, Last: 5}})
+	assert.True(t, info.IsSeasonPack)
+	assert.True(t, info.IsSuspicious)
+	assert.Equal(t, []int{4, 5}, info.MissingEpisodes)
+
+	// a pack covering part of a season is complete when the range matches
+	info = AnalyzeSeasonPackWithOptions(files[1:], SeasonPackOptions{ExpectedEpisodes: &EpisodeRange{First: 2, Last: 3}})
+	assert.True(t, info.IsSeasonPack)
+	assert.False(t, info.IsSuspicious)
+	assert.Empty(t, info.MissingEpisodes)
+}
@@ -0,0 +1,232 @@
+package torrent
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourceSpec is a file or directory on disk and the path it is placed at inside
+// the torrent, see CreateOptions.Sources
+type SourceSpec struct {
+	Path        string
+	TorrentPath string // slash separated, defaults to the base name of Path
+}
+
+// ParseSourceSpec parses a source given as "path" or "path=in/torrent/path".
+// A value that exists on disk as given is never split, so paths containing '='
+// still work without a torrent path.
+func ParseSourceSpec(s string) (SourceSpec, error) {
+	spec := SourceSpec{Path: s}
+	if _, err := os.Stat(s); err != nil {
+		if src, dest, ok := strings.Cut(s, "="); ok {
+			spec = SourceSpec{Path: src, TorrentPath: dest}
+		}
+	}
+	if spec.Path == "" {
+		return SourceSpec{}, fmt.Errorf("invalid source %q: path is empty", s)
+	}
+	if _, err := cleanTorrentPath(spec); err != nil {
+		return SourceSpec{}, err
+	}
+	return spec, nil
+}
+
+// cleanTorrentPath returns the slash separated in-torrent path of a source,
+// rejecting paths that would escape the torrent root
+func cleanTorrentPath(spec SourceSpec) (string, error) {
+	p := spec.TorrentPath
+	if p == "" {
+		p = filepath.Base(filepath.Clean(spec.Path))
+	}
+	p = strings.ReplaceAll(p, "\\", "/")
+	if strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("invalid torrent path %q for %q: must be relative", p, spec.Path)
+	}
+	p = path.Clean(p)
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("invalid torrent path %q for %q: must stay inside the torrent", spec.TorrentPath, spec.Path)
+	}
+	return p, nil
+}
+
+// sourceFiles walks each source and lays its files out under the source's torrent
+// path, returning the files sorted by torrent path and a map from each file's path
+// on disk to its path in the torrent. The filter's patterns match the path in the
+// torrent. Two files at the same torrent path, a torrent path that is both
+// a file and a directory, or the same file added twice are errors. Files that
+// can't be stat'ed are skipped with a warning.
+func sourceFiles(sources []SourceSpec, filter *pathFilter) ([]fileEntry, map[string]string, error) {
+	var files []fileEntry
+	torrentPaths := make(map[string]string) // disk path -> torrent path
+
+	add := func(diskPath, torrentPath string, info os.FileInfo) error {
+		if prev, ok := torrentPaths[diskPath]; ok {
+			return fmt.Errorf("%q is added more than once, as %q and %q", diskPath, prev, torrentPath)
+		}
+		torrentPaths[diskPath] = torrentPath
+		files = append(files, fileEntry{
+			path:    diskPath,
+			length:  info.Size(),
+			modTime: info.ModTime(),
+		})
+		return nil
+	}
+
+	for _, spec := range sources {
+		root, err := cleanTorrentPath(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		srcPath := filepath.Clean(spec.Path)
+		srcInfo, err := os.Stat(srcPath)
+		if err != nil {
+			return nil, nil, notFound(fmt.Errorf("error checking source %q: %w", spec.Path, err))
+		}
+
+		if !srcInfo.IsDir() {
+			skip, err := filter.ignores(root, false)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error processing file patterns for %q: %w", srcPath, err)
+			}
+			if !skip {
+				if err := add(srcPath, root, srcInfo); err != nil {
+					return nil, nil, err
+				}
+			}
+			continue
+		}
+
+		err = filepath.Walk(srcPath, func(currentPath string, walkInfo os.FileInfo, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			rel, err := filepath.Rel(srcPath, currentPath)
+			if err != nil {
+				return fmt.Errorf("error calculating relative path for %q: %w", currentPath, err)
+			}
+			torrentPath := path.Join(root, filepath.ToSlash(rel))
+
+			if walkInfo.IsDir() {
+				if shouldIgnoreDir(currentPath) {
+					return filepath.SkipDir
+				}
+				if rel != "." {
+					skip, err := filter.ignores(torrentPath, true)
+					if err != nil {
+						return fmt.Errorf("error processing directory patterns for %q: %w", currentPath, err)
+					}
+					if skip {
+						return filepath.SkipDir
+					}
+				}
+				return nil
+			}
+
+			// follow links to files, links to directories are skipped like in a regular walk
+			info, err := os.Stat(currentPath)
+			if err != nil {
+				showWarning(fmt.Sprintf("could not stat %q: %v", currentPath, err))
+				return nil
+			}
+			if info.IsDir() {
+				return nil
+			}
+			skip, err := filter.ignores(torrentPath, false)
+			if err != nil {
+				return fmt.Errorf("error processing file patterns for %q: %w", currentPath, err)
+			}
+			if skip {
+				return nil
+			}
+			return add(currentPath, torrentPath, info)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := checkTorrentPaths(torrentPaths); err != nil {
+		return nil, nil, err
+	}
+	sortByTorrentPath(files, torrentPaths)
+
+	return files, torrentPaths, nil
+}
+
+// checkTorrentPaths fails if two files map to the same torrent path, or a file's
+// torrent path is also a directory holding other files
+func checkTorrentPaths(torrentPaths map[string]string) error {
+	diskPaths := make(map[string]string, len(torrentPaths)) // torrent path -> disk path
+	diskOrder := make([]string, 0, len(torrentPaths))
+	for diskPath := range torrentPaths {
+		diskOrder = append(diskOrder, diskPath)
+	}
+	sort.Strings(diskOrder) // stable error messages
+
+	for _, diskPath := range diskOrder {
+		torrentPath := torrentPaths[diskPath]
+		if prev, ok := diskPaths[torrentPath]; ok {
+			return fmt.Errorf("torrent path collision: %q and %q both map to %q", prev, diskPath, torrentPath)
+		}
+		diskPaths[torrentPath] = diskPath
+	}
+	for _, diskPath := range diskOrder {
+		for dir := path.Dir(torrentPaths[diskPath]); dir != "."; dir = path.Dir(dir) {
+			if other, ok := diskPaths[dir]; ok {
+				return fmt.Errorf("torrent path collision: %q is a file from %q but also a directory holding %q", dir, other, diskPath)
+			}
+		}
+	}
+	return nil
+}
+
+// sortByTorrentPath orders files by their torrent path and lays out their offsets
+func sortByTorrentPath(files []fileEntry, torrentPaths map[string]string) {
+	sort.Slice(files, func(i, j int) bool {
+		return torrentPaths[files[i].path] < torrentPaths[files[j].path]
+	})
+	var offset int64
+	for i := range files {
+		files[i].offset = offset
+		offset += files[i].length
+	}
+}
+
+// depthRoot returns the directory that becomes the torrent root when depth parent
+// directories of p are kept, see CreateOptions.PathDepth, and the slash separated
+// path of p below it
+func depthRoot(p string, depth int) (string, string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", "", fmt.Errorf("error resolving %q: %w", p, err)
+	}
+	root := abs
+	for range depth {
+		root = filepath.Dir(root)
+	}
+	if filepath.Dir(root) == root {
+		return "", "", fmt.Errorf("path depth %d goes up to the filesystem root from %q", depth, p)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", "", fmt.Errorf("error calculating relative path for %q: %w", p, err)
+	}
+	return root, filepath.ToSlash(rel), nil
+}
+
+// stripTorrentPaths removes the first n directories from each torrent path. Every
+// file must be nested at least n directories deep.
+func stripTorrentPaths(torrentPaths map[string]string, n int) error {
+	for diskPath, torrentPath := range torrentPaths {
+		parts := strings.SplitN(torrentPath, "/", n+1)
+		if len(parts) <= n {
+			return fmt.Errorf("cannot strip %d leading directories from %q, it is not nested that deep", n, torrentPath)
+		}
+		torrentPaths[diskPath] = parts[n]
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSourceSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	withEquals := filepath.Join(tmpDir, "a=b.txt")
+	if err := os.WriteFile(withEquals, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in      string
+		want    SourceSpec
+		wantErr bool
+	}{
+		{in: "/a/file1", want: SourceSpec{Path: "/a/file1"}},
+		{in: "/b/dir2=extras/dir", want: SourceSpec{Path: "/b/dir2", TorrentPath: "extras/dir"}},
+		{in: withEquals, want: SourceSpec{Path: withEquals}},
+		{in: "=name", wantErr: true},
+		{in: "/a/file1=../escape", wantErr: true},
+		{in: "/a/file1=/abs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSourceSpec(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSourceSpec(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSourceSpec(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSourceSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_Sources(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(rel string, size int) {
+		path := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(len(filepath.Base(rel)) + i)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// scattered sources, and the same layout staged in one directory
+	write("a/file1.mkv", 100<<10)
+	write("b/dir2/one.srt", 3<<10)
+	write("b/dir2/two.srt", 5<<10)
+	write("b/dir2/skip.nfo", 1<<10)
+	write("staged/Pack/file1.mkv", 100<<10)
+	write("staged/Pack/Subs/one.srt", 3<<10)
+	write("staged/Pack/Subs/two.srt", 5<<10)
+
+	pieceExp := uint(16)
+	base := CreateOptions{
+		Name:            "Pack",
+		TrackerURLs:     []string{"https://tracker.example.com/announce"},
+		ExcludePatterns: []string{"*.nfo"},
+		PieceLengthExp:  &pieceExp,
+		IsPrivate:       true,
+		NoDate:          true,
+		Quiet:           true,
+	}
+
+	opts := base
+	opts.Sources = []SourceSpec{
+		{Path: filepath.Join(tmpDir, "b", "dir2"), TorrentPath: "Subs"},
+		{Path: filepath.Join(tmpDir, "a", "file1.mkv")},
+	}
+	combined, err := CreateTorrent(opts)
+	if err != nil {
+		t.Fatalf("CreateTorrent() with sources error = %v", err)
+	}
+
+	info := combined.GetInfo()
+	var paths []string
+	for _, f := range info.Files {
+		paths = append(paths, strings.Join(f.Path, "/"))
+	}
+	if got, want := strings.Join(paths, ","), "Subs/one.srt,Subs/two.srt,file1.mkv"; got != want {
+		t.Errorf("file paths = %s, want %s", got, want)
+	}
+
+	staged := base
+	staged.Path = filepath.Join(tmpDir, "staged", "Pack")
+	want, err := CreateTorrent(staged)
+	if err != nil {
+		t.Fatalf("CreateTorrent() with staged directory error = %v", err)
+	}
+	if combined.HashInfoBytes() != want.HashInfoBytes() {
+		t.Errorf("info hash = %s, want %s as for the staged directory", combined.HashInfoBytes(), want.HashInfoBytes())
+	}
+}
+
+func TestCreateTorrent_SourcesCollision(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"a/file.bin", "b/file.bin", "c/dir/x.bin"} {
+		path := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		sources []SourceSpec
+		wantErr string
+	}{
+		{
+			name: "same name",
+			sources: []SourceSpec{
+				{Path: filepath.Join(tmpDir, "a", "file.bin")},
+				{Path: filepath.Join(tmpDir, "b", "file.bin")},
+			},
+			wantErr: "collision",
+		},
+		{
+			name: "file over directory",
+			sources: []SourceSpec{
+				{Path: filepath.Join(tmpDir, "a", "file.bin"), TorrentPath: "dir"},
+				{Path: filepath.Join(tmpDir, "c", "dir")},
+			},
+			wantErr: "also a directory",
+		},
+		{
+			name: "same file twice",
+			sources: []SourceSpec{
+				{Path: filepath.Join(tmpDir, "a", "file.bin")},
+				{Path: filepath.Join(tmpDir, "a", "file.bin"), TorrentPath: "copy.bin"},
+			},
+			wantErr: "more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CreateTorrent(CreateOptions{
+				Name:        "Pack",
+				Sources:     tt.sources,
+				TrackerURLs: []string{"https://tracker.example.com/announce"},
+				IsPrivate:   true,
+				Quiet:       true,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("CreateTorrent() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateTorrent_PathDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"data/Show/Season 1/e01.mkv", "data/Show/Season 1/e02.mkv", "data/Show/Season 2/e01.mkv", "data/Movie/m.mkv"} {
+		path := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	show := filepath.Join(tmpDir, "data", "Show")
+
+	layout := func(tor *Torrent) string {
+		info := tor.GetInfo()
+		paths := []string{info.Name}
+		for _, f := range info.Files {
+			paths = append(paths, strings.Join(f.Path, "/"))
+		}
+		return strings.Join(paths, ",")
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		depth   int
+		want    string
+		wantErr string
+	}{
+		{name: "default", path: show, want: "Show,Season 1/e01.mkv,Season 1/e02.mkv,Season 2/e01.mkv"},
+		{name: "keep parent", path: show, depth: 1, want: "data,Show/Season 1/e01.mkv,Show/Season 1/e02.mkv,Show/Season 2/e01.mkv"},
+		{name: "keep parent of file", path: filepath.Join(tmpDir, "data", "Movie", "m.mkv"), depth: 1, want: "Movie,m.mkv"},
+		{name: "subdirectory", path: filepath.Join(show, "Season 1"), want: "Season 1,e01.mkv,e02.mkv"},
+		{name: "strip collision", path: show, depth: -1, wantErr: "collision"},
+		{name: "strip too deep", path: show, depth: -2, wantErr: "not nested that deep"},
+		{name: "strip single file", path: filepath.Join(tmpDir, "data", "Movie", "m.mkv"), depth: -1, wantErr: "single file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tor, err := CreateTorrent(CreateOptions{
+				Path:        tt.path,
+				PathDepth:   tt.depth,
+				TrackerURLs: []string{"https://tracker.example.com/announce"},
+				IsPrivate:   true,
+				Quiet:       true,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateTorrent() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateTorrent() error = %v", err)
+			}
+			if got := layout(tor); got != tt.want {
+				t.Errorf("layout = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// stripping a level flattens the season folders when their names don't collide
+	if err := os.Rename(filepath.Join(show, "Season 2", "e01.mkv"), filepath.Join(show, "Season 2", "e03.mkv")); err != nil {
+		t.Fatal(err)
+	}
+	tor, err := CreateTorrent(CreateOptions{
+		Path:        show,
+		PathDepth:   -1,
+		TrackerURLs: []string{"https://tracker.example.com/announce"},
+		IsPrivate:   true,
+		Quiet:       true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() with depth -1 error = %v", err)
+	}
+	if got, want := layout(tor), "Show,e01.mkv,e02.mkv,e03.mkv"; got != want {
+		t.Errorf("layout = %s, want %s", got, want)
+	}
+}
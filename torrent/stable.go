@@ -0,0 +1,103 @@
+package torrent
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// changedFiles re-stats the walked files and returns the ones whose size or
+// modification time no longer match, i.e. files written to while they were hashed.
+// Paths are reported as originally walked, using displayPaths where available.
+func changedFiles(files []fileEntry, displayPaths map[string]string) []string {
+	var changed []string
+	for _, f := range files {
+		if f.padding {
+			continue
+		}
+
+		name := f.path
+		if p, ok := displayPaths[f.path]; ok {
+			name = p
+		}
+
+		info, err := os.Stat(f.path)
+		switch {
+		case err != nil:
+			changed = append(changed, fmt.Sprintf("%s (%v)", name, err))
+		case info.Size() != f.length:
+			changed = append(changed, fmt.Sprintf("%s (size %d -> %d)", name, f.length, info.Size()))
+		case !info.ModTime().Equal(f.modTime):
+			changed = append(changed, fmt.Sprintf("%s (modified)", name))
+		}
+	}
+	return changed
+}
+
+// fileState is the size and modification time of a file, see contentState. The time
+// is kept in nanoseconds so states compare by value, not by monotonic clock or location.
+type fileState struct {
+	size    int64
+	modTime int64
+}
+
+// contentState stats every file under the given paths, following links to files
+func contentState(paths []string) (map[string]fileState, error) {
+	state := make(map[string]fileState)
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := os.Stat(p)
+			if err != nil || info.IsDir() {
+				return nil // broken links and links to directories are skipped by the walk too
+			}
+			state[p] = fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+			return nil
+		})
+		if err != nil {
+			return nil, notFound(fmt.Errorf("error checking path: %w", err))
+		}
+	}
+	return state, nil
+}
+
+// waitStable blocks until no file under paths was added, removed or changed in
+// size or modification time for the stable duration, so content that is still
+// being written is not hashed. When ctx is done it stops waiting and returns an
+// error matching ErrCancelled.
+func waitStable(ctx context.Context, paths []string, stable time.Duration) error {
+	interval := min(time.Second, max(stable/4, 10*time.Millisecond))
+
+	prev, err := contentState(paths)
+	if err != nil {
+		return err
+	}
+	stableSince := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for time.Since(stableSince) < stable {
+		select {
+		case <-ctx.Done():
+			return ctxErr(ctx)
+		case <-ticker.C:
+		}
+		cur, err := contentState(paths)
+		if err != nil {
+			return err
+		}
+		if !maps.Equal(prev, cur) {
+			prev = cur
+			stableSince = time.Now()
+		}
+	}
+	return nil
+}
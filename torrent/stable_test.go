@@ -0,0 +1,157 @@
+package torrent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) fileEntry {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", name, err)
+		}
+		return fileEntry{path: path, length: info.Size(), modTime: info.ModTime()}
+	}
+
+	stable := write("stable.bin", "unchanged")
+	grown := write("grown.bin", "short")
+	touched := write("touched.bin", "same size")
+	removed := write("removed.bin", "gone")
+	files := []fileEntry{stable, grown, touched, removed, {length: 3, padding: true}}
+
+	if changed := changedFiles(files, nil); len(changed) != 0 {
+		t.Fatalf("changedFiles() before changes = %v, want none", changed)
+	}
+
+	if err := os.WriteFile(grown.path, []byte("much longer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := touched.modTime.Add(time.Minute)
+	if err := os.Chtimes(touched.path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(removed.path); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := changedFiles(files, map[string]string{grown.path: "content/grown.bin"})
+	if len(changed) != 3 {
+		t.Fatalf("changedFiles() = %v, want 3 entries", changed)
+	}
+	if !strings.HasPrefix(changed[0], "content/grown.bin (size 5 -> 11)") {
+		t.Errorf("changed[0] = %q, want the display path and size change", changed[0])
+	}
+	if !strings.HasSuffix(changed[1], "touched.bin (modified)") {
+		t.Errorf("changed[1] = %q, want touched.bin reported as modified", changed[1])
+	}
+	if !strings.Contains(changed[2], "removed.bin") {
+		t.Errorf("changed[2] = %q, want removed.bin", changed[2])
+	}
+}
+
+func TestCreateTorrent_VerifyStable(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.bin")
+	if err := os.WriteFile(path, make([]byte, 64<<10), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pieceLength := uint(16)
+	if _, err := CreateTorrent(CreateOptions{Path: path, PieceLengthExp: &pieceLength, Quiet: true, VerifyStable: true}); err != nil {
+		t.Fatalf("CreateTorrent() on unchanged content failed: %v", err)
+	}
+
+	// a progress callback runs while hashing, so touching the file there simulates a live write
+	touched := false
+	_, err := CreateTorrent(CreateOptions{
+		Path:           path,
+		PieceLengthExp: &pieceLength,
+		VerifyStable:   true,
+		ProgressCallback: func(completed, total int, _ float64) {
+			if !touched {
+				touched = true
+				later := time.Now().Add(time.Hour)
+				_ = os.Chtimes(path, later, later)
+			}
+		},
+	})
+	if !errors.Is(err, ErrContentChanged) {
+		t.Fatalf("CreateTorrent() error = %v, want ErrContentChanged", err)
+	}
+	if !strings.Contains(err.Error(), "file.bin (modified)") {
+		t.Errorf("error %q does not name the changed file", err)
+	}
+}
+
+func TestCreateTorrent_WaitStable(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "capture.ts")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keep appending for a while, as a recording still in progress would
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer f.Close()
+		chunk := make([]byte, 16<<10)
+		for range 8 {
+			if _, err := f.Write(chunk); err != nil {
+				return
+			}
+			time.Sleep(40 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+	tor, err := CreateTorrent(CreateOptions{
+		Path:        path,
+		TrackerURLs: []string{"https://tracker.example.com/announce"},
+		IsPrivate:   true,
+		Quiet:       true,
+		WaitStable:  300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("CreateTorrent() error = %v", err)
+	}
+	<-done
+
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("CreateTorrent() returned after %s, before the file stopped growing", elapsed)
+	}
+	if got, want := tor.GetInfo().TotalLength(), int64(8*16<<10); got != want {
+		t.Errorf("torrent length = %d, want the final size %d", got, want)
+	}
+}
+
+func TestWaitStable_Cancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := waitStable(ctx, []string{path}, time.Minute)
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waitStable() error = %v, want ErrCancelled and context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waitStable() returned after %s, long after the context was done", elapsed)
+	}
+}
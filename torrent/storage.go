@@ -0,0 +1,70 @@
+package torrent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StorageType describes the storage the content is read from, used to tune the
+// number of hashing workers and the read size
+type StorageType string
+
+const (
+	// StorageAuto detects the storage type from the content path (default)
+	StorageAuto StorageType = "auto"
+	// StorageSSD reads with one or more workers per CPU, suited to SSDs and NVMe drives
+	StorageSSD StorageType = "ssd"
+	// StorageHDD reads with a single sequential worker, as parallel reads make a spinning disk seek
+	StorageHDD StorageType = "hdd"
+	// StorageNetwork reads with a few workers and large reads to amortize round trips on NFS/SMB mounts
+	StorageNetwork StorageType = "network"
+)
+
+// maxNetworkWorkers caps the workers for network storage, where more parallel reads
+// mostly add contention on the server and link
+const maxNetworkWorkers = 4
+
+// ParseStorageType parses a storage type as used by --storage
+func ParseStorageType(s string) (StorageType, error) {
+	switch StorageType(strings.ToLower(strings.TrimSpace(s))) {
+	case "", StorageAuto:
+		return StorageAuto, nil
+	case StorageSSD:
+		return StorageSSD, nil
+	case StorageHDD:
+		return StorageHDD, nil
+	case StorageNetwork:
+		return StorageNetwork, nil
+	default:
+		return StorageAuto, fmt.Errorf("invalid storage type %q: must be one of auto, ssd, hdd, network", s)
+	}
+}
+
+// resolveStorage returns storage, or the detected storage type of path for StorageAuto.
+// When detection isn't possible StorageSSD is assumed, which keeps the CPU based defaults.
+func resolveStorage(storage StorageType, path string) StorageType {
+	if storage != "" && storage != StorageAuto {
+		debugf("storage: using %s as given", storage)
+		return storage
+	}
+	if detected, ok := detectStorage(path); ok {
+		debugf("storage: detected %s for %s", detected, path)
+		return detected
+	}
+	debugf("storage: could not detect the storage of %s, assuming %s", path, StorageSSD)
+	return StorageSSD
+}
+
+// tuneForStorage adjusts the read size and worker count picked by optimizeForWorkload
+// for the storage the content is read from
+func tuneForStorage(storage StorageType, readSize, numWorkers int) (int, int) {
+	switch storage {
+	case StorageHDD:
+		// one sequential reader avoids seek thrashing between files and pieces
+		return max(readSize, 8<<20), min(numWorkers, 1)
+	case StorageNetwork:
+		return max(readSize, 8<<20), min(numWorkers, maxNetworkWorkers)
+	default:
+		return readSize, numWorkers
+	}
+}
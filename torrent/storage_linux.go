@@ -0,0 +1,134 @@
+package torrent
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// networkFilesystems are mountinfo filesystem types backed by network storage
+var networkFilesystems = map[string]bool{
+	"nfs":         true,
+	"nfs4":        true,
+	"cifs":        true,
+	"smb3":        true,
+	"smbfs":       true,
+	"9p":          true,
+	"afs":         true,
+	"ceph":        true,
+	"glusterfs":   true,
+	"fuse.sshfs":  true,
+	"fuse.rclone": true,
+}
+
+// detectStorage looks up the mount holding path in /proc/self/mountinfo. Network
+// filesystems are reported as StorageNetwork; for block devices the rotational flag
+// in sysfs tells HDDs from SSDs.
+func detectStorage(path string) (StorageType, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	fsType, device, ok := findMount(f, absPath)
+	if !ok {
+		return "", false
+	}
+	if networkFilesystems[fsType] {
+		return StorageNetwork, true
+	}
+
+	// virtual disks (virtio, xen) report rotational regardless of what backs them
+	sysPath, err := filepath.EvalSymlinks(filepath.Join("/sys/dev/block", device))
+	if err != nil || isVirtualDisk(sysPath) {
+		return "", false
+	}
+
+	// partitions have no queue of their own, their parent disk does
+	for _, queue := range []string{"queue/rotational", "../queue/rotational"} {
+		data, err := os.ReadFile(filepath.Join(sysPath, queue))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(data)) == "1" {
+			return StorageHDD, true
+		}
+		return StorageSSD, true
+	}
+	return "", false
+}
+
+// findMount returns the filesystem type and major:minor device number of the
+// mount in mountinfo r with the longest mount point containing path
+func findMount(r io.Reader, path string) (fsType, device string, ok bool) {
+	best := -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// e.g. "36 35 8:1 / /mnt rw,noatime shared:1 - ext4 /dev/sda1 rw"
+		pre, post, found := strings.Cut(scanner.Text(), " - ")
+		if !found {
+			continue
+		}
+		fields, postFields := strings.Fields(pre), strings.Fields(post)
+		if len(fields) < 5 || len(postFields) < 1 {
+			continue
+		}
+
+		mountPoint := unescapeMountPath(fields[4])
+		if !pathWithin(path, mountPoint) || len(mountPoint) < best {
+			continue
+		}
+		best = len(mountPoint)
+		fsType, device, ok = postFields[0], fields[2], true
+	}
+	return fsType, device, ok
+}
+
+// isVirtualDisk reports whether a block device's sysfs path belongs to a virtual disk
+func isVirtualDisk(sysPath string) bool {
+	for _, marker := range []string{"/devices/virtual/", "/virtio", "/xen/", "/vbd-"} {
+		if strings.Contains(sysPath, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathWithin reports whether path is dir or inside it
+func pathWithin(path, dir string) bool {
+	if dir == "/" || path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, dir+"/")
+}
+
+// unescapeMountPath decodes the octal escapes (e.g. "\040" for a space) used in mountinfo
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package torrent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindMount(t *testing.T) {
+	mountinfo := `22 1 8:2 / / rw,relatime shared:1 - ext4 /dev/sda2 rw
+35 22 0:45 / /mnt/media rw,relatime shared:20 - nfs4 nas:/export/media rw
+36 22 259:1 / /mnt/fast\040disk rw,relatime shared:21 - xfs /dev/nvme0n1p1 rw
+37 35 8:17 / /mnt/media/local rw,relatime shared:22 - ext4 /dev/sdb1 rw
+`
+
+	tests := []struct {
+		path       string
+		wantFSType string
+		wantDevice string
+	}{
+		{path: "/home/user/file.mkv", wantFSType: "ext4", wantDevice: "8:2"},
+		{path: "/mnt/media/show/s01e01.mkv", wantFSType: "nfs4", wantDevice: "0:45"},
+		{path: "/mnt/media", wantFSType: "nfs4", wantDevice: "0:45"},
+		{path: "/mnt/mediathek", wantFSType: "ext4", wantDevice: "8:2"},
+		{path: "/mnt/media/local/file", wantFSType: "ext4", wantDevice: "8:17"},
+		{path: "/mnt/fast disk/file", wantFSType: "xfs", wantDevice: "259:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			fsType, device, ok := findMount(strings.NewReader(mountinfo), tt.path)
+			if !ok {
+				t.Fatalf("findMount(%q) found no mount", tt.path)
+			}
+			if fsType != tt.wantFSType || device != tt.wantDevice {
+				t.Errorf("findMount(%q) = (%q, %q), want (%q, %q)", tt.path, fsType, device, tt.wantFSType, tt.wantDevice)
+			}
+		})
+	}
+}
+
+func TestIsVirtualDisk(t *testing.T) {
+	tests := []struct {
+		sysPath string
+		want    bool
+	}{
+		{sysPath: "/sys/devices/pci0000:00/0000:00:17.0/ata1/host0/target0:0:0/0:0:0:0/block/sda/sda1", want: false},
+		{sysPath: "/sys/devices/pci0000:00/0000:00:1d.0/0000:3d:00.0/nvme/nvme0/nvme0n1", want: false},
+		{sysPath: "/sys/devices/pci0000:00/0000:00:02.0/virtio1/block/vda", want: true},
+		{sysPath: "/sys/devices/vbd-51712/block/xvda", want: true},
+		{sysPath: "/sys/devices/virtual/block/loop0", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := isVirtualDisk(tt.sysPath); got != tt.want {
+			t.Errorf("isVirtualDisk(%q) = %v, want %v", tt.sysPath, got, tt.want)
+		}
+	}
+}
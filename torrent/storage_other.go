@@ -0,0 +1,8 @@
+//go:build !linux
+
+package torrent
+
+// detectStorage is only implemented on Linux; elsewhere --storage can be set explicitly
+func detectStorage(path string) (StorageType, bool) {
+	return "", false
+}
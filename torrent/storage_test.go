@@ -0,0 +1,70 @@
+package torrent
+
+import "testing"
+
+func TestParseStorageType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    StorageType
+		wantErr bool
+	}{
+		{input: "", want: StorageAuto},
+		{input: "auto", want: StorageAuto},
+		{input: "SSD", want: StorageSSD},
+		{input: "hdd", want: StorageHDD},
+		{input: " network ", want: StorageNetwork},
+		{input: "tape", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseStorageType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStorageType(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseStorageType(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuneForStorage(t *testing.T) {
+	tests := []struct {
+		storage     StorageType
+		readSize    int
+		workers     int
+		wantSize    int
+		wantWorkers int
+	}{
+		{storage: StorageSSD, readSize: 1 << 20, workers: 16, wantSize: 1 << 20, wantWorkers: 16},
+		{storage: StorageHDD, readSize: 1 << 20, workers: 16, wantSize: 8 << 20, wantWorkers: 1},
+		{storage: StorageNetwork, readSize: 256 << 10, workers: 16, wantSize: 8 << 20, wantWorkers: maxNetworkWorkers},
+		{storage: StorageNetwork, readSize: 64 << 10, workers: 1, wantSize: 8 << 20, wantWorkers: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.storage), func(t *testing.T) {
+			size, workers := tuneForStorage(tt.storage, tt.readSize, tt.workers)
+			if size != tt.wantSize || workers != tt.wantWorkers {
+				t.Errorf("tuneForStorage(%q, %d, %d) = (%d, %d), want (%d, %d)",
+					tt.storage, tt.readSize, tt.workers, size, workers, tt.wantSize, tt.wantWorkers)
+			}
+		})
+	}
+}
+
+func TestPieceHasher_HDDUsesSingleWorker(t *testing.T) {
+	pieceLen := int64(1 << 16)
+	files, expectedHashes := createTestFilesWithPattern(t, t.TempDir(), []int64{100_000, 300_001, 65_536}, pieceLen)
+
+	hasher := NewPieceHasher(files, pieceLen, len(expectedHashes), &mockDisplay{}, false)
+	hasher.storage = StorageHDD
+	if _, workers := hasher.optimizeForWorkload(); workers != 1 {
+		t.Errorf("optimizeForWorkload() workers = %d, want 1 for HDD storage", workers)
+	}
+	if err := hasher.hashPieces(0); err != nil {
+		t.Fatalf("hashPieces() failed: %v", err)
+	}
+	verifyHashes(t, hasher.pieces, expectedHashes)
+}
@@ -0,0 +1,151 @@
+package torrent
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTrackerCheckTimeout is the default time to wait for each tracker in CheckTrackers
+const DefaultTrackerCheckTimeout = 10 * time.Second
+
+// udpProtocolID is the magic constant starting a BEP 15 connect request
+const udpProtocolID = 0x41727101980
+
+// TrackerStatus is the outcome of checking whether a tracker answers, see CheckTracker.
+// Tracker is redacted with RedactTrackerURL so the status can be printed or logged.
+type TrackerStatus struct {
+	Tracker   string `json:"tracker"`
+	Reachable bool   `json:"reachable"`
+	Status    string `json:"status,omitempty"` // HTTP status, or "connected" for a UDP tracker
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// CheckTrackers checks all trackers concurrently and returns their statuses in order
+func CheckTrackers(trackerURLs []string, timeout time.Duration) []TrackerStatus {
+	statuses := make([]TrackerStatus, len(trackerURLs))
+	var wg sync.WaitGroup
+	for i, trackerURL := range trackerURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = CheckTracker(trackerURL, timeout)
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// CheckTracker reports whether the tracker at trackerURL answers within timeout.
+// HTTP(S) trackers get a plain GET of the announce URL, and any response below 500
+// counts, as trackers reject an announce without an info hash with an error body.
+// UDP trackers get a BEP 15 connect request. Nothing is announced for any torrent.
+func CheckTracker(trackerURL string, timeout time.Duration) TrackerStatus {
+	if timeout <= 0 {
+		timeout = DefaultTrackerCheckTimeout
+	}
+	status := TrackerStatus{Tracker: RedactTrackerURL(trackerURL)}
+
+	u, err := url.Parse(trackerURL)
+	if err != nil {
+		status.Error = "invalid tracker URL"
+		return status
+	}
+
+	start := time.Now()
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		err = checkHTTPTracker(trackerURL, timeout, &status)
+	case "udp":
+		err = checkUDPTracker(u.Host, timeout)
+		if err == nil {
+			status.Reachable = true
+			status.Status = "connected"
+		}
+	default:
+		err = fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	status.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// checkHTTPTracker requests the announce URL and records the response status
+func checkHTTPTracker(trackerURL string, timeout time.Duration, status *TrackerStatus) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(trackerURL)
+	if err != nil {
+		// url.Error repeats the full URL, passkey included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	status.Status = resp.Status
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server error %s", resp.Status)
+	}
+	status.Reachable = true
+	return nil
+}
+
+// checkUDPTracker sends a BEP 15 connect request to host and waits for the matching reply
+func checkUDPTracker(host string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("udp", host, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	var req [16]byte
+	binary.BigEndian.PutUint64(req[0:], udpProtocolID)
+	binary.BigEndian.PutUint32(req[8:], 0) // action: connect
+	if _, err := rand.Read(req[12:]); err != nil {
+		return err
+	}
+	if _, err := conn.Write(req[:]); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 512)
+	n, err := conn.Read(resp)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("no response within %s", timeout)
+		}
+		return err
+	}
+	if n < 8 || string(resp[4:8]) != string(req[12:16]) {
+		return fmt.Errorf("unexpected response")
+	}
+	switch action := binary.BigEndian.Uint32(resp[0:4]); action {
+	case 0:
+		if n < 16 {
+			return fmt.Errorf("unexpected response")
+		}
+		return nil
+	case 3:
+		return fmt.Errorf("tracker error: %s", strings.TrimSpace(string(resp[8:n])))
+	default:
+		return fmt.Errorf("unexpected action %d in response", action)
+	}
+}
@@ -0,0 +1,102 @@
+package torrent
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckTracker_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/down/") {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// trackers answer an announce without info_hash with a bencoded failure
+		_, _ = w.Write([]byte("d14:failure reason17:missing info_hashe"))
+	}))
+	defer server.Close()
+
+	const passkey = "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name      string
+		url       string
+		reachable bool
+	}{
+		{name: "answers", url: server.URL + "/announce/" + passkey, reachable: true},
+		{name: "server error", url: server.URL + "/down/announce?passkey=" + passkey, reachable: false},
+		{name: "connection refused", url: "http://127.0.0.1:1/announce?passkey=" + passkey, reachable: false},
+		{name: "unsupported scheme", url: "wss://tracker.example.com/announce", reachable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := CheckTracker(tt.url, 2*time.Second)
+			if status.Reachable != tt.reachable {
+				t.Errorf("CheckTracker(%q) reachable = %v, want %v (error %q)", tt.url, status.Reachable, tt.reachable, status.Error)
+			}
+			if !tt.reachable && status.Error == "" {
+				t.Error("Expected an error for an unreachable tracker")
+			}
+			if strings.Contains(status.Tracker, passkey) || strings.Contains(status.Error, passkey) {
+				t.Errorf("Passkey leaked in status %+v", status)
+			}
+		})
+	}
+}
+
+func TestCheckTracker_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("could not listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 16 || binary.BigEndian.Uint64(buf[0:8]) != udpProtocolID {
+				continue
+			}
+			resp := make([]byte, 16)
+			copy(resp[4:8], buf[12:16]) // action 0 (connect) and the transaction id
+			binary.BigEndian.PutUint64(resp[8:], 42)
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+
+	status := CheckTracker("udp://"+conn.LocalAddr().String()+"/announce", 2*time.Second)
+	if !status.Reachable || status.Status != "connected" {
+		t.Errorf("Expected the UDP tracker to be reachable, got %+v", status)
+	}
+
+	// a port nobody answers on times out
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	status = CheckTracker("udp://"+silent.LocalAddr().String()+"/announce", 200*time.Millisecond)
+	if status.Reachable {
+		t.Errorf("Expected a silent UDP tracker to be unreachable, got %+v", status)
+	}
+}
+
+func TestCheckTrackers_KeepsOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	urls := []string{"wss://tracker.example.com/announce", server.URL + "/announce"}
+	statuses := CheckTrackers(urls, time.Second)
+	if len(statuses) != 2 || statuses[0].Reachable || !statuses[1].Reachable {
+		t.Errorf("Expected [unreachable, reachable], got %+v", statuses)
+	}
+}
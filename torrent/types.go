@@ -2,35 +2,61 @@ package torrent
 
 import (
 	"os"
+	"time"
 
 	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/exp/mmap"
 )
 
 // ProgressCallback is called during hashing to report progress.
 // completed: number of pieces hashed so far
 // total: total number of pieces to hash
 // hashRate: current hashing rate in MiB per second
+//
+// Set it on CreateOptions or VerifyOptions to follow progress when embedding mkbrr,
+// without implementing Displayer. It replaces the terminal output, is called with
+// completed == 0 when hashing starts and with completed == total when it is done,
+// and may be called from another goroutine.
 type ProgressCallback func(completed, total int, hashRate float64)
 
+// BadPieceCallback is called by VerifyData as soon as a piece fails verification,
+// before the final result is available. It is called concurrently from the
+// verification workers, in no particular piece order.
+type BadPieceCallback func(pieceIndex int)
+
 // CreateOptions contains all options for creating a torrent
 type CreateOptions struct {
 	PieceLengthExp          *uint
+	MinPieceLength          *uint // floor for the calculated piece length exponent, ignored with PieceLengthExp
 	MaxPieceLength          *uint
 	TargetPieceCount        *uint
+	PieceLengthAutoMax      bool // smallest piece length that keeps the .torrent under the tracker's size limit
 	Path                    string
+	Sources                 []SourceSpec // files and directories combined into one torrent instead of Path, requires Name
+	PathDepth               int          // parent directories of Path kept in the torrent's layout, or leading directories stripped when negative
 	Name                    string
 	TrackerURLs             []string
+	AnnounceList            [][]string // optional tiers; takes precedence over TrackerURLs for the announce list
 	Comment                 string
 	Source                  string
+	CreatedBy               string // custom "created by" value, takes precedence over NoCreator
 	Version                 string
 	OutputPath              string
 	OutputDir               string
+	OutputPattern           string // filename pattern with placeholders, see preset.ExpandOutputPattern
+	OutputPrefix            string // prepended to the generated filename, before any tracker prefix; unused with OutputPath
+	OutputSuffix            string // appended to the generated filename, before .torrent; unused with OutputPath
+	Force                   bool   // overwrite an existing output file instead of failing with ErrOutputExists
+	SymlinkTo               string // directory to symlink the written torrent into, copied where symlinks are unsupported
 	WebSeeds                []string
 	ExcludePatterns         []string
 	IncludePatterns         []string
+	ExcludeRegex            []string // regular expressions matched against the path in the torrent, applied like ExcludePatterns
+	IncludeRegex            []string // regular expressions matched against the path in the torrent, applied like IncludePatterns
 	Workers                 int
 	IsPrivate               bool
 	NoDate                  bool
+	CreationDate            time.Time // fixed creation date, takes precedence over NoDate; zero means now
 	NoCreator               bool
 	Verbose                 bool
 	Entropy                 bool
@@ -38,14 +64,50 @@ type CreateOptions struct {
 	InfoOnly                bool
 	SkipPrefix              bool
 	FailOnSeasonPackWarning bool
-	// ProgressCallback is called during hashing to report progress.
-	// If nil, no progress callbacks will be made.
-	ProgressCallback        ProgressCallback
+	WarnDuplicates          bool              // warn about files with identical content before hashing
+	FailOnEmptyDirs         bool              // fail instead of skipping empty directories
+	VerifyStable            bool              // fail with ErrContentChanged if a file's size or mtime changed while hashing
+	WaitStable              time.Duration     // before walking, wait until no file changed for this long, for content still being written
+	ExpectedEpisodes        *EpisodeRange     // overrides the episode range inferred during season pack analysis
+	Batch                   bool              // set for concurrent batch jobs, suppresses per-torrent progress bars
+	Strict                  bool              // turn safety warnings (e.g. public torrent for a private tracker, case collisions) into errors
+	IOMode                  IOMode            // how file data is read while hashing, defaults to IOModeSync
+	MaxMemory               int64             // cap in bytes for read buffers across hashing workers, 0 for no limit
+	ReadRetries             int               // times a failed read is retried with backoff, e.g. on network filesystems
+	Storage                 StorageType       // storage the content is read from, detected from Path when empty or StorageAuto
+	Padded                  bool              // insert BEP 47 padding files so each file starts on a piece boundary
+	DedupHardlinks          bool              // hash pieces of hardlinked files once, see hardlinkPieceSources; most effective with Padded
+	DereferenceJunctions    bool              // follow NTFS directory junctions on Windows instead of skipping them, see isJunction
+	LegacyUTF8              bool              // also write name.utf-8 and path.utf-8, read by some older clients
+	NormalizeUnicode        UnicodeForm       // normalize the name and paths in the torrent, not the files on disk; changes the info hash
+	SourceMap               map[string]string // source tags keyed by tracker domain, taking precedence over Source
+	Nodes                   []string          // DHT bootstrap nodes (BEP 5) as host:port, for trackerless torrents
+	Meta                    map[string]string // custom string keys in the root dictionary, outside info so the info hash is unaffected
+	InfoMeta                map[string]string // custom string keys in the info dictionary, which change the info hash
+	// ProgressCallback is called during hashing to report progress and replaces the
+	// terminal output. If nil, no progress callbacks will be made.
+	ProgressCallback ProgressCallback
 }
 
 // Torrent represents a torrent file with additional functionality
 type Torrent struct {
 	*metainfo.MetaInfo
+	hashStats HashStats         // set by CreateTorrent
+	meta      map[string]string // custom root keys written next to the metainfo, see CreateOptions.Meta
+}
+
+// HashStats summarizes the hashing done while creating a torrent
+type HashStats struct {
+	BytesHashed int64
+	Elapsed     time.Duration
+}
+
+// Rate returns the average hashing rate in bytes per second
+func (s HashStats) Rate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.BytesHashed) / s.Elapsed.Seconds()
 }
 
 // FileEntry represents a file in the torrent
@@ -57,43 +119,57 @@ type FileEntry struct {
 
 // internal file entry for processing
 type fileEntry struct {
-	path   string
-	length int64
-	offset int64
+	path    string
+	length  int64
+	offset  int64
+	padding bool      // BEP 47 padding file, hashed as zeros and never read from disk
+	modTime time.Time // modification time when the content was walked, see changedFiles
 }
 
 // internal file reader for processing
 type fileReader struct {
-	file     *os.File
-	position int64
-	length   int64
+	file   *os.File
+	mapped *mmap.ReaderAt // set instead of file when the file is memory-mapped
+	length int64
 }
 
 // TorrentInfo contains summary information about the created torrent
 type TorrentInfo struct {
-	MetaInfo *metainfo.MetaInfo
-	Path     string
-	InfoHash string
-	Announce string
-	Size     int64
-	Files    int
+	MetaInfo    *metainfo.MetaInfo
+	Path        string
+	InfoHash    string
+	Announce    string
+	Size        int64
+	Files       int // entries in the file list including padding files, 0 for a single-file torrent
+	Pieces      int
+	Hash        HashStats
+	Source      string // source tag written to the info dictionary
+	Private     bool
+	PieceLength int64
+	LinkPath    string // link to Path created with CreateOptions.SymlinkTo
+	LinkCopied  bool   // LinkPath is a copy as symlinks were not supported
 }
 
 // VerificationResult holds the outcome of a torrent data verification check
 type VerificationResult struct {
-	BadPieceIndices []int
-	MissingFiles    []string
-	TotalPieces     int
-	GoodPieces      int
-	BadPieces       int
-	MissingPieces   int
-	Completion      float64
+	BadPieceIndices []int             `json:"badPieceIndices"`
+	MissingFiles    []string          `json:"missingFiles"`
+	BadFiles        []string          `json:"badFiles"`                // files that overlap a bad piece
+	ExtraFiles      []string          `json:"extraFiles"`              // files on disk that are not in the torrent, only collected with VerifyOptions.ReportExtra
+	ReadErrors      []string          `json:"readErrors"`              // reads that failed after retrying; their pieces are counted as bad
+	FirstBadPiece   *BadPieceLocation `json:"firstBadPiece,omitempty"` // only set with VerifyOptions.Locate
+	TotalPieces     int               `json:"totalPieces"`
+	GoodPieces      int               `json:"goodPieces"`
+	BadPieces       int               `json:"badPieces"`
+	MissingPieces   int               `json:"missingPieces"`
+	Completion      float64           `json:"completion"`
 }
 
 // callbackDisplayer adapts a ProgressCallback to the Displayer interface
 type callbackDisplayer struct {
 	callback ProgressCallback
 	total    int
+	lastRate float64 // MiB/s of the last update, repeated in the final one
 }
 
 // ShowProgress implements Displayer interface
@@ -106,8 +182,9 @@ func (c *callbackDisplayer) ShowProgress(total int) {
 
 // UpdateProgress implements Displayer interface
 func (c *callbackDisplayer) UpdateProgress(completed int, hashrate float64) {
+	c.lastRate = hashrate / (1024 * 1024)
 	if c.callback != nil {
-		c.callback(completed, c.total, hashrate/(1024*1024))
+		c.callback(completed, c.total, c.lastRate)
 	}
 }
 
@@ -120,7 +197,7 @@ func (c *callbackDisplayer) ShowSeasonPackWarnings(info *SeasonPackInfo) {}
 // FinishProgress implements Displayer interface
 func (c *callbackDisplayer) FinishProgress() {
 	if c.callback != nil {
-		c.callback(c.total, c.total, 0)
+		c.callback(c.total, c.total, c.lastRate)
 	}
 }
 
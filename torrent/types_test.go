@@ -1,6 +1,9 @@
 package torrent
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestCallbackDisplayerReportsHashRateInMiB(t *testing.T) {
 	var got float64
@@ -17,3 +20,41 @@ func TestCallbackDisplayerReportsHashRateInMiB(t *testing.T) {
 		t.Fatalf("callback hash rate = %v, want 1 MiB/s", got)
 	}
 }
+
+func TestVerificationResultJSON(t *testing.T) {
+	data, err := json.Marshal(VerificationResult{
+		BadPieceIndices: []int{3},
+		MissingFiles:    []string{"a.mkv"},
+		BadFiles:        []string{"b.mkv"},
+		TotalPieces:     10,
+		GoodPieces:      9,
+		BadPieces:       1,
+		Completion:      90,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"badPieceIndices":[3],"missingFiles":["a.mkv"],"badFiles":["b.mkv"],"extraFiles":null,"readErrors":null,"totalPieces":10,"goodPieces":9,"badPieces":1,"missingPieces":0,"completion":90}`
+	if string(data) != want {
+		t.Fatalf("json = %s, want %s", data, want)
+	}
+}
+
+func TestCallbackDisplayerFinishRepeatsLastRate(t *testing.T) {
+	var completed int
+	var rate float64
+	displayer := &callbackDisplayer{
+		callback: func(c, _ int, hashRate float64) {
+			completed, rate = c, hashRate
+		},
+	}
+
+	displayer.ShowProgress(4)
+	displayer.UpdateProgress(2, 2*1024*1024)
+	displayer.FinishProgress()
+
+	if completed != 4 || rate != 2 {
+		t.Fatalf("final callback = (%d, %v), want (4, 2 MiB/s)", completed, rate)
+	}
+}
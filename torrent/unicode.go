@@ -0,0 +1,68 @@
+package torrent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnicodeForm selects the Unicode normalization applied to the name and file paths in the torrent
+type UnicodeForm string
+
+const (
+	// UnicodeNone keeps names as they are on disk (default)
+	UnicodeNone UnicodeForm = "none"
+	// UnicodeNFC composes characters, as most Linux and Windows tools write them
+	UnicodeNFC UnicodeForm = "nfc"
+	// UnicodeNFD decomposes characters, as older macOS filesystems store them
+	UnicodeNFD UnicodeForm = "nfd"
+)
+
+// ParseUnicodeForm parses a normalization form as used by --normalize-unicode
+func ParseUnicodeForm(s string) (UnicodeForm, error) {
+	switch UnicodeForm(strings.ToLower(strings.TrimSpace(s))) {
+	case "", UnicodeNone:
+		return UnicodeNone, nil
+	case UnicodeNFC:
+		return UnicodeNFC, nil
+	case UnicodeNFD:
+		return UnicodeNFD, nil
+	default:
+		return UnicodeNone, fmt.Errorf("invalid unicode normalization %q: must be one of nfc, nfd, none", s)
+	}
+}
+
+// normalizeInfoPaths rewrites the name and file path components of info into form.
+// Only the torrent changes, files on disk keep their names. Two paths that become
+// the same after normalization are an error, as clients could not tell them apart.
+func normalizeInfoPaths(info *metainfo.Info, form UnicodeForm) error {
+	var f norm.Form
+	switch form {
+	case UnicodeNFC:
+		f = norm.NFC
+	case UnicodeNFD:
+		f = norm.NFD
+	default:
+		return nil
+	}
+
+	info.Name = f.String(info.Name)
+	seen := make(map[string]string, len(info.Files)) // normalized path -> original path
+	for i := range info.Files {
+		if isPaddingFile(info.Files[i]) {
+			continue
+		}
+		original := strings.Join(info.Files[i].Path, "/")
+		for j, component := range info.Files[i].Path {
+			info.Files[i].Path[j] = f.String(component)
+		}
+		normalized := strings.Join(info.Files[i].Path, "/")
+		if prev, ok := seen[normalized]; ok {
+			return fmt.Errorf("%q and %q are the same path after %s normalization", prev, original, strings.ToUpper(string(form)))
+		}
+		seen[normalized] = original
+	}
+	return nil
+}
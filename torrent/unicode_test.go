@@ -0,0 +1,123 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+const (
+	cafeNFC = "Caf\u00e9"  // é as one code point
+	cafeNFD = "Cafe\u0301" // e followed by a combining acute accent
+)
+
+func TestParseUnicodeForm(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    UnicodeForm
+		wantErr bool
+	}{
+		{input: "", want: UnicodeNone},
+		{input: "none", want: UnicodeNone},
+		{input: "NFC", want: UnicodeNFC},
+		{input: "nfd", want: UnicodeNFD},
+		{input: "nfkc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseUnicodeForm(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUnicodeForm(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseUnicodeForm(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeInfoPaths(t *testing.T) {
+	newInfo := func() *metainfo.Info {
+		return &metainfo.Info{
+			Name: cafeNFD,
+			Files: []metainfo.FileInfo{
+				{Path: []string{cafeNFD, "01.flac"}, Length: 1},
+				paddingFileInfo(3),
+				{Path: []string{"cover.jpg"}, Length: 1},
+			},
+		}
+	}
+
+	info := newInfo()
+	if err := normalizeInfoPaths(info, UnicodeNFC); err != nil {
+		t.Fatalf("normalizeInfoPaths failed: %v", err)
+	}
+	if info.Name != cafeNFC {
+		t.Errorf("Expected name %q, got %q", cafeNFC, info.Name)
+	}
+	if want := []string{cafeNFC, "01.flac"}; !slices.Equal(info.Files[0].Path, want) {
+		t.Errorf("Expected path %q, got %q", want, info.Files[0].Path)
+	}
+	if !isPaddingFile(info.Files[1]) {
+		t.Error("Expected the padding file to be left alone")
+	}
+
+	info = newInfo()
+	if err := normalizeInfoPaths(info, UnicodeNone); err != nil {
+		t.Fatalf("normalizeInfoPaths failed: %v", err)
+	}
+	if info.Name != cafeNFD || info.Files[0].Path[0] != cafeNFD {
+		t.Errorf("Expected names to be kept without normalization, got %q and %q", info.Name, info.Files[0].Path[0])
+	}
+
+	collide := &metainfo.Info{
+		Name: "Album",
+		Files: []metainfo.FileInfo{
+			{Path: []string{cafeNFC + ".flac"}, Length: 1},
+			{Path: []string{cafeNFD + ".flac"}, Length: 1},
+		},
+	}
+	if err := normalizeInfoPaths(collide, UnicodeNFD); err == nil {
+		t.Error("Expected an error for paths that collide after normalization")
+	}
+}
+
+func TestCreateTorrent_NormalizeUnicode(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "Album")
+	if err := os.MkdirAll(filepath.Join(contentDir, cafeNFD), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, cafeNFD, "01.flac"), []byte("unicode normalization test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(form UnicodeForm) (*Torrent, metainfo.Info) {
+		t.Helper()
+		mi, err := CreateTorrent(CreateOptions{Path: contentDir, NormalizeUnicode: form, NoDate: true, Quiet: true})
+		if err != nil {
+			t.Fatalf("CreateTorrent failed: %v", err)
+		}
+		info, err := mi.UnmarshalInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mi, info
+	}
+
+	kept, keptInfo := create(UnicodeNone)
+	normalized, normalizedInfo := create(UnicodeNFC)
+	if got := keptInfo.Files[0].Path[0]; got != cafeNFD {
+		t.Errorf("Expected the on-disk name %q without normalization, got %q", cafeNFD, got)
+	}
+	if got := normalizedInfo.Files[0].Path[0]; got != cafeNFC {
+		t.Errorf("Expected the NFC name %q, got %q", cafeNFC, got)
+	}
+	if kept.HashInfoBytes() == normalized.HashInfoBytes() {
+		t.Error("Expected normalization to change the info hash")
+	}
+}
@@ -0,0 +1,195 @@
+package torrent
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+
+	"github.com/autobrr/mkbrr/internal/trackers"
+)
+
+// ValidationSeverity describes how serious a validation finding is
+type ValidationSeverity int
+
+const (
+	SeverityPass ValidationSeverity = iota
+	SeverityWarn
+	SeverityFail
+)
+
+// String returns the lowercase name of the severity
+func (s ValidationSeverity) String() string {
+	switch s {
+	case SeverityPass:
+		return "pass"
+	case SeverityWarn:
+		return "warn"
+	case SeverityFail:
+		return "fail"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// MarshalText encodes the severity as its lowercase name
+func (s ValidationSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseValidationSeverity parses a severity threshold as used by --fail-on
+func ParseValidationSeverity(s string) (ValidationSeverity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "fail", "error":
+		return SeverityFail, nil
+	default:
+		return SeverityPass, fmt.Errorf("invalid severity %q: must be one of warn, fail", s)
+	}
+}
+
+// ValidationResult holds the outcome of a single tracker validation check
+type ValidationResult struct {
+	Check    string             `json:"check"`
+	Message  string             `json:"message"`
+	Severity ValidationSeverity `json:"severity"`
+}
+
+// ValidateForTracker checks a torrent against the known rules for a tracker.
+// torrentSize is the size of the encoded .torrent file in bytes.
+func ValidateForTracker(mi *metainfo.MetaInfo, info *metainfo.Info, torrentSize int64, trackerURL string) []ValidationResult {
+	var results []ValidationResult
+
+	host := trackerHost(trackerURL)
+
+	// announce URLs should point at the tracker being validated against
+	announceMatches := strings.Contains(mi.Announce, host)
+	for _, tier := range mi.AnnounceList {
+		for _, tracker := range tier {
+			if strings.Contains(tracker, host) {
+				announceMatches = true
+			}
+		}
+	}
+	if announceMatches {
+		results = append(results, ValidationResult{Check: "announce", Severity: SeverityPass, Message: fmt.Sprintf("announce URL matches %s", host)})
+	} else {
+		results = append(results, ValidationResult{Check: "announce", Severity: SeverityWarn, Message: fmt.Sprintf("no announce URL matches %s", host)})
+	}
+
+	switch {
+	case info.Private != nil && *info.Private:
+		results = append(results, ValidationResult{Check: "private", Severity: SeverityPass, Message: "private flag is set"})
+	case trackers.RequiresPrivate(trackerURL):
+		results = append(results, ValidationResult{Check: "private", Severity: SeverityFail, Message: fmt.Sprintf("private flag is not set but %s is a private tracker", host)})
+	default:
+		// public torrents are fine for trackers that don't require the private flag
+		results = append(results, ValidationResult{Check: "private", Severity: SeverityPass, Message: fmt.Sprintf("private flag is not set, which %s allows", host)})
+	}
+
+	maxExp, hasMaxPieceLength := trackers.GetTrackerMaxPieceLength(trackerURL)
+	maxTorrentSize, hasMaxTorrentSize := trackers.GetTrackerMaxTorrentSize(trackerURL)
+	defaultSource, hasDefaultSource := trackers.GetTrackerDefaultSource(trackerURL)
+	recommendedExp := GetRecommendedPieceLengthExp(trackerURL, uint64(info.TotalLength()))
+
+	if !hasMaxPieceLength && !hasMaxTorrentSize && !hasDefaultSource && recommendedExp == 0 {
+		results = append(results, ValidationResult{Check: "rules", Severity: SeverityPass, Message: fmt.Sprintf("no tracker-specific rules known for %s", host)})
+		return results
+	}
+
+	if hasMaxPieceLength {
+		if info.PieceLength > int64(1)<<maxExp {
+			results = append(results, ValidationResult{Check: "piece length", Severity: SeverityFail,
+				Message: fmt.Sprintf("piece length %s exceeds tracker maximum of %s", formatPieceLengthBytes(info.PieceLength), formatPieceSize(maxExp))})
+		} else {
+			results = append(results, ValidationResult{Check: "piece length", Severity: SeverityPass,
+				Message: fmt.Sprintf("piece length %s is within tracker maximum of %s", formatPieceLengthBytes(info.PieceLength), formatPieceSize(maxExp))})
+		}
+	}
+
+	if recommendedExp != 0 {
+		if info.PieceLength != int64(1)<<recommendedExp {
+			results = append(results, ValidationResult{Check: "recommended piece length", Severity: SeverityWarn,
+				Message: fmt.Sprintf("piece length %s differs from recommended %s", formatPieceLengthBytes(info.PieceLength), formatPieceSize(recommendedExp))})
+		} else {
+			results = append(results, ValidationResult{Check: "recommended piece length", Severity: SeverityPass,
+				Message: fmt.Sprintf("piece length matches recommended %s", formatPieceSize(recommendedExp))})
+		}
+	}
+
+	if hasMaxTorrentSize {
+		if uint64(torrentSize) > maxTorrentSize {
+			results = append(results, ValidationResult{Check: "torrent size", Severity: SeverityFail,
+				Message: fmt.Sprintf("torrent file is %.1f KiB, exceeds tracker limit of %.1f KiB", float64(torrentSize)/(1<<10), float64(maxTorrentSize)/(1<<10))})
+		} else {
+			results = append(results, ValidationResult{Check: "torrent size", Severity: SeverityPass,
+				Message: fmt.Sprintf("torrent file is %.1f KiB, within tracker limit of %.1f KiB", float64(torrentSize)/(1<<10), float64(maxTorrentSize)/(1<<10))})
+		}
+	}
+
+	if hasDefaultSource {
+		if !strings.EqualFold(info.Source, defaultSource) {
+			results = append(results, ValidationResult{Check: "source", Severity: SeverityWarn,
+				Message: fmt.Sprintf("source %q differs from tracker default %q", info.Source, defaultSource)})
+		} else {
+			results = append(results, ValidationResult{Check: "source", Severity: SeverityPass,
+				Message: fmt.Sprintf("source matches tracker default %q", defaultSource)})
+		}
+	}
+
+	return results
+}
+
+// HighestSeverity returns the most severe level found in the results
+func HighestSeverity(results []ValidationResult) ValidationSeverity {
+	highest := SeverityPass
+	for _, r := range results {
+		if r.Severity > highest {
+			highest = r.Severity
+		}
+	}
+	return highest
+}
+
+// trackerHost returns the host of a tracker URL without scheme, port or passkey
+func trackerHost(trackerURL string) string {
+	if u, err := url.Parse(trackerURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return trackerURL
+}
+
+// formatPieceLengthBytes formats a piece length in bytes the same way as formatPieceSize
+func formatPieceLengthBytes(pieceLength int64) string {
+	if pieceLength >= 1<<20 {
+		return fmt.Sprintf("%d MiB", pieceLength>>20)
+	}
+	return fmt.Sprintf("%d KiB", pieceLength>>10)
+}
+
+// ValidatePieces checks that the v1 piece hashes of info are well formed: the pieces
+// field must hold whole 20-byte SHA-1 hashes, one for each piece of the content.
+// Without this a corrupt torrent silently yields a wrong piece count. Pure v2
+// torrents carry no v1 piece hashes and are not checked.
+func ValidatePieces(info *metainfo.Info) error {
+	if info.HasV2() && len(info.Pieces) == 0 {
+		return nil
+	}
+	if info.PieceLength <= 0 {
+		return fmt.Errorf("%w: invalid piece length %d", ErrCorruptTorrent, info.PieceLength)
+	}
+	if len(info.Pieces)%20 != 0 {
+		return fmt.Errorf("%w: pieces field is %d bytes, not a multiple of the 20-byte SHA-1 hash length", ErrCorruptTorrent, len(info.Pieces))
+	}
+
+	got := int64(len(info.Pieces) / 20)
+	totalLength := info.TotalLength()
+	want := (totalLength + info.PieceLength - 1) / info.PieceLength
+	if got != want {
+		return fmt.Errorf("%w: has %d piece hashes but %d bytes of content in %d-byte pieces need %d",
+			ErrCorruptTorrent, got, totalLength, info.PieceLength, want)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func findValidationResult(results []ValidationResult, check string) (ValidationResult, bool) {
+	for _, r := range results {
+		if r.Check == check {
+			return r, true
+		}
+	}
+	return ValidationResult{}, false
+}
+
+func TestValidateForTracker(t *testing.T) {
+	private := true
+	public := false
+
+	tests := []struct {
+		name        string
+		announce    string
+		private     *bool
+		pieceLength int64
+		source      string
+		torrentSize int64
+		trackerURL  string
+		want        map[string]ValidationSeverity
+		highest     ValidationSeverity
+	}{
+		{
+			name:        "matching private torrent",
+			announce:    "https://tracker.anthelion.me/announce",
+			private:     &private,
+			pieceLength: 1 << 20,
+			source:      "ANT",
+			torrentSize: 10 << 10,
+			trackerURL:  "https://tracker.anthelion.me/announce",
+			want: map[string]ValidationSeverity{
+				"announce":     SeverityPass,
+				"private":      SeverityPass,
+				"torrent size": SeverityPass,
+				"source":       SeverityPass,
+			},
+			highest: SeverityPass,
+		},
+		{
+			name:        "public torrent and oversized file",
+			announce:    "https://tracker.anthelion.me/announce",
+			private:     &public,
+			pieceLength: 1 << 20,
+			source:      "ANT",
+			torrentSize: 300 << 10,
+			trackerURL:  "https://tracker.anthelion.me/announce",
+			want: map[string]ValidationSeverity{
+				"private":      SeverityFail,
+				"torrent size": SeverityFail,
+			},
+			highest: SeverityFail,
+		},
+		{
+			name:        "wrong source and announce",
+			announce:    "https://other.example/announce",
+			private:     &private,
+			pieceLength: 1 << 20,
+			source:      "OTHER",
+			torrentSize: 10 << 10,
+			trackerURL:  "https://tracker.anthelion.me/announce",
+			want: map[string]ValidationSeverity{
+				"announce": SeverityWarn,
+				"source":   SeverityWarn,
+			},
+			highest: SeverityWarn,
+		},
+		{
+			name:        "piece length above tracker maximum",
+			announce:    "https://morethantv.me/announce",
+			private:     &private,
+			pieceLength: 1 << 24,
+			source:      "MTV",
+			torrentSize: 10 << 10,
+			trackerURL:  "https://morethantv.me/announce",
+			want: map[string]ValidationSeverity{
+				"piece length": SeverityFail,
+			},
+			highest: SeverityFail,
+		},
+		{
+			name:        "public torrent for a public tracker",
+			announce:    "https://unknown.example/announce",
+			private:     &public,
+			pieceLength: 1 << 20,
+			torrentSize: 10 << 10,
+			trackerURL:  "https://unknown.example/announce",
+			want: map[string]ValidationSeverity{
+				"private": SeverityPass,
+			},
+			highest: SeverityPass,
+		},
+		{
+			name:        "unknown tracker",
+			announce:    "https://unknown.example/announce",
+			private:     &private,
+			pieceLength: 1 << 20,
+			torrentSize: 10 << 10,
+			trackerURL:  "https://unknown.example/announce",
+			want: map[string]ValidationSeverity{
+				"rules": SeverityPass,
+			},
+			highest: SeverityPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mi := &metainfo.MetaInfo{Announce: tt.announce}
+			info := &metainfo.Info{
+				Name:        "test",
+				Length:      100 << 20,
+				PieceLength: tt.pieceLength,
+				Private:     tt.private,
+				Source:      tt.source,
+			}
+
+			results := ValidateForTracker(mi, info, tt.torrentSize, tt.trackerURL)
+			for check, want := range tt.want {
+				r, ok := findValidationResult(results, check)
+				if !ok {
+					t.Fatalf("missing %q check in results: %+v", check, results)
+				}
+				if r.Severity != want {
+					t.Errorf("%s severity = %s, want %s (%s)", check, r.Severity, want, r.Message)
+				}
+			}
+			if got := HighestSeverity(results); got != tt.highest {
+				t.Errorf("HighestSeverity() = %s, want %s", got, tt.highest)
+			}
+		})
+	}
+}
+
+func TestParseValidationSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ValidationSeverity
+		wantErr bool
+	}{
+		{input: "warn", want: SeverityWarn},
+		{input: "FAIL", want: SeverityFail},
+		{input: "pass", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseValidationSeverity(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseValidationSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseValidationSeverity(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePieces(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    metainfo.Info
+		wantErr string
+	}{
+		{
+			name: "valid",
+			info: metainfo.Info{PieceLength: 16, Length: 40, Pieces: make([]byte, 3*20)},
+		},
+		{
+			name: "exact multiple of piece length",
+			info: metainfo.Info{PieceLength: 16, Length: 32, Pieces: make([]byte, 2*20)},
+		},
+		{
+			name:    "truncated hash",
+			info:    metainfo.Info{PieceLength: 16, Length: 40, Pieces: make([]byte, 3*20-1)},
+			wantErr: "not a multiple",
+		},
+		{
+			name:    "too few hashes",
+			info:    metainfo.Info{PieceLength: 16, Length: 40, Pieces: make([]byte, 2*20)},
+			wantErr: "has 2 piece hashes",
+		},
+		{
+			name:    "invalid piece length",
+			info:    metainfo.Info{Length: 40, Pieces: make([]byte, 3*20)},
+			wantErr: "invalid piece length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePieces(&tt.info)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePieces() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePieces() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_CorruptPieces(t *testing.T) {
+	info := metainfo.Info{Name: "test", PieceLength: 16384, Length: 100, Pieces: make([]byte, 19)}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := bencode.Marshal(metainfo.MetaInfo{InfoBytes: infoBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "corrupt.torrent")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "20-byte") {
+		t.Errorf("LoadFromFile() error = %v, want a piece hash length error", err)
+	}
+}
@@ -2,11 +2,14 @@ package torrent
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -23,7 +26,25 @@ type VerifyOptions struct {
 	Verbose          bool
 	Quiet            bool
 	Workers          int              // Number of worker goroutines for verification
-	ProgressCallback ProgressCallback // Optional callback for progress updates
+	ProgressCallback ProgressCallback // Optional callback for progress updates, replaces the terminal output
+	Timeout          time.Duration    // Timeout for fetching TorrentPath when it is an http(s) URL
+	ReadRetries      int              // Times a failed read is retried with backoff before the piece is marked bad
+	ReportExtra      bool             // Collect files under ContentPath that are not in the torrent into ExtraFiles
+	Storage          StorageType      // Storage the content is read from, detected from ContentPath when empty or StorageAuto
+	Raw              bool             // Treat ContentPath as one byte stream of the torrent's data, ignoring file boundaries
+	BadPieceCallback BadPieceCallback // Optional callback for each piece that fails verification
+	Locate           bool             // Re-read the first bad piece and report the file ranges it covers in FirstBadPiece
+	DedupHardlinks   bool             // Check pieces of hardlinked files once, see hardlinkPieceSources
+	RelativeTo       string           // Report file paths in the result relative to this directory instead of as found on disk
+
+	// ExtraContentPaths are further roots searched in order for files not found under
+	// ContentPath, for content spread over several disks. The first root holding a file
+	// with the expected size is used.
+	ExtraContentPaths []string
+
+	// MetaInfo is the already loaded torrent. When set, TorrentPath is not read again
+	// and only names the torrent in errors, so a remote torrent is fetched once.
+	MetaInfo *metainfo.MetaInfo
 }
 
 type pieceVerifier struct {
@@ -37,12 +58,20 @@ type pieceVerifier struct {
 
 	badPieceIndices  []int
 	missingFiles     []string
+	readErrors       []string         // Reads that still failed after retrying
 	missingRanges    [][2]int64       // Byte ranges [start, end) of missing/mismatched files
 	progressCallback ProgressCallback // Optional callback for progress updates
+	badPieceCallback BadPieceCallback // Optional callback for each bad piece
 
-	pieceLen  int64
-	numPieces int
-	readSize  int
+	pieceSources map[int]int    // pieces checked against an earlier hardlink's data instead of read
+	sourceHashes map[int][]byte // actual hashes of the pieces in pieceSources' values
+
+	pieceLen    int64
+	numPieces   int
+	readSize    int
+	readRetries int
+	storage     StorageType
+	ctx         context.Context // stops the workers with ErrCancelled when done, see VerifyDataContext
 
 	goodPieces    uint64
 	badPieces     uint64
@@ -52,141 +81,248 @@ type pieceVerifier struct {
 	mutex         sync.RWMutex
 }
 
+// DefaultContentPath returns where a torrent's content is expected when no content path
+// is given: the torrent's name inside downloadDir, or next to the torrent file if
+// downloadDir is empty. Remote torrents require a downloadDir, and torrents piped to
+// stdin are looked up in the current directory without one.
+func DefaultContentPath(torrentPath, downloadDir string, timeout time.Duration) (string, error) {
+	return DefaultContentPathContext(context.Background(), torrentPath, downloadDir, timeout)
+}
+
+// DefaultContentPathContext is DefaultContentPath with a context, used when fetching
+// a remote torrent.
+func DefaultContentPathContext(ctx context.Context, torrentPath, downloadDir string, timeout time.Duration) (string, error) {
+	var t *Torrent
+	var err error
+	if IsRemoteTorrent(torrentPath) {
+		if downloadDir == "" {
+			return "", fmt.Errorf("a download directory is required to locate the content of a remote torrent")
+		}
+		t, _, err = LoadFromURLContext(ctx, torrentPath, timeout)
+	} else if IsStdinTorrent(torrentPath) {
+		t, _, err = LoadFromStdin()
+	} else {
+		t, err = LoadFromFile(torrentPath)
+	}
+	if err != nil {
+		return "", err
+	}
+	return DefaultContentPathFor(t, torrentPath, downloadDir)
+}
+
+// DefaultContentPathFor is DefaultContentPath for a torrent already loaded from
+// torrentPath, so a remote torrent doesn't have to be fetched again.
+func DefaultContentPathFor(t *Torrent, torrentPath, downloadDir string) (string, error) {
+	if IsRemoteTorrent(torrentPath) && downloadDir == "" {
+		return "", fmt.Errorf("a download directory is required to locate the content of a remote torrent")
+	}
+
+	name := t.GetInfo().Name
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("torrent name %q cannot be used as a content path", name)
+	}
+
+	if downloadDir == "" && !IsStdinTorrent(torrentPath) {
+		downloadDir = filepath.Dir(torrentPath)
+	}
+	return filepath.Join(downloadDir, name), nil
+}
+
 // VerifyData checks the integrity of content files against a torrent file.
 // It compares the actual file data against the piece hashes in the torrent.
 // Returns detailed verification results including bad pieces and missing files.
 func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
-	mi, err := metainfo.LoadFromFile(opts.TorrentPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not load torrent file %q: %w", opts.TorrentPath, err)
+	return VerifyDataContext(context.Background(), opts)
+}
+
+// VerifyDataContext is VerifyData with a context. When ctx is done, the workers stop
+// after their current piece and an error matching both ErrCancelled and ctx.Err() is
+// returned instead of a partial result.
+func VerifyDataContext(ctx context.Context, opts VerifyOptions) (*VerificationResult, error) {
+	mi := opts.MetaInfo
+	switch {
+	case mi != nil:
+		// loaded by the caller
+	case IsRemoteTorrent(opts.TorrentPath):
+		t, _, err := LoadFromURLContext(ctx, opts.TorrentPath, opts.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		mi = t.MetaInfo
+	case IsStdinTorrent(opts.TorrentPath):
+		t, _, err := LoadFromStdin()
+		if err != nil {
+			return nil, err
+		}
+		mi = t.MetaInfo
+	default:
+		var err error
+		mi, err = metainfo.LoadFromFile(opts.TorrentPath)
+		if err != nil {
+			return nil, notFound(fmt.Errorf("could not load torrent file %q: %w", opts.TorrentPath, err))
+		}
 	}
 
 	info, err := mi.UnmarshalInfo()
 	if err != nil {
 		return nil, fmt.Errorf("could not unmarshal info dictionary from %q: %w", opts.TorrentPath, err)
 	}
+	if err := ValidatePieces(&info); err != nil {
+		return nil, fmt.Errorf("%q: %w", opts.TorrentPath, err)
+	}
 
 	mappedFiles := make([]fileEntry, 0)
 	var totalSize int64
-	var missingFiles []string
+	var missingFiles, extraFiles []string
+	var rawMissingRanges [][2]int64
 	baseContentPath := filepath.Clean(opts.ContentPath)
+	if opts.RelativeTo != "" {
+		// absolute, so the root can be found in read errors and rewritten
+		if abs, err := filepath.Abs(baseContentPath); err == nil {
+			baseContentPath = abs
+		}
+	}
+	contentRoots := []string{baseContentPath}
+	for _, root := range opts.ExtraContentPaths {
+		root = filepath.Clean(root)
+		if opts.RelativeTo != "" {
+			if abs, err := filepath.Abs(root); err == nil {
+				root = abs
+			}
+		}
+		contentRoots = append(contentRoots, root)
+	}
+	// relative torrent path of each mapped file, keyed by its path on disk
+	mappedRel := make(map[string]string)
+
+	if opts.Raw && len(contentRoots) > 1 {
+		return nil, fmt.Errorf("raw content must be a single content path")
+	}
 
-	if info.IsDir() {
+	if opts.Raw {
+		mappedFiles, missingFiles, rawMissingRanges, err = mapRawContent(baseContentPath, &info)
+		if err != nil {
+			return nil, err
+		}
+	} else if info.IsDir() {
 		// Multi-file torrent
 		expectedFiles := make(map[string]int64) // Map relative path (using '/') to expected size
+		torrentFiles := make(map[string]bool)   // Every file in the torrent, so files found twice are not extra
 		for _, f := range info.Files {
 			// Ensure the key uses forward slashes, consistent with torrent format
 			relPathKey := filepath.ToSlash(filepath.Join(f.Path...))
+			if isPaddingFile(f) {
+				continue // BEP 47 padding files are not stored on disk, added below
+			}
 			expectedFiles[relPathKey] = f.Length
+			torrentFiles[relPathKey] = true
 		}
 
-		// Walk the content directory provided by the user
-		err = filepath.Walk(baseContentPath, func(currentPath string, fileInfo os.FileInfo, walkErr error) error {
-			if walkErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: error walking path %q: %v\n", currentPath, walkErr)
-				return nil
+		// Walk each content root in order; a file is taken from the first root where it
+		// has the expected size, and only reported as mismatched if no root has it
+		var mismatched []string
+		for _, root := range contentRoots {
+			if len(expectedFiles) == 0 && !opts.ReportExtra {
+				break
 			}
-			if fileInfo.IsDir() {
-				if currentPath == baseContentPath {
+			err = filepath.Walk(root, func(currentPath string, fileInfo os.FileInfo, walkErr error) error {
+				if walkErr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: error walking path %q: %v\n", currentPath, walkErr)
+					return nil
+				}
+				if fileInfo.IsDir() {
 					return nil
 				}
-				return nil
-			}
 
-			relPath, err := filepath.Rel(baseContentPath, currentPath)
-			if err != nil {
-				return fmt.Errorf("failed to get relative path for %q: %w", currentPath, err)
-			}
-			relPath = filepath.ToSlash(relPath) // Ensure consistent slashes
+				relPath, err := filepath.Rel(root, currentPath)
+				if err != nil {
+					return fmt.Errorf("failed to get relative path for %q: %w", currentPath, err)
+				}
+				relPath = filepath.ToSlash(relPath) // Ensure consistent slashes
+
+				if expectedSize, ok := expectedFiles[relPath]; ok {
+					if fileInfo.Size() != expectedSize {
+						if !slices.Contains(mismatched, relPath) {
+							mismatched = append(mismatched, relPath)
+						}
+						return nil
+					}
 
-			if expectedSize, ok := expectedFiles[relPath]; ok {
-				if fileInfo.Size() != expectedSize {
-					missingFiles = append(missingFiles, relPath+" (size mismatch)")
+					mappedFiles = append(mappedFiles, fileEntry{
+						path:   currentPath,
+						length: fileInfo.Size(),
+						offset: totalSize,
+					})
+					mappedRel[currentPath] = relPath
+					totalSize += fileInfo.Size()
 					delete(expectedFiles, relPath)
-					return nil
+				} else if opts.ReportExtra && !torrentFiles[relPath] {
+					extraFiles = append(extraFiles, relPath)
 				}
+				return nil
+			})
 
-				mappedFiles = append(mappedFiles, fileEntry{
-					path:   currentPath,
-					length: fileInfo.Size(),
-					offset: totalSize,
-				})
-				totalSize += fileInfo.Size()
-				delete(expectedFiles, relPath)
+			if err != nil {
+				return nil, notFound(fmt.Errorf("error walking content path %q: %w", root, err))
 			}
-			return nil
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("error walking content path %q: %w", baseContentPath, err)
 		}
 
+		for _, relPath := range mismatched {
+			if _, ok := expectedFiles[relPath]; ok {
+				missingFiles = append(missingFiles, relPath+" (size mismatch)")
+				delete(expectedFiles, relPath)
+			}
+		}
 		for relPathKey := range expectedFiles {
 			missingFiles = append(missingFiles, relPathKey)
 		}
 
 	} else {
-		// Single-file torrent
-		contentFileInfo, err := os.Stat(baseContentPath)
-		if err != nil {
-			if os.IsNotExist(err) {
-				missingFiles = append(missingFiles, info.Name)
-			} else {
-				return nil, fmt.Errorf("could not stat content file %q: %w", baseContentPath, err)
+		// Single-file torrent, taken from the first root where it has the expected size
+		sizeMismatch := false
+		for _, root := range contentRoots {
+			filePath, size, err := statSingleFile(root, info.Name)
+			if err != nil {
+				return nil, err
 			}
-		} else {
-			if contentFileInfo.IsDir() {
-				filePathInDir := filepath.Join(baseContentPath, info.Name)
-				contentFileInfo, err = os.Stat(filePathInDir)
-				if err != nil {
-					if os.IsNotExist(err) {
-						missingFiles = append(missingFiles, info.Name)
-					} else {
-						return nil, fmt.Errorf("could not stat content file %q: %w", filePathInDir, err)
-					}
-				} else if contentFileInfo.IsDir() {
-					return nil, fmt.Errorf("expected content file %q, but found a directory", filePathInDir)
-				} else if contentFileInfo.Size() != info.Length {
-					missingFiles = append(missingFiles, info.Name+" (size mismatch)")
-				} else {
-					mappedFiles = append(mappedFiles, fileEntry{
-						path:   filePathInDir,
-						length: contentFileInfo.Size(),
-						offset: 0,
-					})
-					totalSize = contentFileInfo.Size()
-				}
+			if filePath == "" {
+				continue
+			}
+			if size != info.Length {
+				sizeMismatch = true
+				continue
+			}
+			mappedFiles = append(mappedFiles, fileEntry{
+				path:   filePath,
+				length: size,
+				offset: 0,
+			})
+			totalSize = size
+			break
+		}
+		if len(mappedFiles) == 0 {
+			if sizeMismatch {
+				missingFiles = append(missingFiles, info.Name+" (size mismatch)")
 			} else {
-				if contentFileInfo.Size() != info.Length {
-					missingFiles = append(missingFiles, info.Name+" (size mismatch)")
-				} else {
-					mappedFiles = append(mappedFiles, fileEntry{
-						path:   baseContentPath,
-						length: contentFileInfo.Size(),
-						offset: 0,
-					})
-					totalSize = contentFileInfo.Size()
-				}
+				missingFiles = append(missingFiles, info.Name)
 			}
 		}
 	}
 
 	// Sort mapped files based on original torrent order before recalculating offsets
-	if info.IsDir() && len(info.Files) > 0 && len(mappedFiles) > 1 {
+	if !opts.Raw && info.IsDir() && len(info.Files) > 0 && len(mappedFiles) > 1 {
 		originalOrder := make(map[string]int)
 		for i, f := range info.Files {
 			originalOrder[filepath.ToSlash(filepath.Join(f.Path...))] = i
 		}
 		sort.SliceStable(mappedFiles, func(i, j int) bool {
-			relPathI, _ := filepath.Rel(baseContentPath, mappedFiles[i].path)
-			relPathJ, _ := filepath.Rel(baseContentPath, mappedFiles[j].path)
-			return originalOrder[filepath.ToSlash(relPathI)] < originalOrder[filepath.ToSlash(relPathJ)]
+			return originalOrder[mappedRel[mappedFiles[i].path]] < originalOrder[mappedRel[mappedFiles[j].path]]
 		})
 	}
 
 	// Assign torrent-level byte offsets (not compacted) so piece verification
 	// uses the correct position in the torrent's logical byte stream.
-	if info.IsDir() && len(info.Files) > 0 {
+	if !opts.Raw && info.IsDir() && len(info.Files) > 0 {
 		torrentOffsets := make(map[string]int64)
 		currentOffset := int64(0)
 		for _, f := range info.Files {
@@ -195,12 +331,29 @@ func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
 			currentOffset += f.Length
 		}
 		for i := range mappedFiles {
-			relPath, err := filepath.Rel(baseContentPath, mappedFiles[i].path)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get relative path for %q: %w", mappedFiles[i].path, err)
+			mappedFiles[i].offset = torrentOffsets[mappedRel[mappedFiles[i].path]]
+		}
+
+		// Padding files are verified as zeros at their torrent offsets. They often
+		// share a name, so they are placed by offset rather than by path.
+		hasPadding := false
+		currentOffset = 0
+		for _, f := range info.Files {
+			if isPaddingFile(f) {
+				mappedFiles = append(mappedFiles, fileEntry{
+					path:    filepath.Join(baseContentPath, filepath.Join(f.Path...)),
+					length:  f.Length,
+					offset:  currentOffset,
+					padding: true,
+				})
+				hasPadding = true
 			}
-			relPath = filepath.ToSlash(relPath)
-			mappedFiles[i].offset = torrentOffsets[relPath]
+			currentOffset += f.Length
+		}
+		if hasPadding {
+			sort.SliceStable(mappedFiles, func(i, j int) bool {
+				return mappedFiles[i].offset < mappedFiles[j].offset
+			})
 		}
 	}
 
@@ -215,11 +368,18 @@ func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
 		display:          NewDisplay(NewFormatter(opts.Verbose)),
 		missingFiles:     missingFiles,
 		progressCallback: opts.ProgressCallback,
+		badPieceCallback: opts.BadPieceCallback,
+		readRetries:      opts.ReadRetries,
+		storage:          resolveStorage(opts.Storage, opts.ContentPath),
+		ctx:              ctx,
 	}
-	verifier.display.SetQuiet(opts.Quiet)
+	// a progress callback replaces the terminal output, as in CreateTorrent
+	verifier.display.SetQuiet(opts.Quiet || opts.ProgressCallback != nil)
 
 	// Calculate missing ranges *before* verification starts
-	if len(verifier.missingFiles) > 0 {
+	if opts.Raw {
+		verifier.missingRanges = rawMissingRanges
+	} else if len(verifier.missingFiles) > 0 {
 		missingFileSet := make(map[string]bool)
 		for _, mf := range verifier.missingFiles {
 			basePath := strings.TrimSuffix(mf, " (size mismatch)")
@@ -241,6 +401,15 @@ func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
 		}
 	}
 
+	if opts.DedupHardlinks && numPieces > 0 {
+		lastPieceLength := info.TotalLength() - int64(numPieces-1)*info.PieceLength
+		verifier.pieceSources = hardlinkPieceSources(verifier.files, info.PieceLength, numPieces, lastPieceLength)
+		verifier.sourceHashes = make(map[int][]byte)
+		for _, source := range verifier.pieceSources {
+			verifier.sourceHashes[source] = nil
+		}
+	}
+
 	// 5. Perform Verification (Hashing and Comparison)
 	// Pass opts.Workers to verifyPieces
 	err = verifier.verifyPieces(opts.Workers) // Pass workers from options
@@ -257,6 +426,18 @@ func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
 		Completion:      0.0,                         // Will be calculated below
 		BadPieceIndices: verifier.badPieceIndices,
 		MissingFiles:    verifier.missingFiles,
+		BadFiles:        filesWithBadPieces(&info, verifier.badPieceIndices),
+		ExtraFiles:      extraFiles,
+		ReadErrors:      verifier.readErrors,
+	}
+
+	if opts.Locate && len(verifier.badPieceIndices) > 0 {
+		first := slices.Min(verifier.badPieceIndices)
+		result.FirstBadPiece = locatePiece(verifier.files, first, verifier.pieceLen, info.TotalLength())
+	}
+
+	if opts.RelativeTo != "" {
+		relativizeResult(result, opts.RelativeTo, contentRoots)
 	}
 
 	// Final calculation of completion percentage based on pieces that could be checked
@@ -275,6 +456,110 @@ func VerifyData(opts VerifyOptions) (*VerificationResult, error) {
 	return result, nil
 }
 
+// RelativePath returns path relative to base, so a report can be shared without
+// revealing where the data lives. path is returned unchanged if base is empty or
+// the two can't be related, e.g. on different Windows volumes.
+func RelativePath(base, path string) string {
+	if base == "" || path == "" {
+		return path
+	}
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// relativizeResult rewrites the on-disk paths in result relative to base. Missing
+// and extra files are usually relative to the content already; read errors are free
+// text, so every absolute content root is replaced wherever it appears in them.
+func relativizeResult(result *VerificationResult, base string, contentRoots []string) {
+	// longest roots first, so a root nested in another is replaced as a whole
+	roots := slices.Clone(contentRoots)
+	slices.SortStableFunc(roots, func(a, b string) int { return len(b) - len(a) })
+	pairs := make([]string, 0, 2*len(roots))
+	for _, root := range roots {
+		pairs = append(pairs, root, RelativePath(base, root))
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	if loc := result.FirstBadPiece; loc != nil {
+		for i := range loc.Spans {
+			loc.Spans[i].Path = RelativePath(base, loc.Spans[i].Path)
+			loc.Spans[i].ReadError = replacer.Replace(loc.Spans[i].ReadError)
+		}
+	}
+	for i, msg := range result.ReadErrors {
+		result.ReadErrors[i] = replacer.Replace(msg)
+	}
+	for i, file := range result.MissingFiles {
+		result.MissingFiles[i] = replacer.Replace(file)
+	}
+}
+
+// statSingleFile finds a single-file torrent's content under root, which is either the
+// file itself or a directory holding it by the torrent's name. It returns an empty path
+// when the file does not exist.
+func statSingleFile(root, name string) (string, int64, error) {
+	fileInfo, err := os.Stat(root)
+	if os.IsNotExist(err) {
+		return "", 0, nil
+	} else if err != nil {
+		return "", 0, fmt.Errorf("could not stat content file %q: %w", root, err)
+	}
+	if !fileInfo.IsDir() {
+		return root, fileInfo.Size(), nil
+	}
+
+	filePath := filepath.Join(root, name)
+	fileInfo, err = os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return "", 0, nil
+	} else if err != nil {
+		return "", 0, fmt.Errorf("could not stat content file %q: %w", filePath, err)
+	}
+	if fileInfo.IsDir() {
+		return "", 0, fmt.Errorf("expected content file %q, but found a directory", filePath)
+	}
+	return filePath, fileInfo.Size(), nil
+}
+
+// mapRawContent maps a single file holding the torrent's data as one byte stream,
+// including any BEP 47 padding, onto the whole torrent regardless of its file layout.
+// If the file is shorter than the torrent, the pieces past its end are reported missing;
+// bytes beyond the torrent's length are ignored.
+func mapRawContent(contentPath string, info *metainfo.Info) ([]fileEntry, []string, [][2]int64, error) {
+	fileInfo, err := os.Stat(contentPath)
+	if err != nil {
+		return nil, nil, nil, notFound(fmt.Errorf("could not stat raw content %q: %w", contentPath, err))
+	}
+	if fileInfo.IsDir() {
+		return nil, nil, nil, fmt.Errorf("raw content %q must be a file, not a directory", contentPath)
+	}
+
+	totalLength := info.TotalLength()
+	length := min(fileInfo.Size(), totalLength)
+	if length == totalLength {
+		return []fileEntry{{path: contentPath, length: length}}, nil, nil, nil
+	}
+
+	missing := []string{fmt.Sprintf("%s (raw data %d bytes short)", filepath.Base(contentPath), totalLength-length)}
+	ranges := [][2]int64{{length, totalLength}}
+	var files []fileEntry
+	if length > 0 {
+		files = []fileEntry{{path: contentPath, length: length}}
+	}
+	return files, missing, ranges, nil
+}
+
 // optimizeForWorkload determines optimal read buffer size and number of worker goroutines
 func (v *pieceVerifier) optimizeForWorkload() (int, int) {
 	if len(v.files) == 0 {
@@ -317,6 +602,7 @@ func (v *pieceVerifier) optimizeForWorkload() (int, int) {
 		readSize = 8 << 20
 		numWorkers = defaultWorkerCount(true)
 	}
+	readSize, numWorkers = tuneForStorage(v.storage, readSize, numWorkers)
 
 	if numWorkers > v.numPieces {
 		numWorkers = v.numPieces
@@ -357,6 +643,8 @@ func (v *pieceVerifier) verifyPieces(numWorkersOverride int) error {
 	if v.numPieces > 0 && numWorkers <= 0 {
 		numWorkers = 1
 	}
+	debugf("verify: %d pieces of %d bytes from %d files, %d workers, %d byte reads, storage %s",
+		v.numPieces, v.pieceLen, len(v.files), numWorkers, v.readSize, v.storage)
 
 	v.bufferPool = &sync.Pool{
 		New: func() interface{} {
@@ -375,43 +663,26 @@ func (v *pieceVerifier) verifyPieces(numWorkersOverride int) error {
 	v.display.ShowFiles(v.files, numWorkers)
 
 	var completedPieces uint64
-	piecesPerWorker := (v.numPieces + numWorkers - 1) / numWorkers
+	queue := newPieceQueue(v.numPieces, numWorkers)
 	errorsCh := make(chan error, numWorkers)
 	done := make(chan struct{}) // Signal channel to stop progress monitoring
 
 	v.display.ShowProgress(v.numPieces) // Show progress bar only if numPieces > 0
-
-	var wg sync.WaitGroup
-
-	// Verify first piece immediately
-	if err := v.verifyPieceRange(0, 1, &completedPieces); err != nil {
-		errorsCh <- err
-	}
-	if piecesPerWorker > 1 {
-		// Start first worker job
-		wg.Add(1)
-		go func(startPiece, endPiece int) {
-			defer wg.Done()
-			if err := v.verifyPieceRange(startPiece, endPiece, &completedPieces); err != nil {
-				errorsCh <- err
-			}
-		}(1, piecesPerWorker) // Start from piece 1 since piece 0 is already processed
+	if v.progressCallback != nil {
+		v.progressCallback(0, v.numPieces, 0)
 	}
-	// Populate the other workers
-	for i := 1; i < numWorkers; i++ {
-		start := i * piecesPerWorker
-		end := start + piecesPerWorker
-		if end > v.numPieces {
-			end = v.numPieces
-		}
 
+	// Workers pull chunks of pieces from a shared queue to stay balanced
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
-		go func(startPiece, endPiece int) {
+		go func() {
 			defer wg.Done()
-			if err := v.verifyPieceRange(startPiece, endPiece, &completedPieces); err != nil {
+			if err := v.verifyWorker(queue, &completedPieces); err != nil {
+				queue.stop()
 				errorsCh <- err
 			}
-		}(start, end)
+		}()
 	}
 
 	monitorDone := make(chan struct{}) // Channel to signal when the progress monitoring goroutine has fully exited
@@ -470,49 +741,108 @@ func (v *pieceVerifier) verifyPieces(numWorkersOverride int) error {
 		}
 	}
 
+	v.checkHardlinkPieces()
+
 	v.display.FinishProgress()
 	return nil
 }
 
-// verifyPieceRange processes and verifies a specific range of pieces.
-func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, completedPieces *uint64) error {
+// checkHardlinkPieces compares the pieces skipped for being hardlinks of earlier
+// pieces with the hash of their source piece. A piece whose source couldn't be
+// read is bad.
+func (v *pieceVerifier) checkHardlinkPieces() {
+	for piece, source := range v.pieceSources {
+		if v.isMissingPiece(piece) {
+			continue // counted as missing by the workers
+		}
+		expected := v.torrentInfo.Pieces[piece*20 : (piece+1)*20]
+		if actual := v.sourceHashes[source]; actual != nil && bytes.Equal(actual, expected) {
+			atomic.AddUint64(&v.goodPieces, 1)
+		} else {
+			v.recordBadPiece(piece, nil)
+		}
+	}
+}
+
+// isMissingPiece reports whether a piece overlaps the data of a missing file
+func (v *pieceVerifier) isMissingPiece(pieceIndex int) bool {
+	pieceOffset := int64(pieceIndex) * v.pieceLen
+	pieceEndOffset := pieceOffset + v.pieceLen
+	for _, r := range v.missingRanges {
+		if pieceOffset < r[1] && pieceEndOffset > r[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// recordBadPiece counts a piece that failed verification, along with the read
+// error that caused it if any, and reports it to the bad piece callback
+func (v *pieceVerifier) recordBadPiece(pieceIndex int, readErr error) {
+	atomic.AddUint64(&v.badPieces, 1)
+	v.mutex.Lock()
+	v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
+	if readErr != nil {
+		v.readErrors = append(v.readErrors, readErr.Error())
+	}
+	v.mutex.Unlock()
+
+	if v.badPieceCallback != nil {
+		v.badPieceCallback(pieceIndex)
+	}
+}
+
+// verifyWorker verifies chunks claimed from queue until none are left, keeping its
+// buffer and open files across chunks
+func (v *pieceVerifier) verifyWorker(queue *pieceQueue, completedPieces *uint64) error {
 	buf := v.bufferPool.Get().([]byte)
 	defer v.bufferPool.Put(buf)
 
 	hasher := sha1.New()
-	readers := make([]*fileReader, len(v.files))
-	defer func() {
-		for _, r := range readers {
-			if r != nil && r.file != nil {
-				r.file.Close()
-			}
+	readers := newFileReaderCache(v.files)
+	defer readers.closeAll()
+
+	for {
+		if err := ctxErr(v.ctx); err != nil {
+			return err
 		}
-	}()
+		start, end, ok := queue.claim()
+		if !ok {
+			return nil
+		}
+		if err := v.verifyPieceRange(start, end, buf, hasher, readers, completedPieces); err != nil {
+			return err
+		}
+	}
+}
 
-	currentFileIndex := 0
+// verifyPieceRange processes and verifies a specific range of pieces.
+func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, buf []byte, hasher hash.Hash, readers *fileReaderCache, completedPieces *uint64) error {
+	// chunks are claimed in ascending order, so the search can start at the first open file
+	currentFileIndex := readers.first
 
 	for pieceIndex := startPiece; pieceIndex < endPiece; pieceIndex++ {
+		if err := ctxErr(v.ctx); err != nil {
+			return err
+		}
 		var expectedHash []byte
 		var actualHash []byte
 
 		pieceOffset := int64(pieceIndex) * v.pieceLen
 		pieceEndOffset := pieceOffset + v.pieceLen
 
-		// Check if this piece falls within a known missing range
-		isMissing := false
-		for _, r := range v.missingRanges {
-			if pieceOffset < r[1] && pieceEndOffset > r[0] {
-				isMissing = true
-				break
-			}
-		}
-
-		if isMissing {
+		if v.isMissingPiece(pieceIndex) {
 			atomic.AddUint64(&v.missingPieces, 1)
 			atomic.AddUint64(completedPieces, 1)
 			continue // Skip hashing/comparison for missing pieces
 		}
 
+		if _, ok := v.pieceSources[pieceIndex]; ok {
+			// compared with its source's hash once all workers are done
+			atomic.AddUint64(completedPieces, 1)
+			continue
+		}
+
 		// If not missing, proceed to hash and compare
 		hasher.Reset()
 		bytesHashedThisPiece := int64(0)
@@ -527,12 +857,10 @@ func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, completedPiec
 				break
 			}
 		}
+		readers.releaseBefore(currentFileIndex)
 		if !foundStartFile {
 			// Should not happen if missingRanges logic is correct and piece is not missing
-			atomic.AddUint64(&v.badPieces, 1)
-			v.mutex.Lock()
-			v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
-			v.mutex.Unlock()
+			v.recordBadPiece(pieceIndex, nil)
 			atomic.AddUint64(completedPieces, 1)
 			continue
 		}
@@ -556,31 +884,18 @@ func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, completedPiec
 				continue
 			}
 
-			reader := readers[fIdx]
-			if reader == nil {
-				f, err := os.OpenFile(file.path, os.O_RDONLY, 0)
-				if err != nil {
-					// File became unreadable after initial check? Mark as bad.
-					atomic.AddUint64(&v.badPieces, 1)
-					v.mutex.Lock()
-					v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
-					v.mutex.Unlock()
-					goto nextPiece // Use goto to ensure completedPieces is incremented
-				}
-				reader = &fileReader{file: f, position: -1, length: file.length}
-				readers[fIdx] = reader
+			if file.padding {
+				hashZeros(hasher, buf, readLength)
+				bytesHashedThisPiece += readLength
+				pieceOffset += readLength
+				continue
 			}
 
-			if reader.position != readStartInFile {
-				_, err := reader.file.Seek(readStartInFile, io.SeekStart)
-				if err != nil {
-					atomic.AddUint64(&v.badPieces, 1)
-					v.mutex.Lock()
-					v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
-					v.mutex.Unlock()
-					goto nextPiece
-				}
-				reader.position = readStartInFile
+			reader, err := readers.get(fIdx)
+			if err != nil {
+				// File became unreadable after initial check? Mark as bad.
+				v.recordBadPiece(pieceIndex, nil)
+				goto nextPiece // Use goto to ensure completedPieces is incremented
 			}
 
 			bytesToRead := readLength
@@ -589,21 +904,17 @@ func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, completedPiec
 				if bytesToRead < readSize {
 					readSize = bytesToRead
 				}
-				n, err := reader.file.Read(buf[:readSize])
+				n, err := readAtWithRetry(reader.file, file.path, buf[:readSize], readEndInFile-bytesToRead, v.readRetries)
 				if err != nil && err != io.EOF {
-					atomic.AddUint64(&v.badPieces, 1)
-					v.mutex.Lock()
-					v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
-					v.mutex.Unlock()
+					v.recordBadPiece(pieceIndex, err)
 					goto nextPiece
 				}
-				if n == 0 && err == io.EOF {
-					break
-				}
 				hasher.Write(buf[:n])
 				bytesHashedThisPiece += int64(n)
-				reader.position += int64(n)
 				bytesToRead -= int64(n)
+				if err == io.EOF {
+					break
+				}
 			}
 			pieceOffset += readLength
 		}
@@ -614,14 +925,18 @@ func (v *pieceVerifier) verifyPieceRange(startPiece, endPiece int, completedPiec
 
 		expectedHash = v.torrentInfo.Pieces[pieceIndex*20 : (pieceIndex+1)*20]
 		actualHash = hasher.Sum(actualHashBuf[:0])
+		if v.sourceHashes != nil {
+			v.mutex.Lock()
+			if _, ok := v.sourceHashes[pieceIndex]; ok {
+				v.sourceHashes[pieceIndex] = bytes.Clone(actualHash)
+			}
+			v.mutex.Unlock()
+		}
 
 		if bytes.Equal(actualHash, expectedHash) {
 			atomic.AddUint64(&v.goodPieces, 1)
 		} else {
-			atomic.AddUint64(&v.badPieces, 1)
-			v.mutex.Lock()
-			v.badPieceIndices = append(v.badPieceIndices, pieceIndex)
-			v.mutex.Unlock()
+			v.recordBadPiece(pieceIndex, nil)
 		}
 
 	nextPiece:
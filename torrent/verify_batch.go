@@ -0,0 +1,90 @@
+package torrent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// BatchVerifyResult represents the outcome of verifying one torrent in a batch
+type BatchVerifyResult struct {
+	Error       error
+	Result      *VerificationResult
+	TorrentPath string
+	ContentPath string
+	Name        string
+}
+
+// Complete reports whether the torrent's content was fully verified
+func (r BatchVerifyResult) Complete() bool {
+	return r.Error == nil && r.Result != nil && r.Result.BadPieces == 0 && len(r.Result.MissingFiles) == 0
+}
+
+// VerifyBatch verifies every .torrent file in torrentDir against its content in
+// downloadDir, located by the torrent's name. If downloadDir is empty, the content
+// is expected next to the torrent files. Torrents are verified one at a time, each
+// using opts for workers and read retries; a torrent that can't be verified is
+// recorded with its error and doesn't stop the batch.
+func VerifyBatch(torrentDir, downloadDir string, opts VerifyOptions) ([]BatchVerifyResult, error) {
+	entries, err := os.ReadDir(torrentDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read torrent directory: %w", err)
+	}
+
+	var torrentPaths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".torrent") {
+			continue
+		}
+		torrentPaths = append(torrentPaths, filepath.Join(torrentDir, entry.Name()))
+	}
+	if len(torrentPaths) == 0 {
+		return nil, fmt.Errorf("no .torrent files found in %q", torrentDir)
+	}
+	sort.Strings(torrentPaths)
+
+	if downloadDir == "" {
+		downloadDir = torrentDir
+	}
+
+	// progress output of individual torrents would drown the summary
+	opts.Quiet = true
+	opts.ProgressCallback = nil
+
+	results := make([]BatchVerifyResult, len(torrentPaths))
+	for i, torrentPath := range torrentPaths {
+		results[i] = verifyBatchTorrent(torrentPath, downloadDir, opts)
+		if opts.RelativeTo != "" {
+			results[i].TorrentPath = RelativePath(opts.RelativeTo, results[i].TorrentPath)
+			results[i].ContentPath = RelativePath(opts.RelativeTo, results[i].ContentPath)
+		}
+	}
+	return results, nil
+}
+
+func verifyBatchTorrent(torrentPath, downloadDir string, opts VerifyOptions) BatchVerifyResult {
+	result := BatchVerifyResult{
+		TorrentPath: torrentPath,
+		Name:        strings.TrimSuffix(filepath.Base(torrentPath), filepath.Ext(torrentPath)),
+	}
+
+	contentPath, err := DefaultContentPath(torrentPath, downloadDir, opts.Timeout)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Name = filepath.Base(contentPath)
+	result.ContentPath = contentPath
+
+	if _, err := os.Stat(contentPath); err != nil {
+		result.Error = fmt.Errorf("content not found: %w", err)
+		return result
+	}
+
+	opts.TorrentPath = torrentPath
+	opts.ContentPath = contentPath
+	result.Result, result.Error = VerifyData(opts)
+	return result
+}
@@ -0,0 +1,78 @@
+package torrent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	tempDir := t.TempDir()
+	downloadDir := filepath.Join(tempDir, "data")
+	torrentDir := filepath.Join(tempDir, "torrents")
+	for _, dir := range []string{downloadDir, torrentDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pieceLenExp := uint(16)
+	create := func(name string, content []byte) string {
+		contentPath := filepath.Join(downloadDir, name)
+		if err := os.WriteFile(contentPath, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		torrentPath := filepath.Join(torrentDir, name+".torrent")
+		if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, PieceLengthExp: &pieceLenExp, NoDate: true}); err != nil {
+			t.Fatalf("failed to create torrent: %v", err)
+		}
+		return contentPath
+	}
+
+	content := make([]byte, 200_000)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	create("good.bin", content)
+	corruptPath := create("corrupt.bin", content)
+	missingPath := create("missing.bin", content)
+
+	corrupted := append([]byte(nil), content...)
+	corrupted[70_000] ^= 0xff
+	if err := os.WriteFile(corruptPath, corrupted, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(missingPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(torrentDir, "notes.txt"), []byte("not a torrent"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := VerifyBatch(torrentDir, downloadDir, VerifyOptions{})
+	if err != nil {
+		t.Fatalf("VerifyBatch failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	// results are sorted by torrent file name
+	corrupt, good, missing := results[0], results[1], results[2]
+	if corrupt.Name != "corrupt.bin" || good.Name != "good.bin" || missing.Name != "missing.bin" {
+		t.Fatalf("unexpected result order: %q, %q, %q", corrupt.Name, good.Name, missing.Name)
+	}
+	if !good.Complete() {
+		t.Errorf("expected good.bin to be complete, got %+v", good)
+	}
+	if corrupt.Complete() || corrupt.Error != nil || corrupt.Result.BadPieces != 1 {
+		t.Errorf("expected corrupt.bin to have 1 bad piece, got %+v", corrupt)
+	}
+	if missing.Error == nil {
+		t.Error("expected an error for missing content")
+	}
+
+	if _, err := VerifyBatch(downloadDir, "", VerifyOptions{}); err == nil {
+		t.Error("expected an error for a directory without torrents")
+	}
+}
@@ -1,13 +1,21 @@
 package torrent
 
 import (
+	"context"
 	"crypto/sha1"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/anacrolix/torrent/metainfo"
 )
 
 // Reusing the helper from hasher_test.go to create test files efficiently.
@@ -298,6 +306,144 @@ func TestVerifyData_PerfectMatch_MultiFile(t *testing.T) {
 	}
 }
 
+func TestVerifyData_ReportExtra(t *testing.T) {
+	tempDir := t.TempDir()
+	contentDir := filepath.Join(tempDir, "content")
+	if err := os.MkdirAll(filepath.Join(contentDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.bin", filepath.Join("sub", "b.bin")} {
+		if err := os.WriteFile(filepath.Join(contentDir, name), []byte("content of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pieceLenExp := uint(16)
+	torrentPath := filepath.Join(tempDir, "extra.torrent")
+	if _, err := Create(CreateOptions{Path: contentDir, OutputPath: torrentPath, PieceLengthExp: &pieceLenExp, NoDate: true}); err != nil {
+		t.Fatalf("Failed to create test torrent file: %v", err)
+	}
+
+	// junk added after the torrent was created
+	for _, name := range []string{"notes.txt", filepath.Join("sub", "thumbs.db")} {
+		if err := os.WriteFile(filepath.Join(contentDir, name), []byte("junk"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData failed unexpectedly: %v", err)
+	}
+	if result.ExtraFiles != nil {
+		t.Errorf("Expected no extra files without ReportExtra, got %v", result.ExtraFiles)
+	}
+
+	result, err = VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Quiet: true, ReportExtra: true})
+	if err != nil {
+		t.Fatalf("VerifyData failed unexpectedly: %v", err)
+	}
+	if result.Completion != 100.0 || len(result.MissingFiles) != 0 {
+		t.Errorf("Expected extra files not to affect completion, got %.2f%% and missing %v", result.Completion, result.MissingFiles)
+	}
+	want := []string{"notes.txt", "sub/thumbs.db"}
+	if fmt.Sprint(result.ExtraFiles) != fmt.Sprint(want) {
+		t.Errorf("Expected extra files %v, got %v", want, result.ExtraFiles)
+	}
+}
+
+func TestVerifyData_ExtraContentPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	contentDir := filepath.Join(tempDir, "content")
+	files := map[string]string{
+		"a.bin":                       strings.Repeat("a", 70000),
+		filepath.Join("sub", "b.bin"): strings.Repeat("b", 50000),
+		"c.bin":                       strings.Repeat("c", 30000),
+	}
+	for name, data := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(contentDir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(contentDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pieceLenExp := uint(16)
+	torrentPath := filepath.Join(tempDir, "roots.torrent")
+	if _, err := Create(CreateOptions{Path: contentDir, OutputPath: torrentPath, PieceLengthExp: &pieceLenExp, NoDate: true}); err != nil {
+		t.Fatalf("Failed to create test torrent file: %v", err)
+	}
+
+	// split the content over two roots, with a truncated copy of c.bin on the first
+	diskA := filepath.Join(tempDir, "diskA")
+	diskB := filepath.Join(tempDir, "diskB")
+	for _, dir := range []string{diskA, filepath.Join(diskB, "sub")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	move := func(name, root string) {
+		if err := os.Rename(filepath.Join(contentDir, name), filepath.Join(root, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	move("a.bin", diskA)
+	move(filepath.Join("sub", "b.bin"), diskB)
+	move("c.bin", diskB)
+	if err := os.WriteFile(filepath.Join(diskA, "c.bin"), []byte("short"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: diskA, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData failed unexpectedly: %v", err)
+	}
+	want := []string{"c.bin (size mismatch)", "sub/b.bin"}
+	if fmt.Sprint(result.MissingFiles) != fmt.Sprint(want) {
+		t.Errorf("Expected missing files %v with one root, got %v", want, result.MissingFiles)
+	}
+
+	result, err = VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: diskA, ExtraContentPaths: []string{diskB}, Quiet: true, ReportExtra: true})
+	if err != nil {
+		t.Fatalf("VerifyData failed unexpectedly: %v", err)
+	}
+	if result.Completion != 100.0 || result.BadPieces != 0 || len(result.MissingFiles) != 0 {
+		t.Errorf("Expected complete result over both roots, got %.2f%%, %d bad pieces, missing %v", result.Completion, result.BadPieces, result.MissingFiles)
+	}
+	if len(result.ExtraFiles) != 0 {
+		t.Errorf("Expected a file found on another root not to be extra, got %v", result.ExtraFiles)
+	}
+
+	if _, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: diskA, ExtraContentPaths: []string{diskB}, Raw: true, Quiet: true}); err == nil {
+		t.Error("Expected an error for raw content with several roots")
+	}
+}
+
+func TestVerifyDataContext_Cancel(t *testing.T) {
+	tempDir := t.TempDir()
+	contentPath := filepath.Join(tempDir, "content.bin")
+	if err := os.WriteFile(contentPath, make([]byte, 200000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pieceLenExp := uint(16)
+	torrentPath := filepath.Join(tempDir, "content.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, PieceLengthExp: &pieceLenExp, Quiet: true}); err != nil {
+		t.Fatalf("Failed to create test torrent file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := VerifyDataContext(ctx, VerifyOptions{TorrentPath: torrentPath, ContentPath: contentPath, Quiet: true})
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyDataContext() error = %v, want ErrCancelled and context.Canceled", err)
+	}
+	if result != nil {
+		t.Errorf("Expected no result after cancelling, got %+v", result)
+	}
+}
+
 func TestVerifyData_CorruptedData(t *testing.T) {
 	numFiles := 3
 	fileSize := int64(1 * 1024 * 1024) // 1 MiB per file
@@ -858,3 +1004,384 @@ func TestVerifyData_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultContentPath(t *testing.T) {
+	tempDir := t.TempDir()
+	pieceLenExp := uint(16)
+
+	contentPath := filepath.Join(tempDir, "Some.Release")
+	if err := os.MkdirAll(contentPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(contentPath, "a.bin"), make([]byte, 100_000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	singlePath := filepath.Join(tempDir, "single.mkv")
+	if err := os.WriteFile(singlePath, make([]byte, 10_000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	torrentDir := filepath.Join(tempDir, "torrents")
+	if err := os.MkdirAll(torrentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	create := func(path, name string) string {
+		out := filepath.Join(torrentDir, name)
+		if _, err := Create(CreateOptions{Path: path, OutputPath: out, PieceLengthExp: &pieceLenExp, NoDate: true}); err != nil {
+			t.Fatalf("failed to create torrent: %v", err)
+		}
+		return out
+	}
+	multiTorrent := create(contentPath, "multi.torrent")
+	singleTorrent := create(singlePath, "single.torrent")
+
+	tests := []struct {
+		name        string
+		torrentPath string
+		downloadDir string
+		want        string
+	}{
+		{"multi file next to torrent", multiTorrent, "", filepath.Join(torrentDir, "Some.Release")},
+		{"multi file in download dir", multiTorrent, tempDir, contentPath},
+		{"single file in download dir", singleTorrent, tempDir, singlePath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultContentPath(tt.torrentPath, tt.downloadDir, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := DefaultContentPath("https://example.com/x.torrent", "", 0); err == nil {
+		t.Error("expected an error for a remote torrent without a download dir")
+	}
+}
+
+// TestVerifyData_PreloadedMetaInfo checks that a torrent loaded by the caller is not
+// fetched again: the remote TorrentPath only names it.
+func TestVerifyData_PreloadedMetaInfo(t *testing.T) {
+	tempDir := t.TempDir()
+	pieceLenExp := uint(16)
+	contentPath := filepath.Join(tempDir, "Some.Release.mkv")
+	if err := os.WriteFile(contentPath, make([]byte, 100_000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torrentPath := filepath.Join(tempDir, "release.torrent")
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, PieceLengthExp: &pieceLenExp, NoDate: true}); err != nil {
+		t.Fatalf("failed to create torrent: %v", err)
+	}
+	loaded, err := LoadFromFile(torrentPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remotePath := "http://127.0.0.1:0/release.torrent"
+	got, err := DefaultContentPathFor(loaded, remotePath, tempDir)
+	if err != nil {
+		t.Fatalf("DefaultContentPathFor failed: %v", err)
+	}
+	if got != contentPath {
+		t.Errorf("DefaultContentPathFor = %q, want %q", got, contentPath)
+	}
+
+	result, err := VerifyData(VerifyOptions{
+		TorrentPath: remotePath,
+		ContentPath: got,
+		Quiet:       true,
+		MetaInfo:    loaded.MetaInfo,
+	})
+	if err != nil {
+		t.Fatalf("VerifyData fetched the torrent again: %v", err)
+	}
+	if result.BadPieces != 0 || result.GoodPieces != result.TotalPieces {
+		t.Errorf("expected all pieces good, got %+v", result)
+	}
+}
+
+func TestVerifyData_Raw(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "content")
+	if err := os.MkdirAll(filepath.Join(contentDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// odd sizes so pieces span file boundaries
+	names := []string{"a.bin", "b.bin", "sub/c.bin"}
+	sizes := []int{100000, 12345, 150001}
+	var raw []byte
+	for i, name := range names {
+		data := make([]byte, sizes[i])
+		for j := range data {
+			data[j] = byte((i*31 + j) % 251)
+		}
+		if err := os.WriteFile(filepath.Join(contentDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		raw = append(raw, data...)
+	}
+
+	torrentPath := filepath.Join(tmpDir, "content.torrent")
+	pieceLength := uint(16)
+	if _, err := Create(CreateOptions{Path: contentDir, OutputPath: torrentPath, PieceLengthExp: &pieceLength, Quiet: true}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	numPieces := (len(raw) + 1<<16 - 1) >> 16
+
+	rawPath := filepath.Join(tmpDir, "content.raw")
+	if err := os.WriteFile(rawPath, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: rawPath, Raw: true, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData(raw) failed: %v", err)
+	}
+	if result.Completion != 100 || result.GoodPieces != numPieces || len(result.MissingFiles) != 0 {
+		t.Errorf("raw verification = %+v, want all %d pieces good", result, numPieces)
+	}
+
+	// a short stream reports the pieces past its end as missing
+	if err := os.WriteFile(rawPath, raw[:len(raw)-50000], 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: rawPath, Raw: true, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData(raw, short) failed: %v", err)
+	}
+	if result.MissingPieces == 0 || result.BadPieces != 0 || len(result.MissingFiles) != 1 {
+		t.Errorf("short raw verification = %+v, want missing pieces and one missing entry", result)
+	}
+	if result.GoodPieces+result.MissingPieces != numPieces {
+		t.Errorf("good %d + missing %d pieces, want %d", result.GoodPieces, result.MissingPieces, numPieces)
+	}
+
+	if _, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Raw: true, Quiet: true}); err == nil {
+		t.Error("VerifyData(raw) on a directory should fail")
+	}
+}
+
+func TestVerifyData_BadPieceCallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentPath := filepath.Join(tmpDir, "content.bin")
+	data := make([]byte, 5<<16)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	if err := os.WriteFile(contentPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	torrentPath := filepath.Join(tmpDir, "content.torrent")
+	pieceLength := uint(16)
+	if _, err := Create(CreateOptions{Path: contentPath, OutputPath: torrentPath, PieceLengthExp: &pieceLength, Quiet: true}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	// corrupt pieces 1 and 3
+	data[1<<16] ^= 0xff
+	data[3<<16+100] ^= 0xff
+	if err := os.WriteFile(contentPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var reported []int
+	result, err := VerifyData(VerifyOptions{
+		TorrentPath: torrentPath,
+		ContentPath: contentPath,
+		Quiet:       true,
+		Workers:     2,
+		BadPieceCallback: func(pieceIndex int) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, pieceIndex)
+		},
+	})
+	if err != nil {
+		t.Fatalf("VerifyData() failed: %v", err)
+	}
+
+	sort.Ints(reported)
+	if !slices.Equal(reported, []int{1, 3}) {
+		t.Errorf("bad piece callback reported %v, want [1 3]", reported)
+	}
+	if result.BadPieces != len(reported) {
+		t.Errorf("result has %d bad pieces, callback reported %d", result.BadPieces, len(reported))
+	}
+}
+
+func TestVerifyData_Locate(t *testing.T) {
+	tmpDir := t.TempDir()
+	contentDir := filepath.Join(tmpDir, "content")
+	if err := os.MkdirAll(contentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 100000)
+	for i := range data {
+		data[i] = byte(1 + i%251)
+	}
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := os.WriteFile(filepath.Join(contentDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	torrentPath := filepath.Join(tmpDir, "content.torrent")
+	pieceLength := uint(16)
+	if _, err := Create(CreateOptions{Path: contentDir, OutputPath: torrentPath, PieceLengthExp: &pieceLength, Quiet: true}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	// piece 1 spans the end of a.bin and the start of b.bin, zero out that start;
+	// piece 2 lies inside b.bin and is corrupted too but is not the first
+	corrupt := slices.Clone(data)
+	clear(corrupt[:2<<16-100000])
+	corrupt[80000] ^= 0xff
+	if err := os.WriteFile(filepath.Join(contentDir, "b.bin"), corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Quiet: true})
+	if err != nil {
+		t.Fatalf("VerifyData() failed: %v", err)
+	}
+	if result.FirstBadPiece != nil {
+		t.Errorf("FirstBadPiece = %+v without Locate, want nil", result.FirstBadPiece)
+	}
+
+	result, err = VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Quiet: true, Locate: true})
+	if err != nil {
+		t.Fatalf("VerifyData() failed: %v", err)
+	}
+	loc := result.FirstBadPiece
+	if loc == nil {
+		t.Fatal("FirstBadPiece = nil with Locate")
+	}
+	if loc.Piece != 1 || loc.Offset != 1<<16 || loc.Length != 1<<16 {
+		t.Errorf("FirstBadPiece = piece %d at %d+%d, want piece 1 at %d+%d", loc.Piece, loc.Offset, loc.Length, 1<<16, 1<<16)
+	}
+	want := []PieceSpan{
+		{Path: filepath.Join(contentDir, "a.bin"), Start: 1 << 16, End: 100000},
+		{Path: filepath.Join(contentDir, "b.bin"), Start: 0, End: 2<<16 - 100000, Zeroed: true},
+	}
+	if !slices.Equal(loc.Spans, want) {
+		t.Errorf("FirstBadPiece spans = %+v, want %+v", loc.Spans, want)
+	}
+
+	result, err = VerifyData(VerifyOptions{TorrentPath: torrentPath, ContentPath: contentDir, Quiet: true, Locate: true, RelativeTo: tmpDir})
+	if err != nil {
+		t.Fatalf("VerifyData() failed: %v", err)
+	}
+	for i, span := range result.FirstBadPiece.Spans {
+		if wantPath := filepath.Join("content", filepath.Base(want[i].Path)); span.Path != wantPath {
+			t.Errorf("span %d path with RelativeTo = %q, want %q", i, span.Path, wantPath)
+		}
+	}
+}
+
+func TestLocatePiece_ReadError(t *testing.T) {
+	tmpDir := t.TempDir()
+	present := filepath.Join(tmpDir, "a.bin")
+	if err := os.WriteFile(present, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []fileEntry{
+		{path: filepath.Join(tmpDir, "gone.bin"), length: 100},
+		{path: present, length: 100, offset: 100},
+	}
+
+	loc := locatePiece(files, 0, 200, 200)
+	if len(loc.Spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(loc.Spans))
+	}
+	if loc.Spans[0].ReadError == "" {
+		t.Error("expected the unreadable file's span to carry its read error")
+	}
+	if loc.Spans[1].ReadError != "" || !loc.Spans[1].Zeroed {
+		t.Errorf("expected the readable span to still be checked, got %+v", loc.Spans[1])
+	}
+}
+
+func TestRelativizeResult_ContentRoots(t *testing.T) {
+	base := t.TempDir()
+	primary := filepath.Join(base, "downloads")
+	extra := filepath.Join(base, "downloads", "extra")
+	result := &VerificationResult{
+		ReadErrors: []string{
+			"error reading " + filepath.Join(primary, "a.bin") + ": input/output error",
+			"error reading " + filepath.Join(extra, "b.bin") + ": input/output error",
+		},
+		FirstBadPiece: &BadPieceLocation{Spans: []PieceSpan{
+			{Path: filepath.Join(extra, "b.bin"), ReadError: "error opening " + filepath.Join(extra, "b.bin")},
+		}},
+	}
+
+	relativizeResult(result, base, []string{primary, extra})
+
+	want := []string{
+		"error reading " + filepath.Join("downloads", "a.bin") + ": input/output error",
+		"error reading " + filepath.Join("downloads", "extra", "b.bin") + ": input/output error",
+	}
+	if !slices.Equal(result.ReadErrors, want) {
+		t.Errorf("ReadErrors = %q, want %q", result.ReadErrors, want)
+	}
+	span := result.FirstBadPiece.Spans[0]
+	if wantPath := filepath.Join("downloads", "extra", "b.bin"); span.Path != wantPath || span.ReadError != "error opening "+wantPath {
+		t.Errorf("span = %+v, want paths relative to %s", span, base)
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	base := t.TempDir()
+	tests := []struct {
+		base, path, want string
+	}{
+		{"", filepath.Join(base, "a", "b.mkv"), filepath.Join(base, "a", "b.mkv")},
+		{base, filepath.Join(base, "a", "b.mkv"), filepath.Join("a", "b.mkv")},
+		{filepath.Join(base, "a"), filepath.Join(base, "c"), filepath.Join("..", "c")},
+		{base, base, "."},
+	}
+	for _, tt := range tests {
+		if got := RelativePath(tt.base, tt.path); got != tt.want {
+			t.Errorf("RelativePath(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilesWithBadPieces(t *testing.T) {
+	info := &metainfo.Info{
+		Name:        "Show",
+		PieceLength: 100,
+		Files: []metainfo.FileInfo{
+			{Path: []string{"a.mkv"}, Length: 150},        // pieces 0-1
+			paddingFileInfo(50),                           // piece 1
+			{Path: []string{"empty.txt"}, Length: 0},      // nothing to read
+			{Path: []string{"sub", "b.mkv"}, Length: 200}, // pieces 2-3
+			{Path: []string{"sub", "c.nfo"}, Length: 10},  // piece 4
+		},
+	}
+
+	tests := []struct {
+		bad  []int
+		want []string
+	}{
+		{nil, nil},
+		{[]int{1}, []string{"a.mkv"}},
+		{[]int{4, 0, 3}, []string{"a.mkv", "sub/b.mkv", "sub/c.nfo"}},
+		{[]int{2, 3}, []string{"sub/b.mkv"}},
+	}
+	for _, tt := range tests {
+		if got := filesWithBadPieces(info, tt.bad); !slices.Equal(got, tt.want) {
+			t.Errorf("filesWithBadPieces(%v) = %v, want %v", tt.bad, got, tt.want)
+		}
+	}
+
+	single := &metainfo.Info{Name: "movie.mkv", PieceLength: 100, Length: 250}
+	if got := filesWithBadPieces(single, []int{2}); !slices.Equal(got, []string{"movie.mkv"}) {
+		t.Errorf("filesWithBadPieces() for a single file = %v, want [movie.mkv]", got)
+	}
+}